@@ -1,14 +1,17 @@
 package root
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"os"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/docker/docker-agent/pkg/audit"
 	"github.com/docker/docker-agent/pkg/cli"
 	"github.com/docker/docker-agent/pkg/config"
 	"github.com/docker/docker-agent/pkg/server"
@@ -22,9 +25,16 @@ type apiFlags struct {
 	pullIntervalMins int
 	fakeResponses    string
 	recordPath       string
+	authTokensFile   string
+	authJWKSFile     string
+	authIssuer       string
+	authAudience     string
+	auditLogPath     string
 	runConfig        config.RuntimeConfig
 }
 
+const envAPIToken = "DOCKER_AGENT_API_TOKEN"
+
 func newAPICmd() *cobra.Command {
 	var flags apiFlags
 
@@ -36,10 +46,15 @@ func newAPICmd() *cobra.Command {
 	}
 
 	cmd.PersistentFlags().StringVarP(&flags.listenAddr, "listen", "l", "127.0.0.1:8080", "Address to listen on")
-	cmd.PersistentFlags().StringVarP(&flags.sessionDB, "session-db", "s", "session.db", "Path to the session database")
+	cmd.PersistentFlags().StringVarP(&flags.sessionDB, "session-db", "s", "session.db", "Path to the SQLite session database, or a postgres:// connection URL")
 	cmd.PersistentFlags().IntVar(&flags.pullIntervalMins, "pull-interval", 0, "Auto-pull OCI reference every N minutes (0 = disabled)")
 	cmd.PersistentFlags().StringVar(&flags.fakeResponses, "fake", "", "Replay AI responses from cassette file (for testing)")
 	cmd.PersistentFlags().StringVar(&flags.recordPath, "record", "", "Record AI API interactions to cassette file")
+	cmd.PersistentFlags().StringVar(&flags.authTokensFile, "auth-tokens-file", "", "Require bearer tokens listed in this YAML file (see also "+envAPIToken+")")
+	cmd.PersistentFlags().StringVar(&flags.authJWKSFile, "auth-jwks-file", "", "Accept JWT bearer tokens signed by a key from this JWKS file")
+	cmd.PersistentFlags().StringVar(&flags.authIssuer, "auth-issuer", "", "Required issuer (iss) of JWT bearer tokens")
+	cmd.PersistentFlags().StringVar(&flags.authAudience, "auth-audience", "", "Required audience (aud) of JWT bearer tokens")
+	cmd.PersistentFlags().StringVar(&flags.auditLogPath, "audit-log", "", "Append a record of every tool call decision to this JSONL file")
 	cmd.MarkFlagsMutuallyExclusive("fake", "record")
 	addRuntimeConfigFlags(cmd, &flags.runConfig)
 
@@ -93,13 +108,7 @@ func (f *apiFlags) runAPICommand(cmd *cobra.Command, args []string) error {
 
 	slog.Debug("Starting server", "agents", agentsPath, "addr", ln.Addr().String())
 
-	// Expand tilde in session database path
-	sessionDB, err := expandTilde(f.sessionDB)
-	if err != nil {
-		return err
-	}
-
-	sessionStore, err := session.NewSQLiteSessionStore(sessionDB)
+	sessionStore, err := f.sessionStore(ctx)
 	if err != nil {
 		return fmt.Errorf("creating session store: %w", err)
 	}
@@ -114,10 +123,103 @@ func (f *apiFlags) runAPICommand(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("resolving agent sources: %w", err)
 	}
 
-	s, err := server.New(ctx, sessionStore, &f.runConfig, time.Duration(f.pullIntervalMins)*time.Minute, sources)
+	checkpoints, err := newCheckpointStore(f.sessionDB)
+	if err != nil {
+		return err
+	}
+	serverOpts := []server.Opt{server.WithCheckpoints(checkpoints)}
+	if f.auditLogPath != "" {
+		auditLog, err := audit.Open(f.auditLogPath)
+		if err != nil {
+			return err
+		}
+		defer auditLog.Close()
+		serverOpts = append(serverOpts, server.WithAuditLog(auditLog))
+	}
+	auth, err := f.authenticator()
+	if err != nil {
+		return err
+	}
+	if auth != nil {
+		serverOpts = append(serverOpts, server.WithAuthenticator(auth))
+	} else if !isLoopback(ln.Addr()) {
+		slog.Warn("API server is listening on a non-loopback address without authentication", "addr", ln.Addr().String())
+	}
+
+	s, err := server.New(ctx, sessionStore, &f.runConfig, time.Duration(f.pullIntervalMins)*time.Minute, sources, serverOpts...)
 	if err != nil {
 		return fmt.Errorf("creating server: %w", err)
 	}
 
 	return s.Serve(ctx, ln)
 }
+
+// authenticator builds the API authenticator from the auth flags and the
+// DOCKER_AGENT_API_TOKEN environment variable. It returns nil when no
+// authentication is configured.
+func (f *apiFlags) authenticator() (server.Authenticator, error) {
+	var auths server.Authenticators
+
+	if token := os.Getenv(envAPIToken); token != "" {
+		tokens, err := server.NewStaticTokens(server.StaticToken{Name: envAPIToken, Token: token, Scopes: []server.Scope{server.ScopeAll}})
+		if err != nil {
+			return nil, err
+		}
+		auths = append(auths, tokens)
+	}
+
+	if f.authTokensFile != "" {
+		path, err := expandTilde(f.authTokensFile)
+		if err != nil {
+			return nil, err
+		}
+		tokens, err := server.LoadStaticTokens(path)
+		if err != nil {
+			return nil, err
+		}
+		auths = append(auths, tokens)
+	}
+
+	if f.authJWKSFile != "" {
+		path, err := expandTilde(f.authJWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		verifier, err := server.NewJWTVerifier(server.JWTConfig{
+			JWKSFile: path,
+			Issuer:   f.authIssuer,
+			Audience: f.authAudience,
+		})
+		if err != nil {
+			return nil, err
+		}
+		auths = append(auths, verifier)
+	} else if f.authIssuer != "" || f.authAudience != "" {
+		return nil, errors.New("--auth-issuer and --auth-audience require --auth-jwks-file")
+	}
+
+	if len(auths) == 0 {
+		return nil, nil
+	}
+	return auths, nil
+}
+
+func isLoopback(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	return !ok || tcpAddr.IP.IsLoopback()
+}
+
+// sessionStore opens the Postgres database when --session-db is a
+// connection URL, and the SQLite database at that path otherwise.
+func (f *apiFlags) sessionStore(ctx context.Context) (session.Store, error) {
+	if session.IsPostgresDSN(f.sessionDB) {
+		return session.NewPostgresSessionStore(ctx, f.sessionDB)
+	}
+
+	// Expand tilde in session database path
+	sessionDB, err := expandTilde(f.sessionDB)
+	if err != nil {
+		return nil, err
+	}
+	return session.NewSQLiteSessionStore(sessionDB)
+}
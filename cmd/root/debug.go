@@ -43,6 +43,15 @@ func newDebugCmd() *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE:  flags.runDebugToolsetsCommand,
 	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "permissions <agent-file>|<registry-ref> <tool> [<json-args>]",
+		Short: "Dry-run the permission decision for a tool call",
+		Long:  "Evaluate the permission rules of an agent's configuration against a tool call and explain which rule decides.",
+		Example: `  docker-agent debug permissions agent.yaml shell '{"cmd":"rm -rf build"}'
+  docker-agent debug permissions agent.yaml edit_file '{"path":"src/main.go"}'`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: flags.runDebugPermissionsCommand,
+	})
 	titleCmd := &cobra.Command{
 		Use:   "title <agent-file>|<registry-ref> <question>",
 		Short: "Generate a session title from a question",
@@ -55,6 +64,7 @@ func newDebugCmd() *cobra.Command {
 	addRuntimeConfigFlags(cmd, &flags.runConfig)
 
 	cmd.AddCommand(newDebugAuthCmd())
+	cmd.AddCommand(newDebugAuditCmd())
 
 	return cmd
 }
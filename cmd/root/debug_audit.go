@@ -0,0 +1,99 @@
+package root
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/docker-agent/pkg/audit"
+	"github.com/docker/docker-agent/pkg/telemetry"
+)
+
+func newDebugAuditCmd() *cobra.Command {
+	var (
+		filter     audit.Filter
+		decision   string
+		since      time.Duration
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "audit <audit-log>",
+		Short: "Query an audit log of tool call decisions",
+		Long:  "Print the records of an audit log written with --audit-log, oldest first.",
+		Example: `  docker-agent debug audit audit.jsonl
+  docker-agent debug audit audit.jsonl --session <session-id> --decision deny
+  docker-agent debug audit audit.jsonl --tool shell --since 24h --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			telemetry.TrackCommand("debug", []string{"audit"})
+
+			switch audit.Decision(decision) {
+			case "", audit.DecisionAllow, audit.DecisionDeny, audit.DecisionCanceled:
+				filter.Decision = audit.Decision(decision)
+			default:
+				return fmt.Errorf("invalid decision %q: must be one of allow, deny, canceled", decision)
+			}
+			if since > 0 {
+				filter.Since = time.Now().Add(-since)
+			}
+
+			records, err := audit.ReadFile(args[0], filter)
+			if err != nil {
+				return err
+			}
+
+			w := cmd.OutOrStdout()
+			if jsonOutput {
+				enc := json.NewEncoder(w)
+				for _, rec := range records {
+					if err := enc.Encode(rec); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+
+			if len(records) == 0 {
+				fmt.Fprintln(w, "No audit records found.")
+				return nil
+			}
+			for _, rec := range records {
+				printAuditRecord(w, rec)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&filter.SessionID, "session", "", "Only show the records of this session")
+	cmd.Flags().StringVar(&filter.Tool, "tool", "", "Only show the records of this tool")
+	cmd.Flags().StringVar(&decision, "decision", "", "Only show the records with this decision (allow, deny or canceled)")
+	cmd.Flags().DurationVar(&since, "since", 0, "Only show the records of the last duration (e.g. 24h)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON Lines format")
+
+	return cmd
+}
+
+// printAuditRecord prints a record on a single line, e.g.
+// "2026-01-02 15:04:05  <session>  root  shell  allow by permissions (shell:cmd=ls*), success in 12ms".
+func printAuditRecord(w io.Writer, rec audit.Record) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s  %s  %s  %s  %s by %s", rec.Time.Local().Format(time.DateTime), rec.SessionID, rec.Agent, rec.Tool, rec.Decision, rec.DecidedBy)
+	if rec.DecidedBy == audit.SourceUser && rec.Owner != "" {
+		fmt.Fprintf(&b, " %s", rec.Owner)
+	}
+	if rec.Pattern != "" {
+		fmt.Fprintf(&b, " (%s)", rec.Pattern)
+	}
+	if rec.Reason != "" {
+		fmt.Fprintf(&b, ": %s", rec.Reason)
+	}
+	if rec.Status != "" {
+		fmt.Fprintf(&b, ", %s in %s", rec.Status, time.Duration(rec.DurationMs)*time.Millisecond)
+	}
+	fmt.Fprintln(w, b.String())
+}
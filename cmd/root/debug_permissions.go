@@ -0,0 +1,85 @@
+package root
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/permissions"
+	"github.com/docker/docker-agent/pkg/telemetry"
+)
+
+func (f *debugFlags) runDebugPermissionsCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("debug", []string{"permissions"})
+
+	toolName := args[1]
+	var toolArgs map[string]any
+	if len(args) > 2 {
+		if err := json.Unmarshal([]byte(args[2]), &toolArgs); err != nil {
+			return fmt.Errorf("invalid tool arguments: %w", err)
+		}
+	}
+
+	agentSource, err := config.Resolve(args[0], f.runConfig.EnvProvider())
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(cmd.Context(), agentSource)
+	if err != nil {
+		return err
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	checker := permissions.NewChecker(cfg.Permissions, permissions.WithWorkingDir(cmp.Or(f.runConfig.WorkingDir, wd)))
+	if err := checker.Validate(); err != nil {
+		return err
+	}
+
+	printPermissionsExplanation(cmd.OutOrStdout(), toolName, checker.Explain(toolName, toolArgs))
+	return nil
+}
+
+// printPermissionsExplanation prints every rule, in evaluation order, and
+// the decision they lead to.
+func printPermissionsExplanation(w io.Writer, toolName string, explanation permissions.Explanation) {
+	if len(explanation.Rules) == 0 {
+		fmt.Fprintln(w, "No permission rules configured.")
+	}
+
+	decided := false
+	for _, rule := range explanation.Rules {
+		list := rule.List.String()
+		if rule.List == permissions.ForceAsk {
+			list = "ask"
+		}
+		switch {
+		case rule.Matched && !decided:
+			decided = true
+			fmt.Fprintf(w, "  %-5s %s: matches, decides\n", list, rule.Pattern)
+		case rule.Matched:
+			fmt.Fprintf(w, "  %-5s %s: matches, overridden by an earlier rule\n", list, rule.Pattern)
+		default:
+			fmt.Fprintf(w, "  %-5s %s: %s\n", list, rule.Pattern, rule.Reason)
+		}
+	}
+
+	switch explanation.Decision {
+	case permissions.Deny:
+		fmt.Fprintf(w, "Decision: deny. %s is denied by %q.\n", toolName, explanation.Pattern)
+	case permissions.Allow:
+		fmt.Fprintf(w, "Decision: allow. %s runs without confirmation because of %q.\n", toolName, explanation.Pattern)
+	case permissions.ForceAsk:
+		fmt.Fprintf(w, "Decision: ask. %s always requires confirmation because of %q.\n", toolName, explanation.Pattern)
+	default:
+		fmt.Fprintf(w, "Decision: no rule matches. %s runs without confirmation if it is read-only, otherwise it requires confirmation.\n", toolName)
+	}
+}
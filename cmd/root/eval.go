@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -21,8 +23,15 @@ const defaultJudgeModel = "anthropic/claude-opus-4-5-20251101"
 
 type evalFlags struct {
 	evaluation.Config
-	runConfig config.RuntimeConfig
-	outputDir string
+	runConfig     config.RuntimeConfig
+	outputDir     string
+	fakeResponses string
+	format        string
+
+	baseline         string
+	compareFormat    string
+	compareReport    string
+	compareTolerance float64
 }
 
 func newEvalCmd() *cobra.Command {
@@ -44,6 +53,16 @@ func newEvalCmd() *cobra.Command {
 	cmd.Flags().StringVar(&flags.BaseImage, "base-image", "", "Custom base Docker image for running evaluations")
 	cmd.Flags().BoolVar(&flags.KeepContainers, "keep-containers", false, "Keep containers after evaluation (don't use --rm)")
 	cmd.Flags().StringSliceVarP(&flags.EnvVars, "env", "e", nil, "Environment variables to pass to container (KEY or KEY=VALUE)")
+	cmd.Flags().BoolVar(&flags.Local, "local", false, "Run the agent in process in a temporary directory instead of in a Docker container. Warning: the setup scripts and the agent's tools, shell included, then run on the host without isolation and without asking for confirmation")
+	cmd.Flags().StringVar(&flags.fakeResponses, "fake", "", "Replay AI responses from cassette file (implies --local)")
+	cmd.Flags().StringVar(&flags.format, "format", "", "Write a report of the run to the standard output: "+strings.Join(evaluation.ReportFormats, ", ")+" (progress and summary then go to the standard error)")
+	cmd.Flags().StringVar(&flags.baseline, "baseline", "", "Compare the results to a previous run (--baseline=<run>) and fail on regressions (default: the latest run)")
+	cmd.Flag("baseline").NoOptDefVal = "latest"
+	cmd.Flags().StringVar(&flags.compareFormat, "baseline-format", "markdown", "Format of the baseline comparison report: markdown or junit")
+	cmd.Flags().StringVar(&flags.compareReport, "baseline-report", "", "Write the baseline comparison report to this file instead of the standard output")
+	cmd.Flags().Float64Var(&flags.compareTolerance, "tolerance", 0.2, "Relative increase of cost or output tokens of an eval above which it regresses")
+
+	cmd.AddCommand(newEvalCompareCmd())
 
 	return cmd
 }
@@ -51,6 +70,10 @@ func newEvalCmd() *cobra.Command {
 func (f *evalFlags) runEvalCommand(cmd *cobra.Command, args []string) error {
 	telemetry.TrackCommand("eval", args)
 
+	if f.format != "" && !slices.Contains(evaluation.ReportFormats, f.format) {
+		return fmt.Errorf("unsupported report format %q: expected one of %s", f.format, strings.Join(evaluation.ReportFormats, ", "))
+	}
+
 	ctx := cmd.Context()
 	agentFilename := args[0]
 	evalsDir := "./evals"
@@ -58,6 +81,23 @@ func (f *evalFlags) runEvalCommand(cmd *cobra.Command, args []string) error {
 		evalsDir = args[1]
 	}
 
+	// Replaying responses needs the in-process mode: the fake proxy only
+	// listens on localhost. The judge is skipped since its requests are not
+	// part of the cassette.
+	if f.fakeResponses != "" {
+		f.Local = true
+		f.JudgeModel = ""
+	}
+	fakeCleanup, err := setupFakeProxy(f.fakeResponses, 0, &f.runConfig)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := fakeCleanup(); err != nil {
+			slog.Error("Failed to cleanup fake proxy", "error", err)
+		}
+	}()
+
 	// Output directory defaults to <evals-dir>/results
 	outputDir := f.outputDir
 	if outputDir == "" {
@@ -98,7 +138,11 @@ func (f *evalFlags) runEvalCommand(cmd *cobra.Command, args []string) error {
 	fmt.Fprintf(logFile, "\n")
 
 	// Create tee writer to write to both console and log file
+	// The standard output is reserved for the report, if any.
 	consoleOut := cmd.OutOrStdout()
+	if f.format != "" {
+		consoleOut = cmd.ErrOrStderr()
+	}
 	teeOut := io.MultiWriter(consoleOut, logFile)
 
 	// Check if console is a TTY (for colored output)
@@ -119,12 +163,20 @@ func (f *evalFlags) runEvalCommand(cmd *cobra.Command, args []string) error {
 		return evalErr
 	}
 
+	// Save results to JSON file, for comparisons between runs
+	resultsPath, resultsErr := evaluation.SaveRunJSON(run, outputDir)
+	if resultsErr != nil {
+		slog.Error("Failed to save results JSON", "error", resultsErr)
+	} else {
+		fmt.Fprintf(teeOut, "\nResults JSON: %s\n", resultsPath)
+	}
+
 	// Save sessions to SQLite database
 	dbPath, err := evaluation.SaveRunSessions(ctx, run, outputDir)
 	if err != nil {
 		slog.Error("Failed to save sessions database", "error", err)
 	} else {
-		fmt.Fprintf(teeOut, "\nSessions DB: %s\n", dbPath)
+		fmt.Fprintf(teeOut, "Sessions DB: %s\n", dbPath)
 	}
 
 	// Save sessions to JSON file (same format as /eval produces)
@@ -137,5 +189,39 @@ func (f *evalFlags) runEvalCommand(cmd *cobra.Command, args []string) error {
 
 	fmt.Fprintf(teeOut, "Log: %s\n", logPath)
 
-	return evalErr
+	if f.format != "" {
+		if err := evaluation.WriteReport(cmd.OutOrStdout(), run, f.format); err != nil {
+			return fmt.Errorf("writing report: %w", err)
+		}
+	}
+
+	if evalErr != nil {
+		return evalErr
+	}
+
+	if f.baseline != "" {
+		if resultsErr != nil {
+			return fmt.Errorf("comparing to baseline: %w", resultsErr)
+		}
+		return f.compareToBaseline(teeOut, outputDir, runName, resultsPath)
+	}
+
+	return nil
+}
+
+// compareToBaseline compares the results of a run to the baseline run.
+func (f *evalFlags) compareToBaseline(out io.Writer, outputDir, runName, resultsPath string) error {
+	var basePath string
+	var err error
+	if f.baseline == "latest" {
+		basePath, err = evaluation.LatestRun(outputDir, runName)
+	} else {
+		basePath, err = evaluation.ResolveRun(outputDir, f.baseline)
+	}
+	if err != nil {
+		return fmt.Errorf("finding baseline: %w", err)
+	}
+
+	fmt.Fprintln(out)
+	return compareRuns(out, basePath, resultsPath, f.compareFormat, f.compareReport, f.compareTolerance)
 }
@@ -0,0 +1,101 @@
+package root
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/docker-agent/pkg/evaluation"
+	"github.com/docker/docker-agent/pkg/telemetry"
+)
+
+type evalCompareFlags struct {
+	resultsDir string
+	format     string
+	report     string
+	tolerance  float64
+}
+
+func newEvalCompareCmd() *cobra.Command {
+	var flags evalCompareFlags
+
+	cmd := &cobra.Command{
+		Use:   "compare <run-a> <run-b>",
+		Short: "Compare the results of two evaluation runs",
+		Long:  "Compare the results of two evaluation runs, eval by eval, and fail if the second run regressed. Runs are given by name or by the path of their results file.",
+		Example: `  docker-agent eval compare happy-panda-1234 brave-otter-5678
+  docker-agent eval compare ./evals/results/happy-panda-1234.json ./evals/results/brave-otter-5678.json --format junit --report report.xml`,
+		Args: cobra.ExactArgs(2),
+		RunE: flags.runEvalCompareCommand,
+	}
+
+	cmd.Flags().StringVar(&flags.resultsDir, "results", "./evals/results", "Directory of the results of the runs given by name")
+	cmd.Flags().StringVar(&flags.format, "format", "markdown", "Format of the comparison report: markdown or junit")
+	cmd.Flags().StringVar(&flags.report, "report", "", "Write the comparison report to this file instead of the standard output")
+	cmd.Flags().Float64Var(&flags.tolerance, "tolerance", 0.2, "Relative increase of cost or output tokens of an eval above which it regresses")
+
+	return cmd
+}
+
+func (f *evalCompareFlags) runEvalCompareCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("eval", []string{"compare"})
+
+	basePath, err := evaluation.ResolveRun(f.resultsDir, args[0])
+	if err != nil {
+		return err
+	}
+	headPath, err := evaluation.ResolveRun(f.resultsDir, args[1])
+	if err != nil {
+		return err
+	}
+
+	return compareRuns(cmd.OutOrStdout(), basePath, headPath, f.format, f.report, f.tolerance)
+}
+
+// compareRuns compares two saved runs, writes the report and returns an
+// error if the second run regressed.
+func compareRuns(out io.Writer, basePath, headPath, format, report string, tolerance float64) error {
+	if format != "markdown" && format != "junit" {
+		return fmt.Errorf("unsupported report format %q: expected markdown or junit", format)
+	}
+
+	base, err := evaluation.LoadRunJSON(basePath)
+	if err != nil {
+		return err
+	}
+	head, err := evaluation.LoadRunJSON(headPath)
+	if err != nil {
+		return err
+	}
+
+	comparison := evaluation.Compare(base, head, evaluation.CompareOptions{Tolerance: tolerance})
+
+	w := out
+	if report != "" {
+		file, err := os.Create(report)
+		if err != nil {
+			return fmt.Errorf("creating report: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if format == "junit" {
+		err = comparison.WriteJUnit(w)
+	} else {
+		err = comparison.WriteMarkdown(w)
+	}
+	if err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	if report != "" {
+		fmt.Fprintf(out, "Comparison report: %s\n", report)
+	}
+
+	if regressions := comparison.Regressions(); len(regressions) > 0 {
+		return fmt.Errorf("%d of %d evals regressed between %s and %s", len(regressions), len(comparison.Cases), base.Name, head.Name)
+	}
+	return nil
+}
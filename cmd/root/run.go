@@ -18,6 +18,8 @@ import (
 	"go.opentelemetry.io/otel"
 
 	"github.com/docker/docker-agent/pkg/app"
+	"github.com/docker/docker-agent/pkg/audit"
+	"github.com/docker/docker-agent/pkg/checkpoint"
 	"github.com/docker/docker-agent/pkg/cli"
 	"github.com/docker/docker-agent/pkg/config"
 	"github.com/docker/docker-agent/pkg/paths"
@@ -51,6 +53,8 @@ type runExecFlags struct {
 	forceTUI          bool
 	sandbox           bool
 	sandboxTemplate   string
+	auditLogPath      string
+	auditLog          *audit.Log
 
 	// Exec only
 	exec          bool
@@ -114,6 +118,7 @@ func addRunOrExecFlags(cmd *cobra.Command, flags *runExecFlags) {
 	_ = cmd.PersistentFlags().MarkHidden("force-tui")
 	cmd.PersistentFlags().BoolVar(&flags.sandbox, "sandbox", false, "Run the agent inside a Docker sandbox (requires Docker Desktop with sandbox support)")
 	cmd.PersistentFlags().StringVar(&flags.sandboxTemplate, "template", "", "Template image for the sandbox (passed to docker sandbox create -t)")
+	cmd.PersistentFlags().StringVar(&flags.auditLogPath, "audit-log", "", "Append a record of every tool call decision to this JSONL file")
 	cmd.MarkFlagsMutuallyExclusive("fake", "record")
 
 	// --exec only
@@ -248,6 +253,14 @@ func (f *runExecFlags) runOrExec(ctx context.Context, out *cli.Printer, args []s
 		return err
 	}
 
+	if f.auditLogPath != "" {
+		f.auditLog, err = audit.Open(f.auditLogPath)
+		if err != nil {
+			return err
+		}
+		defer f.auditLog.Close()
+	}
+
 	loadResult, err := f.loadAgentFrom(ctx, agentSource)
 	if err != nil {
 		return err
@@ -355,6 +368,11 @@ func (f *runExecFlags) createLocalRuntimeAndSession(ctx context.Context, loadRes
 		return nil, nil, fmt.Errorf("creating session store: %w", err)
 	}
 
+	checkpoints, err := newCheckpointStore(sessionDB)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Create model switcher config for runtime model switching support
 	modelSwitcherCfg := &runtime.ModelSwitcherConfig{
 		Models:             loadResult.Models,
@@ -364,12 +382,7 @@ func (f *runExecFlags) createLocalRuntimeAndSession(ctx context.Context, loadRes
 		AgentDefaultModels: loadResult.AgentDefaultModels,
 	}
 
-	localRt, err := runtime.New(t,
-		runtime.WithSessionStore(sessStore),
-		runtime.WithCurrentAgent(f.agentName),
-		runtime.WithTracer(otel.Tracer(AppName)),
-		runtime.WithModelSwitcherConfig(modelSwitcherCfg),
-	)
+	localRt, err := runtime.New(t, f.localRuntimeOpts(sessStore, checkpoints, modelSwitcherCfg)...)
 	if err != nil {
 		return nil, nil, fmt.Errorf("creating runtime: %w", err)
 	}
@@ -491,6 +504,22 @@ func (f *runExecFlags) buildAppOpts(args []string) ([]app.Opt, error) {
 	return opts, nil
 }
 
+// localRuntimeOpts returns the options of the local runtimes, for the initial
+// session and spawned sessions alike.
+func (f *runExecFlags) localRuntimeOpts(sessStore session.Store, checkpoints *checkpoint.Store, modelSwitcherCfg *runtime.ModelSwitcherConfig) []runtime.Opt {
+	opts := []runtime.Opt{
+		runtime.WithSessionStore(sessStore),
+		runtime.WithCheckpoints(checkpoints),
+		runtime.WithCurrentAgent(f.agentName),
+		runtime.WithTracer(otel.Tracer(AppName)),
+		runtime.WithModelSwitcherConfig(modelSwitcherCfg),
+	}
+	if f.auditLog != nil {
+		opts = append(opts, runtime.WithAuditLog(f.auditLog))
+	}
+	return opts
+}
+
 // buildSessionOpts returns the canonical set of session options derived from
 // CLI flags and agent configuration. Both the initial session and spawned
 // sessions use this method so their options never drift apart.
@@ -532,13 +561,13 @@ func (f *runExecFlags) createSessionSpawner(agentSource config.Source, sessStore
 			AgentDefaultModels: loadResult.AgentDefaultModels,
 		}
 
+		checkpoints, err := newCheckpointStore(f.sessionDB)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
 		// Create the local runtime
-		localRt, err := runtime.New(team,
-			runtime.WithSessionStore(sessStore),
-			runtime.WithCurrentAgent(f.agentName),
-			runtime.WithTracer(otel.Tracer(AppName)),
-			runtime.WithModelSwitcherConfig(modelSwitcherCfg),
-		)
+		localRt, err := runtime.New(team, f.localRuntimeOpts(sessStore, checkpoints, modelSwitcherCfg)...)
 		if err != nil {
 			return nil, nil, nil, err
 		}
@@ -597,3 +626,18 @@ func applyTheme() {
 	styles.ApplyTheme(theme)
 	slog.Debug("Applied theme", "theme_ref", themeRef, "theme_name", theme.Name)
 }
+
+// newCheckpointStore returns the store for the files snapshotted before tools
+// change them. Checkpoints are kept next to the SQLite session database, or in
+// the data directory when sessions are stored in Postgres.
+func newCheckpointStore(sessionDB string) (*checkpoint.Store, error) {
+	if session.IsPostgresDSN(sessionDB) {
+		return checkpoint.NewStore(filepath.Join(paths.GetDataDir(), "checkpoints")), nil
+	}
+
+	sessionDB, err := expandTilde(sessionDB)
+	if err != nil {
+		return nil, err
+	}
+	return checkpoint.NewStore(filepath.Join(filepath.Dir(sessionDB), "checkpoints")), nil
+}
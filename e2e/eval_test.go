@@ -0,0 +1,38 @@
+package e2e_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestEval_LocalFake runs an eval in process, replaying the model responses
+// of a cassette, and checks its assertions against the response and the
+// files left in the working directory by the setup script.
+func TestEval_LocalFake(t *testing.T) {
+	evalsDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(evalsDir, "sum.json"), []byte(`{
+  "id": "sum",
+  "title": "Sum",
+  "messages": [
+    {"message": {"agentName": "", "message": {"role": "user", "content": "What's 2+2?"}}}
+  ],
+  "evals": {
+    "setup": "echo hello > note.txt",
+    "contains": ["4"],
+    "files": [{"path": "note.txt", "content": "hello\n"}],
+    "max_output_tokens": 100
+  }
+}`), 0o644)
+	require.NoError(t, err)
+
+	out := runCLI(t, "eval", "testdata/basic.yaml", evalsDir,
+		"--fake", filepath.Join("testdata", "cassettes", t.Name()+".yaml"),
+		"--output", t.TempDir(),
+		"--format", "tap")
+
+	require.Contains(t, out, "1..1\nok 1 - Sum\n")
+	require.Contains(t, out, "output_tokens: 8\n")
+}
@@ -46,6 +46,8 @@ type Session struct {
 	rt         runtime.Runtime
 	cancel     context.CancelFunc
 	workingDir string
+	mode       string // current session mode: an agent name or a permission profile
+	profile    string // current permission profile
 }
 
 // NewAgent creates a new ACP agent
@@ -96,7 +98,7 @@ func (a *Agent) Initialize(ctx context.Context, params acp.InitializeRequest) (a
 			Title:   &agentTitle,
 		},
 		AgentCapabilities: acp.AgentCapabilities{
-			LoadSession: false,
+			LoadSession: true,
 			PromptCapabilities: acp.PromptCapabilities{
 				EmbeddedContext: true,
 				Image:           false, // Not yet supported
@@ -119,29 +121,14 @@ func (a *Agent) NewSession(ctx context.Context, params acp.NewSessionRequest) (a
 		slog.Warn("MCP servers provided by client are not yet supported", "count", len(params.McpServers))
 	}
 
-	// Validate and normalize working directory
-	var workingDir string
-	if wd := strings.TrimSpace(params.Cwd); wd != "" {
-		absWd, err := filepath.Abs(wd)
-		if err != nil {
-			return acp.NewSessionResponse{}, fmt.Errorf("invalid working directory: %w", err)
-		}
-		info, err := os.Stat(absWd)
-		if err != nil {
-			return acp.NewSessionResponse{}, fmt.Errorf("working directory does not exist: %w", err)
-		}
-		if !info.IsDir() {
-			return acp.NewSessionResponse{}, errors.New("working directory must be a directory")
-		}
-		workingDir = absWd
+	workingDir, err := resolveWorkingDir(params.Cwd)
+	if err != nil {
+		return acp.NewSessionResponse{}, err
 	}
 
-	rt, err := runtime.New(a.team,
-		runtime.WithCurrentAgent("root"),
-		runtime.WithSessionStore(a.sessionStore),
-	)
+	rt, err := a.newRuntime()
 	if err != nil {
-		return acp.NewSessionResponse{}, fmt.Errorf("failed to create runtime: %w", err)
+		return acp.NewSessionResponse{}, err
 	}
 
 	// Get root agent config for session settings
@@ -170,16 +157,57 @@ func (a *Agent) NewSession(ctx context.Context, params acp.NewSessionRequest) (a
 
 	slog.Debug("ACP session created", "session_id", sess.ID)
 
-	a.mu.Lock()
-	a.sessions[sess.ID] = &Session{
+	acpSess := &Session{
 		id:         sess.ID,
 		sess:       sess,
 		rt:         rt,
 		workingDir: workingDir,
+		mode:       rt.CurrentAgentName(),
+		profile:    modeAsk,
 	}
+
+	a.mu.Lock()
+	a.sessions[sess.ID] = acpSess
 	a.mu.Unlock()
 
-	return acp.NewSessionResponse{SessionId: acp.SessionId(sess.ID)}, nil
+	return acp.NewSessionResponse{
+		SessionId: acp.SessionId(sess.ID),
+		Modes:     a.sessionModes(acpSess),
+	}, nil
+}
+
+// resolveWorkingDir validates and normalizes the working directory sent by the client.
+// An empty cwd is returned as is.
+func resolveWorkingDir(cwd string) (string, error) {
+	wd := strings.TrimSpace(cwd)
+	if wd == "" {
+		return "", nil
+	}
+
+	absWd, err := filepath.Abs(wd)
+	if err != nil {
+		return "", fmt.Errorf("invalid working directory: %w", err)
+	}
+	info, err := os.Stat(absWd)
+	if err != nil {
+		return "", fmt.Errorf("working directory does not exist: %w", err)
+	}
+	if !info.IsDir() {
+		return "", errors.New("working directory must be a directory")
+	}
+	return absWd, nil
+}
+
+// newRuntime creates a runtime for a session, starting with the root agent
+func (a *Agent) newRuntime() (runtime.Runtime, error) {
+	rt, err := runtime.New(a.team,
+		runtime.WithCurrentAgent("root"),
+		runtime.WithSessionStore(a.sessionStore),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create runtime: %w", err)
+	}
+	return rt, nil
 }
 
 // Authenticate implements [acp.Agent]
@@ -188,10 +216,81 @@ func (a *Agent) Authenticate(context.Context, acp.AuthenticateRequest) (acp.Auth
 	return acp.AuthenticateResponse{}, nil
 }
 
-// LoadSession implements [acp.Agent] (optional, not supported)
-func (a *Agent) LoadSession(context.Context, acp.LoadSessionRequest) (acp.LoadSessionResponse, error) {
-	slog.Debug("ACP LoadSession called (not supported)")
-	return acp.LoadSessionResponse{}, errors.New("load session not supported")
+// LoadSession implements [acp.Agent]
+//
+// The session is read from the session store, its history is replayed to the
+// client as session updates and it's attached to a new runtime so that the
+// next prompt continues the conversation.
+func (a *Agent) LoadSession(ctx context.Context, params acp.LoadSessionRequest) (acp.LoadSessionResponse, error) {
+	sid := string(params.SessionId)
+	slog.Debug("ACP LoadSession called", "session_id", sid, "cwd", params.Cwd)
+
+	if len(params.McpServers) > 0 {
+		slog.Warn("MCP servers provided by client are not yet supported", "count", len(params.McpServers))
+	}
+
+	sess, err := a.sessionStore.GetSession(ctx, sid)
+	if err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			return acp.LoadSessionResponse{}, fmt.Errorf("session %s not found", sid)
+		}
+		return acp.LoadSessionResponse{}, fmt.Errorf("failed to load session: %w", err)
+	}
+
+	// The client may have moved the project since the session was created.
+	workingDir, err := resolveWorkingDir(params.Cwd)
+	if err != nil {
+		return acp.LoadSessionResponse{}, err
+	}
+	if workingDir != "" {
+		sess.WorkingDir = workingDir
+	}
+
+	rt, err := a.newRuntime()
+	if err != nil {
+		return acp.LoadSessionResponse{}, err
+	}
+
+	// Apply any stored model overrides from the session
+	if modelSwitcher, ok := rt.(runtime.ModelSwitcher); ok {
+		for agentName, modelRef := range sess.AgentModelOverrides {
+			if err := modelSwitcher.SetAgentModel(ctx, agentName, modelRef); err != nil {
+				slog.Warn("Failed to apply stored model override", "agent", agentName, "model", modelRef, "error", err)
+			}
+		}
+	}
+
+	for _, update := range buildReplayUpdates(sess) {
+		if err := a.conn.SessionUpdate(ctx, acp.SessionNotification{
+			SessionId: params.SessionId,
+			Update:    update,
+		}); err != nil {
+			return acp.LoadSessionResponse{}, fmt.Errorf("failed to replay session: %w", err)
+		}
+	}
+
+	acpSess := &Session{
+		id:         sid,
+		sess:       sess,
+		rt:         rt,
+		workingDir: sess.WorkingDir,
+		mode:       rt.CurrentAgentName(),
+		profile:    modeAsk,
+	}
+	if sess.ToolsApproved {
+		acpSess.profile = modeYolo
+	}
+
+	a.mu.Lock()
+	if prev, ok := a.sessions[sid]; ok && prev.cancel != nil {
+		prev.cancel()
+	}
+	a.sessions[sid] = acpSess
+	a.mu.Unlock()
+
+	slog.Debug("ACP session loaded", "session_id", sid, "items", len(sess.Messages))
+
+	return acp.LoadSessionResponse{Modes: a.sessionModes(acpSess)}, nil
 }
 
 // Cancel implements [acp.Agent]
@@ -361,9 +460,24 @@ func stringOrDefault(s *string, def string) string {
 	return *s
 }
 
-// SetSessionMode implements acp.Agent (optional)
-func (a *Agent) SetSessionMode(context.Context, acp.SetSessionModeRequest) (acp.SetSessionModeResponse, error) {
-	// We don't implement session modes, agents have only one mode (for now? ;) ).
+// SetSessionMode implements [acp.Agent]
+//
+// Modes either switch the current agent or the permission profile of the session.
+func (a *Agent) SetSessionMode(ctx context.Context, params acp.SetSessionModeRequest) (acp.SetSessionModeResponse, error) {
+	sid := string(params.SessionId)
+	slog.Debug("ACP SetSessionMode called", "session_id", sid, "mode", params.ModeId)
+
+	a.mu.Lock()
+	acpSess, ok := a.sessions[sid]
+	a.mu.Unlock()
+
+	if !ok {
+		return acp.SetSessionModeResponse{}, fmt.Errorf("session %s not found", sid)
+	}
+	if err := a.setMode(ctx, acpSess, string(params.ModeId)); err != nil {
+		return acp.SetSessionModeResponse{}, err
+	}
+
 	return acp.SetSessionModeResponse{}, nil
 }
 
@@ -417,6 +531,14 @@ func (a *Agent) runAgent(ctx context.Context, acpSess *Session) error {
 				return err
 			}
 
+		case *runtime.ToolCallProgressEvent:
+			if err := a.conn.SessionUpdate(ctx, acp.SessionNotification{
+				SessionId: acp.SessionId(acpSess.id),
+				Update:    buildToolCallProgress(e),
+			}); err != nil {
+				return err
+			}
+
 		case *runtime.ToolCallResponseEvent:
 			if err := a.conn.SessionUpdate(ctx, acp.SessionNotification{
 				SessionId: acp.SessionId(acpSess.id),
@@ -686,6 +808,16 @@ func buildToolCallComplete(toolCall tools.ToolCall, output string) acp.SessionUp
 	)
 }
 
+// buildToolCallProgress builds a tool call update showing the progress
+// reported by a running tool.
+func buildToolCallProgress(e *runtime.ToolCallProgressEvent) acp.SessionUpdate {
+	return acp.UpdateToolCall(
+		acp.ToolCallId(e.ToolCallID),
+		acp.WithUpdateStatus(acp.ToolCallStatusInProgress),
+		acp.WithUpdateContent([]acp.ToolCallContent{acp.ToolContent(acp.TextBlock(e.Text()))}),
+	)
+}
+
 // isFileEditTool returns true if the tool is a file editing operation
 func isFileEditTool(toolName string) bool {
 	return slices.Contains([]string{"edit_file", "write_file"}, toolName)
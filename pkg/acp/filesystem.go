@@ -106,6 +106,8 @@ func (t *FilesystemToolset) handleReadFile(ctx context.Context, toolCall tools.T
 		return tools.ResultError(fmt.Sprintf("Error: %s", err)), nil
 	}
 
+	// The whole file is read so that it's paged as by the builtin read_file,
+	// with the total line count and the maximum read size.
 	resp, err := t.agent.conn.ReadTextFile(ctx, acp.ReadTextFileRequest{
 		SessionId: acp.SessionId(sessionID),
 		Path:      resolvedPath,
@@ -114,7 +116,7 @@ func (t *FilesystemToolset) handleReadFile(ctx context.Context, toolCall tools.T
 		return tools.ResultError(fmt.Sprintf("Error reading file: %s", err)), nil
 	}
 
-	return tools.ResultSuccess(resp.Content), nil
+	return t.ReadFileResult(resp.Content, args), nil
 }
 
 func (t *FilesystemToolset) handleWriteFile(ctx context.Context, toolCall tools.ToolCall) (*tools.ToolCallResult, error) {
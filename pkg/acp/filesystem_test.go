@@ -1,11 +1,17 @@
 package acp
 
 import (
+	"context"
+	"io"
 	"path/filepath"
 	"testing"
 
+	"github.com/coder/acp-go-sdk"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/tools/builtin"
 )
 
 func TestResolvePath(t *testing.T) {
@@ -85,3 +91,41 @@ func TestNormalizePathForComparison(t *testing.T) {
 	// The exact behavior depends on the platform.
 	assert.NotEmpty(t, result)
 }
+
+// fileClient is an ACP client serving the content of a single file.
+type fileClient struct {
+	acp.Client
+	content string
+}
+
+func (c *fileClient) ReadTextFile(context.Context, acp.ReadTextFileRequest) (acp.ReadTextFileResponse, error) {
+	return acp.ReadTextFileResponse{Content: c.content}, nil
+}
+
+func TestFilesystemToolset_ReadFilePagesLargeFiles(t *testing.T) {
+	t.Parallel()
+
+	agentReader, clientWriter := io.Pipe()
+	clientReader, agentWriter := io.Pipe()
+	t.Cleanup(func() {
+		_ = agentWriter.Close()
+		_ = clientWriter.Close()
+	})
+	acp.NewClientSideConnection(&fileClient{content: "aaaa\nbbbb\ncccc\n"}, clientWriter, clientReader)
+
+	agent := &Agent{}
+	agent.SetAgentConnection(acp.NewAgentSideConnection(agent, agentWriter, agentReader))
+	ts := NewFilesystemToolset(agent, t.TempDir(), builtin.WithMaxReadSize(10))
+
+	readFile := func(args string) string {
+		result, err := ts.handleReadFile(withSessionID(t.Context(), "session"), tools.ToolCall{
+			Function: tools.FunctionCall{Name: builtin.ToolNameReadFile, Arguments: args},
+		})
+		require.NoError(t, err)
+		return result.Output
+	}
+
+	assert.Equal(t, "aaaa\nbbbb\n\n[File too large (15 bytes), showing lines 1-2 of 3. Use offset=3 to continue.]", readFile(`{"path":"large.txt"}`))
+	assert.Equal(t, "bbbb\n\n[Showing lines 2-2 of 3. Use offset=3 to continue.]", readFile(`{"path":"large.txt","offset":2,"limit":1}`))
+	assert.Equal(t, "cccc\n", readFile(`{"path":"large.txt","offset":3}`))
+}
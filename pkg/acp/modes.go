@@ -0,0 +1,138 @@
+package acp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/coder/acp-go-sdk"
+
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+// Permission profiles, offered as session modes next to the agents of the team.
+const (
+	modeAsk      = "ask"
+	modeReadOnly = "read-only"
+	modeYolo     = "yolo"
+)
+
+// permissionProfile is a session mode that changes how tool calls are approved.
+type permissionProfile struct {
+	id          string
+	name        string
+	description string
+}
+
+var permissionProfiles = []permissionProfile{
+	{
+		id:          modeAsk,
+		name:        "Ask before edits",
+		description: "Ask for confirmation before running tools that can make changes",
+	},
+	{
+		id:          modeReadOnly,
+		name:        "Read-only",
+		description: "Only run tools that don't make changes",
+	},
+	{
+		id:          modeYolo,
+		name:        "YOLO",
+		description: "Run every tool without asking for confirmation",
+	},
+}
+
+// sessionModes lists the modes of a session: one per agent, to switch the
+// current agent, followed by the permission profiles.
+func (a *Agent) sessionModes(acpSess *Session) *acp.SessionModeState {
+	var modes []acp.SessionMode
+	for _, info := range a.team.AgentsInfo() {
+		mode := acp.SessionMode{
+			Id:   acp.SessionModeId(info.Name),
+			Name: info.Name,
+		}
+		if info.Description != "" {
+			mode.Description = &info.Description
+		}
+		modes = append(modes, mode)
+	}
+	for _, profile := range permissionProfiles {
+		if a.isAgentMode(profile.id) {
+			continue
+		}
+		modes = append(modes, acp.SessionMode{
+			Id:          acp.SessionModeId(profile.id),
+			Name:        profile.name,
+			Description: &profile.description,
+		})
+	}
+
+	return &acp.SessionModeState{
+		AvailableModes: modes,
+		CurrentModeId:  acp.SessionModeId(acpSess.mode),
+	}
+}
+
+// isAgentMode reports whether mode selects an agent of the team.
+// Agents take precedence over permission profiles with the same name.
+func (a *Agent) isAgentMode(mode string) bool {
+	return slices.Contains(a.team.AgentNames(), mode)
+}
+
+// setMode switches the current agent or the permission profile of a session.
+func (a *Agent) setMode(ctx context.Context, acpSess *Session, mode string) error {
+	if a.isAgentMode(mode) {
+		if err := acpSess.rt.SetCurrentAgent(mode); err != nil {
+			return err
+		}
+		// The read-only profile denies the tools of the current agent.
+		if acpSess.profile == modeReadOnly {
+			if err := applyPermissionProfile(ctx, acpSess, modeReadOnly); err != nil {
+				return err
+			}
+		}
+		acpSess.mode = mode
+		return nil
+	}
+
+	if !slices.ContainsFunc(permissionProfiles, func(p permissionProfile) bool { return p.id == mode }) {
+		return fmt.Errorf("unknown session mode %q", mode)
+	}
+	if err := applyPermissionProfile(ctx, acpSess, mode); err != nil {
+		return err
+	}
+	acpSess.profile = mode
+	acpSess.mode = mode
+	return nil
+}
+
+// applyPermissionProfile replaces the session permissions with the ones of a profile.
+func applyPermissionProfile(ctx context.Context, acpSess *Session, profile string) error {
+	sess := acpSess.sess
+
+	switch profile {
+	case modeAsk:
+		sess.ToolsApproved = false
+		sess.Permissions = nil
+
+	case modeReadOnly:
+		agentTools, err := acpSess.rt.CurrentAgentTools(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list tools: %w", err)
+		}
+		var deny []string
+		for _, tool := range agentTools {
+			if !tool.Annotations.ReadOnlyHint {
+				deny = append(deny, tool.Name)
+			}
+		}
+		sess.ToolsApproved = false
+		sess.Permissions = &session.PermissionsConfig{Deny: deny}
+
+	case modeYolo:
+		sess.ToolsApproved = true
+		sess.Permissions = nil
+	}
+
+	return nil
+}
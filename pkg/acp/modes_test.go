@@ -0,0 +1,80 @@
+package acp
+
+import (
+	"testing"
+
+	acpsdk "github.com/coder/acp-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/team"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+func TestSessionModes(t *testing.T) {
+	t.Parallel()
+
+	prov := &mockProvider{id: "test/mock-model", stream: &mockStream{}}
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(prov),
+		agent.WithDescription("The coordinator"),
+		agent.WithTools(
+			tools.Tool{Name: "read_file", Annotations: tools.ToolAnnotations{ReadOnlyHint: true}},
+			tools.Tool{Name: "write_file"},
+		),
+	)
+	reviewer := agent.New("reviewer", "You review code", agent.WithModel(prov))
+
+	acpAgent := &Agent{
+		runConfig:    &config.RuntimeConfig{},
+		sessionStore: session.NewInMemorySessionStore(),
+		sessions:     make(map[string]*Session),
+		team:         team.New(team.WithAgents(root, reviewer)),
+	}
+
+	resp, err := acpAgent.NewSession(t.Context(), acpsdk.NewSessionRequest{})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Modes)
+	assert.Equal(t, acpsdk.SessionModeId("root"), resp.Modes.CurrentModeId)
+
+	var ids []acpsdk.SessionModeId
+	for _, mode := range resp.Modes.AvailableModes {
+		ids = append(ids, mode.Id)
+	}
+	assert.Equal(t, []acpsdk.SessionModeId{"root", "reviewer", modeAsk, modeReadOnly, modeYolo}, ids)
+	require.NotNil(t, resp.Modes.AvailableModes[0].Description)
+	assert.Equal(t, "The coordinator", *resp.Modes.AvailableModes[0].Description)
+
+	acpSess := acpAgent.sessions[string(resp.SessionId)]
+	setMode := func(mode string) error {
+		_, err := acpAgent.SetSessionMode(t.Context(), acpsdk.SetSessionModeRequest{
+			SessionId: resp.SessionId,
+			ModeId:    acpsdk.SessionModeId(mode),
+		})
+		return err
+	}
+
+	require.NoError(t, setMode(modeYolo))
+	assert.True(t, acpSess.sess.ToolsApproved)
+
+	require.NoError(t, setMode(modeReadOnly))
+	assert.False(t, acpSess.sess.ToolsApproved)
+	require.NotNil(t, acpSess.sess.Permissions)
+	assert.Equal(t, []string{"write_file"}, acpSess.sess.Permissions.Deny)
+
+	// Switching agents keeps the read-only profile, for the tools of the new agent.
+	require.NoError(t, setMode("reviewer"))
+	assert.Equal(t, "reviewer", acpSess.rt.CurrentAgentName())
+	require.NotNil(t, acpSess.sess.Permissions)
+	assert.Empty(t, acpSess.sess.Permissions.Deny)
+	assert.Equal(t, acpsdk.SessionModeId("reviewer"), acpAgent.sessionModes(acpSess).CurrentModeId)
+
+	require.NoError(t, setMode(modeAsk))
+	assert.Nil(t, acpSess.sess.Permissions)
+	assert.False(t, acpSess.sess.ToolsApproved)
+
+	require.ErrorContains(t, setMode("unknown"), `unknown session mode "unknown"`)
+}
@@ -0,0 +1,90 @@
+package acp
+
+import (
+	"encoding/json"
+
+	"github.com/coder/acp-go-sdk"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/tools/builtin"
+)
+
+// buildReplayUpdates converts the history of a persisted session into the
+// updates a client would have received while it was running, so that it can
+// rebuild the conversation on LoadSession.
+func buildReplayUpdates(sess *session.Session) []acp.SessionUpdate {
+	var updates []acp.SessionUpdate
+	toolCalls := make(map[string]tools.ToolCall)
+
+	for _, item := range sess.Messages {
+		if !item.IsMessage() || item.Message.Implicit {
+			continue
+		}
+
+		msg := item.Message.Message
+		switch msg.Role {
+		case chat.MessageRoleUser:
+			if msg.Content != "" {
+				updates = append(updates, acp.UpdateUserMessageText(msg.Content))
+			}
+
+		case chat.MessageRoleAssistant:
+			if msg.ReasoningContent != "" {
+				updates = append(updates, acp.UpdateAgentThoughtText(msg.ReasoningContent))
+			}
+			if msg.Content != "" {
+				updates = append(updates, acp.UpdateAgentMessageText(msg.Content))
+			}
+			for i, toolCall := range msg.ToolCalls {
+				var toolDef tools.Tool
+				if i < len(msg.ToolDefinitions) {
+					toolDef = msg.ToolDefinitions[i]
+				}
+				toolCalls[toolCall.ID] = toolCall
+				updates = append(updates, buildToolCallStart(toolCall, toolDef))
+			}
+
+		case chat.MessageRoleTool:
+			toolCall, ok := toolCalls[msg.ToolCallID]
+			if !ok {
+				continue
+			}
+			if msg.IsError {
+				updates = append(updates, acp.UpdateToolCall(
+					acp.ToolCallId(toolCall.ID),
+					acp.WithUpdateStatus(acp.ToolCallStatusFailed),
+					acp.WithUpdateContent([]acp.ToolCallContent{acp.ToolContent(acp.TextBlock(msg.Content))}),
+					acp.WithUpdateRawOutput(map[string]any{"content": msg.Content}),
+				))
+				continue
+			}
+			updates = append(updates, buildToolCallComplete(toolCall, msg.Content))
+
+			if isTodoTool(toolCall.Function.Name) {
+				if planUpdate := buildPlanUpdateFromTodos(todosFromOutput(msg.Content)); planUpdate != nil {
+					updates = append(updates, *planUpdate)
+				}
+			}
+		}
+	}
+
+	return updates
+}
+
+// todosFromOutput extracts the current todo list from the JSON output of a
+// todo tool. The live Meta of the tool result isn't persisted with the session.
+func todosFromOutput(output string) []builtin.Todo {
+	var result struct {
+		AllTodos []builtin.Todo `json:"all_todos"`
+		Todos    []builtin.Todo `json:"todos"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		return nil
+	}
+	if result.AllTodos != nil {
+		return result.AllTodos
+	}
+	return result.Todos
+}
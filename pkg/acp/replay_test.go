@@ -0,0 +1,86 @@
+package acp
+
+import (
+	"testing"
+
+	acpsdk "github.com/coder/acp-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/tools/builtin"
+)
+
+func TestBuildReplayUpdates(t *testing.T) {
+	t.Parallel()
+
+	todoCall := tools.ToolCall{
+		ID:       "call-2",
+		Function: tools.FunctionCall{Name: builtin.ToolNameCreateTodos, Arguments: `{}`},
+	}
+	shellCall := tools.ToolCall{
+		ID:       "call-1",
+		Function: tools.FunctionCall{Name: "shell", Arguments: `{"cmd":"ls"}`},
+	}
+
+	sess := session.New()
+	sess.Messages = []session.Item{
+		session.NewMessageItem(session.UserMessage("list the files")),
+		session.NewMessageItem(session.ImplicitUserMessage("hidden")),
+		session.NewMessageItem(&session.Message{
+			AgentName: "root",
+			Message: chat.Message{
+				Role:             chat.MessageRoleAssistant,
+				ReasoningContent: "thinking",
+				Content:          "Sure",
+				ToolCalls:        []tools.ToolCall{shellCall, todoCall},
+				ToolDefinitions:  []tools.Tool{{Name: "shell"}, {Name: builtin.ToolNameCreateTodos}},
+			},
+		}),
+		session.NewMessageItem(&session.Message{Message: chat.Message{Role: chat.MessageRoleTool, ToolCallID: "call-1", Content: "boom", IsError: true}}),
+		session.NewMessageItem(&session.Message{Message: chat.Message{
+			Role:       chat.MessageRoleTool,
+			ToolCallID: "call-2",
+			Content:    `{"all_todos":[{"id":"todo_1","description":"Write tests","status":"in-progress"}]}`,
+		}}),
+		{Summary: "summaries are not replayed"},
+	}
+
+	updates := buildReplayUpdates(sess)
+	require.Len(t, updates, 8)
+
+	require.NotNil(t, updates[0].UserMessageChunk)
+	assert.Equal(t, "list the files", updates[0].UserMessageChunk.Content.Text.Text)
+	require.NotNil(t, updates[1].AgentThoughtChunk)
+	assert.Equal(t, "thinking", updates[1].AgentThoughtChunk.Content.Text.Text)
+	require.NotNil(t, updates[2].AgentMessageChunk)
+	assert.Equal(t, "Sure", updates[2].AgentMessageChunk.Content.Text.Text)
+
+	require.NotNil(t, updates[3].ToolCall)
+	assert.Equal(t, acpsdk.ToolCallId("call-1"), updates[3].ToolCall.ToolCallId)
+	assert.Equal(t, acpsdk.ToolKindExecute, updates[3].ToolCall.Kind)
+	require.NotNil(t, updates[4].ToolCall)
+	assert.Equal(t, acpsdk.ToolCallId("call-2"), updates[4].ToolCall.ToolCallId)
+
+	require.NotNil(t, updates[5].ToolCallUpdate)
+	assert.Equal(t, acpsdk.ToolCallStatusFailed, *updates[5].ToolCallUpdate.Status)
+	require.NotNil(t, updates[6].ToolCallUpdate)
+	assert.Equal(t, acpsdk.ToolCallStatusCompleted, *updates[6].ToolCallUpdate.Status)
+
+	require.NotNil(t, updates[7].Plan)
+	require.Len(t, updates[7].Plan.Entries, 1)
+	assert.Equal(t, "Write tests", updates[7].Plan.Entries[0].Content)
+	assert.Equal(t, acpsdk.PlanEntryStatusInProgress, updates[7].Plan.Entries[0].Status)
+}
+
+func TestLoadSession_NotFound(t *testing.T) {
+	t.Parallel()
+
+	acpAgent := NewAgent(nil, &config.RuntimeConfig{}, session.NewInMemorySessionStore())
+
+	_, err := acpAgent.LoadSession(t.Context(), acpsdk.LoadSessionRequest{SessionId: "missing"})
+	require.ErrorContains(t, err, "session missing not found")
+}
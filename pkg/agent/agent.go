@@ -11,6 +11,7 @@ import (
 	"github.com/docker/docker-agent/pkg/config/latest"
 	"github.com/docker/docker-agent/pkg/config/types"
 	"github.com/docker/docker-agent/pkg/model/provider"
+	"github.com/docker/docker-agent/pkg/redact"
 	"github.com/docker/docker-agent/pkg/tools"
 )
 
@@ -34,11 +35,13 @@ type Agent struct {
 	addDescriptionParameter bool
 	maxIterations           int
 	numHistoryItems         int
+	maxParallelToolCalls    int
 	addPromptFiles          []string
 	tools                   []tools.Tool
 	commands                types.Commands
 	pendingWarnings         []string
 	hooks                   *latest.HooksConfig
+	redactor                *redact.Redactor
 	thinkingConfigured      bool // true if thinking_budget was explicitly set in config
 }
 
@@ -81,6 +84,13 @@ func (a *Agent) NumHistoryItems() int {
 	return a.numHistoryItems
 }
 
+// MaxParallelToolCalls returns the maximum number of read-only tool calls
+// the runtime may execute concurrently within a single model turn.
+// Zero or one means tool calls run sequentially.
+func (a *Agent) MaxParallelToolCalls() int {
+	return a.maxParallelToolCalls
+}
+
 func (a *Agent) AddPromptFiles() []string {
 	return a.addPromptFiles
 }
@@ -197,6 +207,12 @@ func (a *Agent) Hooks() *latest.HooksConfig {
 	return a.hooks
 }
 
+// Redactor returns the redactor applied to this agent's tool results, or
+// nil if secrets are not redacted.
+func (a *Agent) Redactor() *redact.Redactor {
+	return a.redactor
+}
+
 // Tools returns the tools available to this agent
 func (a *Agent) Tools(ctx context.Context) ([]tools.Tool, error) {
 	a.ensureToolSetsAreStarted(ctx)
@@ -6,6 +6,7 @@ import (
 	"github.com/docker/docker-agent/pkg/config/latest"
 	"github.com/docker/docker-agent/pkg/config/types"
 	"github.com/docker/docker-agent/pkg/model/provider"
+	"github.com/docker/docker-agent/pkg/redact"
 	"github.com/docker/docker-agent/pkg/tools"
 )
 
@@ -133,6 +134,12 @@ func WithNumHistoryItems(numHistoryItems int) Opt {
 	}
 }
 
+func WithMaxParallelToolCalls(maxParallelToolCalls int) Opt {
+	return func(a *Agent) {
+		a.maxParallelToolCalls = maxParallelToolCalls
+	}
+}
+
 func WithCommands(commands types.Commands) Opt {
 	return func(a *Agent) {
 		a.commands = commands
@@ -153,6 +160,13 @@ func WithHooks(hooks *latest.HooksConfig) Opt {
 	}
 }
 
+// WithRedactor redacts secrets from the agent's tool results.
+func WithRedactor(redactor *redact.Redactor) Opt {
+	return func(a *Agent) {
+		a.redactor = redactor
+	}
+}
+
 // WithThinkingConfigured sets whether thinking_budget was explicitly configured in the agent's YAML.
 // When true, the session will initialize with thinking enabled.
 func WithThinkingConfigured(configured bool) Opt {
@@ -124,6 +124,17 @@ type SessionsResponse struct {
 	WorkingDir   string `json:"working_dir,omitempty"`
 }
 
+// SessionSearchResult represents a session matching a search query
+type SessionSearchResult struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	CreatedAt   string `json:"created_at"`
+	NumMessages int    `json:"num_messages"`
+	// Snippet is an excerpt of the matching title or message, with the
+	// matched terms wrapped in <mark> and </mark>.
+	Snippet string `json:"snippet"`
+}
+
 // SessionResponse represents a detailed session
 type SessionResponse struct {
 	ID            string                     `json:"id"`
@@ -166,6 +177,20 @@ type UpdateSessionTitleRequest struct {
 	Title string `json:"title"`
 }
 
+// Checkpoint represents the files changed by tools during a turn of a session
+type Checkpoint struct {
+	// Turn is the 1-based index of the user message that started the turn.
+	Turn  int      `json:"turn"`
+	Paths []string `json:"paths"`
+}
+
+// RestoreCheckpointResponse represents the response from restoring the files
+// changed since the start of a turn
+type RestoreCheckpointResponse struct {
+	Turn     int      `json:"turn"`
+	Restored []string `json:"restored"`
+}
+
 // UpdateSessionTitleResponse represents the response from updating a session's title
 type UpdateSessionTitleResponse struct {
 	ID    string `json:"id"`
@@ -18,6 +18,7 @@ import (
 	"github.com/docker/docker-agent/pkg/app/export"
 	"github.com/docker/docker-agent/pkg/app/transcript"
 	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/checkpoint"
 	"github.com/docker/docker-agent/pkg/cli"
 	"github.com/docker/docker-agent/pkg/config/types"
 	"github.com/docker/docker-agent/pkg/runtime"
@@ -271,6 +272,11 @@ func (a *App) ExecuteMCPPrompt(ctx context.Context, promptName string, arguments
 	return a.runtime.ExecuteMCPPrompt(ctx, promptName, arguments)
 }
 
+// CurrentMCPResources returns the available MCP resources for the active agent
+func (a *App) CurrentMCPResources(ctx context.Context) []mcptools.ResourceInfo {
+	return a.runtime.CurrentMCPResources(ctx)
+}
+
 // ResolveCommand converts /command to its prompt text
 func (a *App) ResolveCommand(ctx context.Context, userInput string) string {
 	return runtime.ResolveCommand(ctx, a.runtime, userInput)
@@ -304,6 +310,9 @@ func (a *App) Run(ctx context.Context, cancel context.CancelFunc, message string
 
 			for _, att := range attachments {
 				switch {
+				case att.ResourceURI != "":
+					// MCP resource attachment: read through the runtime.
+					a.processResourceAttachment(ctx, att, &textBuilder)
 				case att.FilePath != "":
 					// File-reference attachment: read and classify from disk.
 					a.processFileAttachment(ctx, att, &textBuilder, &binaryParts)
@@ -458,6 +467,19 @@ func (a *App) processInlineAttachment(att messages.Attachment, textBuilder *stri
 	fmt.Fprintf(textBuilder, "<attached_file path=%q>\n%s\n</attached_file>", att.Name, att.Content)
 }
 
+// processResourceAttachment reads an MCP resource and appends it to
+// textBuilder. The runtime keeps the session up to date with its changes.
+func (a *App) processResourceAttachment(ctx context.Context, att messages.Attachment, textBuilder *strings.Builder) {
+	content, err := a.runtime.AttachMCPResource(ctx, a.session, att.ResourceURI)
+	if err != nil {
+		slog.Warn("skipping attachment: failed to read resource", "uri", att.ResourceURI, "error", err)
+		a.sendEvent(ctx, runtime.Warning(fmt.Sprintf("Skipped attachment %s: failed to read resource", att.Name), ""))
+		return
+	}
+	textBuilder.WriteString("\n\n")
+	fmt.Fprintf(textBuilder, "<attached_resource uri=%q>\n%s\n</attached_resource>", att.ResourceURI, content)
+}
+
 // RunWithMessage runs the agent loop with a pre-constructed message.
 // This is used for special cases like image attachments.
 func (a *App) RunWithMessage(ctx context.Context, cancel context.CancelFunc, msg *session.Message) {
@@ -555,6 +577,7 @@ func (a *App) NewSession() {
 	// so they don't reset to default on /new
 	var opts []session.Opt
 	if a.session != nil {
+		a.runtime.DetachMCPResources(a.session.ID)
 		opts = append(opts,
 			session.WithThinking(a.session.Thinking),
 			session.WithToolsApproved(a.session.ToolsApproved),
@@ -616,6 +639,30 @@ func (a *App) HasPermissions() bool {
 	return a.PermissionsInfo() != nil
 }
 
+// UndoTurn restores the files changed by tools since the start of the given
+// turn to their previous state. A turn of 0 undoes the last turn that changed
+// files. It returns the undone turn and the restored paths.
+func (a *App) UndoTurn(ctx context.Context, turn int) (int, []string, error) {
+	checkpointer, ok := a.runtime.(runtime.Checkpointer)
+	if !ok {
+		return 0, nil, runtime.ErrCheckpointsDisabled
+	}
+
+	if turn == 0 {
+		turns, err := checkpointer.Checkpoints(ctx, a.session)
+		if err != nil {
+			return 0, nil, err
+		}
+		if len(turns) == 0 {
+			return 0, nil, checkpoint.ErrNothingToRestore
+		}
+		turn = turns[len(turns)-1].Number
+	}
+
+	restored, err := checkpointer.RestoreCheckpoint(ctx, a.session, turn)
+	return turn, restored, err
+}
+
 // SwitchAgent switches the currently active agent for subsequent user messages
 func (a *App) SwitchAgent(agentName string) error {
 	return a.runtime.SetCurrentAgent(agentName)
@@ -831,6 +878,9 @@ func (a *App) ReplaceSession(ctx context.Context, sess *session.Session) {
 		a.cancel()
 		a.cancel = nil
 	}
+	if a.session != nil && a.session.ID != sess.ID {
+		a.runtime.DetachMCPResources(a.session.ID)
+	}
 	a.session = sess
 	// Clear first message so it won't be re-sent on re-init
 	a.firstMessage = nil
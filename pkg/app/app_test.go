@@ -60,6 +60,16 @@ func (m *mockRuntime) ExecuteMCPPrompt(context.Context, string, map[string]strin
 	return "", nil
 }
 
+func (m *mockRuntime) CurrentMCPResources(context.Context) []mcptools.ResourceInfo {
+	return nil
+}
+
+func (m *mockRuntime) DetachMCPResources(string) {}
+
+func (m *mockRuntime) AttachMCPResource(context.Context, *session.Session, string) (string, error) {
+	return "", nil
+}
+
 func (m *mockRuntime) UpdateSessionTitle(_ context.Context, sess *session.Session, title string) error {
 	sess.Title = title
 	return nil
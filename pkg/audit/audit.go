@@ -0,0 +1,187 @@
+// Package audit keeps an append-only log of the decisions taken on tool calls:
+// who or what approved or denied each call, the permission pattern that
+// matched, the (redacted) arguments, and how the execution went.
+//
+// The log is a JSON Lines file: one [Record] per line, in the order the
+// decisions were taken. Records are never rewritten.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/redact"
+)
+
+// Decision is what was decided for a tool call.
+type Decision string
+
+const (
+	// DecisionAllow means the tool call was allowed to run.
+	DecisionAllow Decision = "allow"
+	// DecisionDeny means the tool call was rejected and did not run.
+	DecisionDeny Decision = "deny"
+	// DecisionCanceled means the session was canceled while the tool call was waiting for a confirmation.
+	DecisionCanceled Decision = "canceled"
+)
+
+// Source is who or what decided on a tool call.
+type Source string
+
+const (
+	// SourceYolo is the --yolo flag, or the user approving all the tool calls of the session.
+	SourceYolo Source = "yolo"
+	// SourceSessionPermissions is a pattern of the session's permissions.
+	SourceSessionPermissions Source = "session_permissions"
+	// SourcePermissions is a pattern of the permissions configuration.
+	SourcePermissions Source = "permissions"
+	// SourceReadOnlyHint is the read-only annotation of the tool.
+	SourceReadOnlyHint Source = "read_only_hint"
+	// SourceUser is the user answering a confirmation.
+	SourceUser Source = "user"
+	// SourceHook is a pre_tool_use hook.
+	SourceHook Source = "hook"
+)
+
+// Status is the outcome of a tool call that was allowed to run.
+type Status string
+
+const (
+	StatusSuccess  Status = "success"
+	StatusError    Status = "error"
+	StatusCanceled Status = "canceled"
+)
+
+// Record is one entry of the audit log.
+type Record struct {
+	Time      time.Time `json:"time"`
+	SessionID string    `json:"session_id"`
+	// Owner is the authenticated caller the session belongs to, who answers
+	// its confirmations. It is empty when the API runs without authentication.
+	Owner      string         `json:"owner,omitempty"`
+	Agent      string         `json:"agent"`
+	ToolCallID string         `json:"tool_call_id"`
+	Tool       string         `json:"tool"`
+	Arguments  map[string]any `json:"arguments,omitempty"`
+	Decision   Decision       `json:"decision"`
+	DecidedBy  Source         `json:"decided_by"`
+	// Pattern is the permission pattern that matched, if any.
+	Pattern string `json:"pattern,omitempty"`
+	// Reason explains a denial, e.g. the message of a hook or of the user.
+	Reason string `json:"reason,omitempty"`
+	// DurationMs is how long the tool ran, in milliseconds, for the tool calls that were allowed.
+	DurationMs int64  `json:"duration_ms,omitempty"`
+	Status     Status `json:"status,omitempty"`
+}
+
+// Log appends records to a JSON Lines file.
+type Log struct {
+	mu       sync.Mutex
+	file     *os.File
+	redactor *redact.Redactor
+}
+
+// Open opens the audit log at path for appending, creating the file and its
+// directory if needed.
+func Open(path string) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("creating audit log directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+	redactor, err := redact.New(redact.Options{})
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &Log{file: file, redactor: redactor}, nil
+}
+
+// Append writes a record at the end of the log. The secrets in the arguments
+// are redacted before being written.
+func (l *Log) Append(rec Record) error {
+	if rec.Time.IsZero() {
+		rec.Time = time.Now()
+	}
+	rec.Arguments = l.redactor.RedactArguments(rec.Arguments)
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// A single write per record keeps lines whole when several processes
+	// append to the same file.
+	_, err = l.file.Write(line)
+	return err
+}
+
+// Close closes the log file.
+func (l *Log) Close() error {
+	return l.file.Close()
+}
+
+// Filter selects records of the audit log. Zero fields match every record.
+type Filter struct {
+	SessionID string
+	Tool      string
+	Decision  Decision
+	Since     time.Time
+}
+
+// Match reports whether rec is selected by the filter.
+func (f Filter) Match(rec Record) bool {
+	return (f.SessionID == "" || rec.SessionID == f.SessionID) &&
+		(f.Tool == "" || rec.Tool == f.Tool) &&
+		(f.Decision == "" || rec.Decision == f.Decision) &&
+		(f.Since.IsZero() || !rec.Time.Before(f.Since))
+}
+
+// Read returns the records of the log read from r that match the filter, oldest first.
+func Read(r io.Reader, filter Filter) ([]Record, error) {
+	var records []Record
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("reading audit log line %d: %w", line, err)
+		}
+		if filter.Match(rec) {
+			records = append(records, rec)
+		}
+	}
+	return records, scanner.Err()
+}
+
+// ReadFile returns the records of the log at path that match the filter,
+// oldest first. A missing file holds no records.
+func ReadFile(path string, filter Filter) ([]Record, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Read(file, filter)
+}
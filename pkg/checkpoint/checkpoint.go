@@ -0,0 +1,295 @@
+// Package checkpoint snapshots files before tools change them so that the
+// workspace can be restored to its state before a given turn of a session.
+//
+// Snapshots are plain copies of the files, stored on disk next to the session
+// database, so restoring works whether or not the workspace is a git repository.
+package checkpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// manifestFile lists the entries of a turn, in the turn's directory.
+const manifestFile = "manifest.json"
+
+// ErrNothingToRestore is returned when no file was changed since the start of a turn.
+var ErrNothingToRestore = errors.New("no changes to restore")
+
+// Entry records the state of a path before it was first changed in a turn.
+type Entry struct {
+	// Path is the absolute path of the file or directory.
+	Path string `json:"path"`
+	// Existed is false when the path was created during the turn.
+	Existed bool `json:"existed"`
+	// Dir is true when the path was a directory.
+	Dir bool `json:"dir,omitempty"`
+	// Mode holds the permissions of an existing file.
+	Mode fs.FileMode `json:"mode,omitempty"`
+	// Blob is the name of the copy of an existing file, in the turn's directory.
+	Blob string `json:"blob,omitempty"`
+}
+
+// Turn lists the paths changed during a turn of a session.
+type Turn struct {
+	// Number is the 1-based index of the user message that started the turn.
+	Number  int     `json:"number"`
+	Entries []Entry `json:"entries"`
+}
+
+// Store keeps the checkpoints of all sessions under a base directory.
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewStore creates a checkpoint store rooted at dir.
+// The directory is created when the first snapshot is taken.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+func (s *Store) sessionDir(sessionID string) string {
+	return filepath.Join(s.dir, filepath.Base(sessionID))
+}
+
+func (s *Store) turnDir(sessionID string, turn int) string {
+	return filepath.Join(s.sessionDir(sessionID), strconv.Itoa(turn))
+}
+
+// Snapshot records the current state of path, unless it was already recorded
+// during the same turn.
+func (s *Store) Snapshot(sessionID string, turn int, path string) error {
+	if sessionID == "" {
+		return errors.New("session ID cannot be empty")
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := s.turnDir(sessionID, turn)
+	entries, err := readManifest(dir)
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(entries, func(e Entry) bool { return e.Path == path }) {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	entry := Entry{Path: path}
+	info, err := os.Lstat(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		// Parent directories created along with the path are removed on restore too.
+		for parent := filepath.Dir(path); parent != filepath.Dir(parent); parent = filepath.Dir(parent) {
+			if _, err := os.Lstat(parent); !errors.Is(err, fs.ErrNotExist) {
+				break
+			}
+			if !slices.ContainsFunc(entries, func(e Entry) bool { return e.Path == parent }) {
+				entries = append(entries, Entry{Path: parent, Dir: true})
+			}
+		}
+	case err != nil:
+		return err
+	case info.IsDir():
+		entry.Existed = true
+		entry.Dir = true
+	case info.Mode().IsRegular():
+		entry.Existed = true
+		entry.Mode = info.Mode().Perm()
+		entry.Blob = strconv.Itoa(len(entries))
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, entry.Blob), content, 0o600); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("cannot snapshot %s: not a regular file or directory", path)
+	}
+
+	return writeManifest(dir, append(entries, entry))
+}
+
+// Turns returns the turns of a session that changed files, oldest first.
+func (s *Store) Turns(sessionID string) ([]Turn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.turns(sessionID)
+}
+
+func (s *Store) turns(sessionID string) ([]Turn, error) {
+	dirEntries, err := os.ReadDir(s.sessionDir(sessionID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var turns []Turn
+	for _, dirEntry := range dirEntries {
+		number, err := strconv.Atoi(dirEntry.Name())
+		if err != nil || !dirEntry.IsDir() {
+			continue
+		}
+		entries, err := readManifest(filepath.Join(s.sessionDir(sessionID), dirEntry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) > 0 {
+			turns = append(turns, Turn{Number: number, Entries: entries})
+		}
+	}
+	slices.SortFunc(turns, func(a, b Turn) int { return a.Number - b.Number })
+	return turns, nil
+}
+
+// Restore puts every path changed since the start of the given turn back in
+// the state it was in before that turn, and drops the checkpoints of that
+// turn and of the later ones. It returns the restored paths.
+func (s *Store) Restore(sessionID string, turn int) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	turns, err := s.turns(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The oldest snapshot of each path is its state before the turn.
+	type snapshot struct {
+		Entry
+		dir string
+	}
+	var snapshots []snapshot
+	seen := make(map[string]bool)
+	for _, t := range turns {
+		if t.Number < turn {
+			continue
+		}
+		for _, entry := range t.Entries {
+			if !seen[entry.Path] {
+				seen[entry.Path] = true
+				snapshots = append(snapshots, snapshot{Entry: entry, dir: s.turnDir(sessionID, t.Number)})
+			}
+		}
+	}
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("%w since turn %d", ErrNothingToRestore, turn)
+	}
+
+	// Recreate directories and files first, then remove what was created,
+	// deepest paths first so that directories are empty when removed.
+	slices.SortStableFunc(snapshots, func(a, b snapshot) int {
+		if a.Existed != b.Existed {
+			if a.Existed {
+				return -1
+			}
+			return 1
+		}
+		if a.Existed {
+			return len(a.Path) - len(b.Path)
+		}
+		return len(b.Path) - len(a.Path)
+	})
+
+	var restored, failed []string
+	for _, snap := range snapshots {
+		if err := restore(snap.Entry, snap.dir); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", snap.Path, err))
+			continue
+		}
+		restored = append(restored, snap.Path)
+	}
+	if len(failed) > 0 {
+		return restored, fmt.Errorf("failed to restore %d path(s): %v", len(failed), failed)
+	}
+
+	for _, t := range turns {
+		if t.Number >= turn {
+			if err := os.RemoveAll(s.turnDir(sessionID, t.Number)); err != nil {
+				return restored, err
+			}
+		}
+	}
+	return restored, nil
+}
+
+// Delete removes all the checkpoints of a session.
+func (s *Store) Delete(sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return os.RemoveAll(s.sessionDir(sessionID))
+}
+
+func restore(entry Entry, dir string) error {
+	switch {
+	case entry.Existed && entry.Dir:
+		return os.MkdirAll(entry.Path, 0o755)
+	case entry.Existed:
+		content, err := os.ReadFile(filepath.Join(dir, entry.Blob))
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(entry.Path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(entry.Path, content, entry.Mode); err != nil {
+			return err
+		}
+		return os.Chmod(entry.Path, entry.Mode)
+	case entry.Dir:
+		// Keep created directories that still hold files changed by other means.
+		if children, err := os.ReadDir(entry.Path); err != nil || len(children) > 0 {
+			return nil
+		}
+		return os.Remove(entry.Path)
+	default:
+		if err := os.Remove(entry.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+}
+
+func readManifest(dir string) ([]Entry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("reading checkpoint manifest: %w", err)
+	}
+	return entries, nil
+}
+
+func writeManifest(dir string, entries []Entry) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, manifestFile), data, 0o600)
+}
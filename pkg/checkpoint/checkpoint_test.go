@@ -0,0 +1,122 @@
+package checkpoint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreEditedFile(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(t.TempDir())
+	file := filepath.Join(t.TempDir(), "main.go")
+	require.NoError(t, os.WriteFile(file, []byte("original"), 0o640))
+
+	require.NoError(t, store.Snapshot("sess", 1, file))
+	require.NoError(t, os.WriteFile(file, []byte("first edit"), 0o644))
+	// Only the state before the first change of a turn is kept.
+	require.NoError(t, store.Snapshot("sess", 1, file))
+	require.NoError(t, os.WriteFile(file, []byte("second edit"), 0o644))
+
+	restored, err := store.Restore("sess", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{file}, restored)
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "original", string(content))
+
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+
+	turns, err := store.Turns("sess")
+	require.NoError(t, err)
+	assert.Empty(t, turns)
+}
+
+func TestRestoreRemovesCreatedFilesAndDirectories(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(t.TempDir())
+	workspace := t.TempDir()
+	file := filepath.Join(workspace, "a", "b", "new.txt")
+
+	require.NoError(t, store.Snapshot("sess", 1, file))
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+	require.NoError(t, os.WriteFile(file, []byte("new"), 0o644))
+
+	_, err := store.Restore("sess", 1)
+	require.NoError(t, err)
+
+	assert.NoDirExists(t, filepath.Join(workspace, "a"))
+	assert.DirExists(t, workspace)
+}
+
+func TestRestoreRecreatesRemovedDirectory(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(t.TempDir())
+	dir := filepath.Join(t.TempDir(), "empty")
+	require.NoError(t, os.Mkdir(dir, 0o755))
+
+	require.NoError(t, store.Snapshot("sess", 1, dir))
+	require.NoError(t, os.Remove(dir))
+
+	_, err := store.Restore("sess", 1)
+	require.NoError(t, err)
+	assert.DirExists(t, dir)
+}
+
+func TestRestoreSinceTurn(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(t.TempDir())
+	file := filepath.Join(t.TempDir(), "notes.md")
+	require.NoError(t, os.WriteFile(file, []byte("v1"), 0o644))
+
+	require.NoError(t, store.Snapshot("sess", 1, file))
+	require.NoError(t, os.WriteFile(file, []byte("v2"), 0o644))
+	require.NoError(t, store.Snapshot("sess", 2, file))
+	require.NoError(t, os.WriteFile(file, []byte("v3"), 0o644))
+	require.NoError(t, store.Snapshot("sess", 3, file))
+	require.NoError(t, os.WriteFile(file, []byte("v4"), 0o644))
+
+	turns, err := store.Turns("sess")
+	require.NoError(t, err)
+	require.Len(t, turns, 3)
+	assert.Equal(t, []int{1, 2, 3}, []int{turns[0].Number, turns[1].Number, turns[2].Number})
+
+	_, err = store.Restore("sess", 2)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(content))
+
+	turns, err = store.Turns("sess")
+	require.NoError(t, err)
+	require.Len(t, turns, 1)
+	assert.Equal(t, 1, turns[0].Number)
+
+	_, err = store.Restore("sess", 2)
+	require.ErrorIs(t, err, ErrNothingToRestore)
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(t.TempDir())
+	file := filepath.Join(t.TempDir(), "file.txt")
+
+	require.NoError(t, store.Snapshot("sess", 1, file))
+	require.NoError(t, store.Delete("sess"))
+
+	turns, err := store.Turns("sess")
+	require.NoError(t, err)
+	assert.Empty(t, turns)
+}
@@ -0,0 +1,46 @@
+package checkpoint
+
+import (
+	"context"
+	"log/slog"
+)
+
+type recorderKey struct{}
+
+// Recorder snapshots paths for the current turn of a session.
+type Recorder struct {
+	store     *Store
+	sessionID string
+	turn      int
+}
+
+// NewRecorder returns a recorder that snapshots paths into the given turn of a session.
+func NewRecorder(store *Store, sessionID string, turn int) *Recorder {
+	return &Recorder{store: store, sessionID: sessionID, turn: turn}
+}
+
+// WithRecorder returns a context in which tools snapshot the paths they change with rec.
+func WithRecorder(ctx context.Context, rec *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, rec)
+}
+
+// HasRecorder reports whether ctx already carries a recorder.
+func HasRecorder(ctx context.Context) bool {
+	_, ok := ctx.Value(recorderKey{}).(*Recorder)
+	return ok
+}
+
+// Snapshot records the state of paths before a tool changes them.
+// It does nothing when ctx carries no recorder. Failures are logged but
+// don't prevent the change.
+func Snapshot(ctx context.Context, paths ...string) {
+	rec, ok := ctx.Value(recorderKey{}).(*Recorder)
+	if !ok {
+		return
+	}
+	for _, path := range paths {
+		if err := rec.store.Snapshot(rec.sessionID, rec.turn, path); err != nil {
+			slog.Warn("Failed to snapshot file before change", "path", path, "session_id", rec.sessionID, "error", err)
+		}
+	}
+}
@@ -51,6 +51,16 @@ func (m *mockRuntime) CurrentMCPPrompts(context.Context) map[string]mcptools.Pro
 func (m *mockRuntime) ExecuteMCPPrompt(context.Context, string, map[string]string) (string, error) {
 	return "", nil
 }
+
+func (m *mockRuntime) CurrentMCPResources(context.Context) []mcptools.ResourceInfo {
+	return nil
+}
+
+func (m *mockRuntime) DetachMCPResources(string) {}
+
+func (m *mockRuntime) AttachMCPResource(context.Context, *session.Session, string) (string, error) {
+	return "", nil
+}
 func (m *mockRuntime) UpdateSessionTitle(context.Context, *session.Session, string) error    { return nil }
 func (m *mockRuntime) TitleGenerator() *sessiontitle.Generator                               { return nil }
 func (m *mockRuntime) Close() error                                                          { return nil }
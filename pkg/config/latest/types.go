@@ -251,11 +251,13 @@ type AgentConfig struct {
 	AddDescriptionParameter bool              `json:"add_description_parameter,omitempty"`
 	MaxIterations           int               `json:"max_iterations,omitempty"`
 	NumHistoryItems         int               `json:"num_history_items,omitempty"`
+	MaxParallelToolCalls    int               `json:"max_parallel_tool_calls,omitempty"`
 	AddPromptFiles          []string          `json:"add_prompt_files,omitempty" yaml:"add_prompt_files,omitempty"`
 	Commands                types.Commands    `json:"commands,omitempty"`
 	StructuredOutput        *StructuredOutput `json:"structured_output,omitempty"`
 	Skills                  SkillsConfig      `json:"skills,omitzero"`
 	Hooks                   *HooksConfig      `json:"hooks,omitempty"`
+	Redact                  *RedactConfig     `json:"redact,omitempty"`
 }
 
 const SkillSourceLocal = "local"
@@ -566,6 +568,9 @@ type Toolset struct {
 	Ref     string   `json:"ref,omitempty"`
 	Remote  Remote   `json:"remote"`
 	Config  any      `json:"config,omitempty"`
+	// ResourceTools exposes the resources of the MCP server to the agent
+	// with list_resources and read_resource tools.
+	ResourceTools bool `json:"resource_tools,omitempty"`
 
 	// For `mcp` and `lsp` tools - version/package reference for auto-installation.
 	// Format: "owner/repo" or "owner/repo@version"
@@ -598,6 +603,9 @@ type Toolset struct {
 	// For the `filesystem` tool - VCS integration
 	IgnoreVCS *bool `json:"ignore_vcs,omitempty"`
 
+	// For the `filesystem` tool - size in bytes above which reads are paged
+	MaxReadSize int `json:"max_read_size,omitempty"`
+
 	// For the `lsp` tool
 	FileTypes []string `json:"file_types,omitempty"`
 
@@ -1233,8 +1241,27 @@ type PermissionsConfig struct {
 	Deny []string `json:"deny,omitempty"`
 }
 
+// RedactConfig controls the redaction of secrets from tool results before
+// they are stored in the session and sent to the model.
+// Redaction of well-known key formats and of the values of secret
+// environment variables is enabled by default.
+type RedactConfig struct {
+	// Enabled turns redaction on or off (default: true)
+	Enabled *bool `json:"enabled,omitempty"`
+	// Entropy also redacts random-looking strings that don't match a known key format (default: false)
+	Entropy bool `json:"entropy,omitempty"`
+	// Allow lists regular expressions of values that are never redacted.
+	// Each expression must match the whole value.
+	Allow []string `json:"allow,omitempty"`
+}
+
+// IsEnabled returns whether secrets must be redacted.
+func (r *RedactConfig) IsEnabled() bool {
+	return r == nil || r.Enabled == nil || *r.Enabled
+}
+
 // HooksConfig represents the hooks configuration for an agent.
-// Hooks allow running shell commands at various points in the agent lifecycle.
+// Hooks allow running shell commands, calling webhooks or asking a model at various points in the agent lifecycle.
 type HooksConfig struct {
 	// PreToolUse hooks run before tool execution
 	PreToolUse []HookMatcherConfig `json:"pre_tool_use,omitempty" yaml:"pre_tool_use,omitempty"`
@@ -1250,6 +1277,21 @@ type HooksConfig struct {
 
 	// OnUserInput hooks run when the agent needs user input
 	OnUserInput []HookDefinition `json:"on_user_input,omitempty" yaml:"on_user_input,omitempty"`
+
+	// PreModelCall hooks run before each model request
+	PreModelCall []HookDefinition `json:"pre_model_call,omitempty" yaml:"pre_model_call,omitempty"`
+
+	// PostModelCall hooks run after each model response
+	PostModelCall []HookDefinition `json:"post_model_call,omitempty" yaml:"post_model_call,omitempty"`
+
+	// PreCompaction hooks run before the session is summarized
+	PreCompaction []HookDefinition `json:"pre_compaction,omitempty" yaml:"pre_compaction,omitempty"`
+
+	// PostCompaction hooks run after the session was summarized
+	PostCompaction []HookDefinition `json:"post_compaction,omitempty" yaml:"post_compaction,omitempty"`
+
+	// PreDelegation hooks run before a transfer_task or a handoff
+	PreDelegation []HookDefinition `json:"pre_delegation,omitempty" yaml:"pre_delegation,omitempty"`
 }
 
 // IsEmpty returns true if no hooks are configured
@@ -1261,7 +1303,12 @@ func (h *HooksConfig) IsEmpty() bool {
 		len(h.PostToolUse) == 0 &&
 		len(h.SessionStart) == 0 &&
 		len(h.SessionEnd) == 0 &&
-		len(h.OnUserInput) == 0
+		len(h.OnUserInput) == 0 &&
+		len(h.PreModelCall) == 0 &&
+		len(h.PostModelCall) == 0 &&
+		len(h.PreCompaction) == 0 &&
+		len(h.PostCompaction) == 0 &&
+		len(h.PreDelegation) == 0
 }
 
 // HookMatcherConfig represents a hook matcher with its hooks.
@@ -1277,12 +1324,27 @@ type HookMatcherConfig struct {
 
 // HookDefinition represents a single hook configuration
 type HookDefinition struct {
-	// Type specifies the hook type (currently only "command" is supported)
+	// Type specifies the hook type: "command", "webhook" or "prompt"
 	Type string `json:"type" yaml:"type"`
 
-	// Command is the shell command to execute
+	// Command is the shell command to execute (for command hooks)
 	Command string `json:"command,omitempty" yaml:"command,omitempty"`
 
+	// URL is the endpoint the hook input is POSTed to (for webhook hooks)
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// Headers are added to webhook requests
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// Retries is the number of times a failed webhook request is retried
+	Retries int `json:"retries,omitempty" yaml:"retries,omitempty"`
+
+	// Prompt is the policy a model checks tool calls against (for prompt hooks)
+	Prompt string `json:"prompt,omitempty" yaml:"prompt,omitempty"`
+
+	// Model evaluates the prompt of prompt hooks; defaults to the agent's model
+	Model string `json:"model,omitempty" yaml:"model,omitempty"`
+
 	// Timeout is the execution timeout in seconds (default: 60)
 	Timeout int `json:"timeout,omitempty" yaml:"timeout,omitempty"`
 }
@@ -1324,6 +1386,24 @@ func (h *HooksConfig) validate() error {
 		}
 	}
 
+	// Validate model call, compaction and delegation hooks
+	for _, event := range []struct {
+		name  string
+		hooks []HookDefinition
+	}{
+		{"pre_model_call", h.PreModelCall},
+		{"post_model_call", h.PostModelCall},
+		{"pre_compaction", h.PreCompaction},
+		{"post_compaction", h.PostCompaction},
+		{"pre_delegation", h.PreDelegation},
+	} {
+		for i, hook := range event.hooks {
+			if err := hook.validate(event.name, i); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -1348,12 +1428,30 @@ func (h *HookDefinition) validate(prefix string, index int) error {
 		return fmt.Errorf("hooks.%s[%d]: type is required", prefix, index)
 	}
 
-	if h.Type != "command" {
-		return fmt.Errorf("hooks.%s[%d]: unsupported hook type '%s' (only 'command' is supported)", prefix, index, h.Type)
-	}
-
-	if h.Command == "" {
-		return fmt.Errorf("hooks.%s[%d]: command is required for command hooks", prefix, index)
+	switch h.Type {
+	case "command":
+		if h.Command == "" {
+			return fmt.Errorf("hooks.%s[%d]: command is required for command hooks", prefix, index)
+		}
+	case "webhook":
+		if h.URL == "" {
+			return fmt.Errorf("hooks.%s[%d]: url is required for webhook hooks", prefix, index)
+		}
+		if !strings.HasPrefix(h.URL, "http://") && !strings.HasPrefix(h.URL, "https://") {
+			return fmt.Errorf("hooks.%s[%d]: url must start with http:// or https://", prefix, index)
+		}
+		if h.Retries < 0 {
+			return fmt.Errorf("hooks.%s[%d]: retries must not be negative", prefix, index)
+		}
+	case "prompt":
+		if h.Prompt == "" {
+			return fmt.Errorf("hooks.%s[%d]: prompt is required for prompt hooks", prefix, index)
+		}
+		if !strings.HasPrefix(prefix, "pre_tool_use") {
+			return fmt.Errorf("hooks.%s[%d]: prompt hooks are only supported for pre_tool_use", prefix, index)
+		}
+	default:
+		return fmt.Errorf("hooks.%s[%d]: unsupported hook type '%s' (must be 'command', 'webhook' or 'prompt')", prefix, index, h.Type)
 	}
 
 	return nil
@@ -2,6 +2,8 @@ package latest
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
 )
 
 func (t *Config) UnmarshalYAML(unmarshal func(any) error) error {
@@ -33,6 +35,11 @@ func (t *Config) validate() error {
 				return err
 			}
 		}
+		if agent.Redact != nil {
+			if err := agent.Redact.validate(); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -69,6 +76,12 @@ func (t *Toolset) validate() error {
 	if t.IgnoreVCS != nil && t.Type != "filesystem" {
 		return errors.New("ignore_vcs can only be used with type 'filesystem'")
 	}
+	if t.MaxReadSize != 0 && t.Type != "filesystem" {
+		return errors.New("max_read_size can only be used with type 'filesystem'")
+	}
+	if t.MaxReadSize < 0 {
+		return errors.New("max_read_size must not be negative")
+	}
 	if len(t.Env) > 0 && (t.Type != "shell" && t.Type != "script" && t.Type != "mcp" && t.Type != "lsp") {
 		return errors.New("env can only be used with type 'shell', 'script', 'mcp' or 'lsp'")
 	}
@@ -105,6 +118,9 @@ func (t *Toolset) validate() error {
 	if t.Config != nil && t.Type != "mcp" {
 		return errors.New("config can only be used with type 'mcp'")
 	}
+	if t.ResourceTools && t.Type != "mcp" {
+		return errors.New("resource_tools can only be used with type 'mcp'")
+	}
 	if t.URL != "" && t.Type != "a2a" && t.Type != "openapi" {
 		return errors.New("url can only be used with type 'a2a' or 'openapi'")
 	}
@@ -158,3 +174,12 @@ func (t *Toolset) validate() error {
 
 	return nil
 }
+
+func (r *RedactConfig) validate() error {
+	for _, expr := range r.Allow {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid redact.allow expression %q: %w", expr, err)
+		}
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ type RuntimeConfig struct {
 
 	EnvProviderForTests environment.Provider
 	envProvider         environment.Provider
+	envRecorder         *environment.Recorder
 	envProviderLock     sync.Mutex
 }
 
@@ -34,17 +35,35 @@ func (runConfig *RuntimeConfig) Clone() *RuntimeConfig {
 	return clone
 }
 
+// EnvProvider returns the provider used to resolve environment variables.
+// The values it resolves are recorded and returned by [RuntimeConfig.ResolvedEnv].
 func (runConfig *RuntimeConfig) EnvProvider() environment.Provider {
+	runConfig.envProviderLock.Lock()
+	defer runConfig.envProviderLock.Unlock()
+
+	if runConfig.envRecorder == nil {
+		runConfig.envRecorder = environment.NewRecorder()
+	}
+
 	if runConfig.EnvProviderForTests != nil {
-		return runConfig.EnvProviderForTests
+		return runConfig.envRecorder.Wrap(runConfig.EnvProviderForTests)
 	}
 
+	env := runConfig.envRecorder.Wrap(runConfig.computedEnvProvider())
+	runConfig.envProvider = env
+	return env
+}
+
+// ResolvedEnv returns the environment variables resolved so far through
+// [RuntimeConfig.EnvProvider], by name.
+func (runConfig *RuntimeConfig) ResolvedEnv() map[string]string {
 	runConfig.envProviderLock.Lock()
 	defer runConfig.envProviderLock.Unlock()
 
-	env := runConfig.computedEnvProvider()
-	runConfig.envProvider = env
-	return env
+	if runConfig.envRecorder == nil {
+		return nil
+	}
+	return runConfig.envRecorder.Values()
 }
 
 func (runConfig *RuntimeConfig) computedEnvProvider() environment.Provider {
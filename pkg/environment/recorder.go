@@ -0,0 +1,54 @@
+package environment
+
+import (
+	"context"
+	"maps"
+	"sync"
+)
+
+// Recorder remembers the values resolved through the providers it wraps, so
+// that they can be redacted wherever they later appear.
+type Recorder struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{
+		values: make(map[string]string),
+	}
+}
+
+// Wrap returns a provider that resolves variables with p and records the
+// non-empty values it finds.
+func (r *Recorder) Wrap(p Provider) Provider {
+	return &recordingProvider{provider: p, recorder: r}
+}
+
+// Values returns the recorded values, by variable name.
+func (r *Recorder) Values() map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return maps.Clone(r.values)
+}
+
+func (r *Recorder) record(name, value string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.values[name] = value
+}
+
+type recordingProvider struct {
+	provider Provider
+	recorder *Recorder
+}
+
+func (p *recordingProvider) Get(ctx context.Context, name string) (string, bool) {
+	value, found := p.provider.Get(ctx, name)
+	if found && value != "" {
+		p.recorder.record(name, value)
+	}
+	return value, found
+}
@@ -0,0 +1,23 @@
+package environment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewRecorder()
+	provider := recorder.Wrap(NewEnvListProvider([]string{"API_TOKEN=secret-value", "EMPTY="}))
+
+	value, found := provider.Get(t.Context(), "API_TOKEN")
+	assert.True(t, found)
+	assert.Equal(t, "secret-value", value)
+
+	_, _ = provider.Get(t.Context(), "EMPTY")
+	_, _ = provider.Get(t.Context(), "MISSING")
+
+	assert.Equal(t, map[string]string{"API_TOKEN": "secret-value"}, recorder.Values())
+}
@@ -0,0 +1,254 @@
+package evaluation
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+// AssertionResult is the outcome of a deterministic assertion.
+type AssertionResult struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"` // Why the assertion failed
+}
+
+// toolCall is a tool call made during an evaluation run.
+type toolCall struct {
+	name      string
+	arguments string
+}
+
+// runOutcome is what an evaluation run produced, checked by the assertions.
+type runOutcome struct {
+	response     string
+	cost         float64
+	inputTokens  int64
+	outputTokens int64
+	toolCalls    []toolCall
+	// files holds the content of the files read back from the working
+	// directory after the run, by path. Missing files are not in the map.
+	files map[string]string
+}
+
+// checkAssertions checks the deterministic assertions of the criteria
+// against the outcome of a run, in a stable order.
+func checkAssertions(evals *session.EvalCriteria, outcome runOutcome) []AssertionResult {
+	var results []AssertionResult
+	check := func(name string, failure string) {
+		results = append(results, AssertionResult{Name: name, Passed: failure == "", Message: failure})
+	}
+
+	for _, s := range evals.Contains {
+		check(fmt.Sprintf("response contains %q", s), checkContains(outcome.response, s))
+	}
+	for _, s := range evals.NotContains {
+		failure := ""
+		if strings.Contains(outcome.response, s) {
+			failure = "the response contains it"
+		}
+		check(fmt.Sprintf("response does not contain %q", s), failure)
+	}
+	for _, expr := range evals.Matches {
+		check("response matches "+expr, checkMatches(outcome.response, expr))
+	}
+	if evals.ResponseSchema != nil {
+		check("response matches schema", checkSchema(outcome.response, evals.ResponseSchema))
+	}
+	for _, file := range evals.Files {
+		check("file "+file.Path, checkFile(file, outcome.files))
+	}
+	if len(evals.ToolCalls) > 0 {
+		check("tool call sequence", checkToolCalls(evals.ToolCalls, outcome.toolCalls))
+	}
+	if evals.MaxCost > 0 {
+		failure := ""
+		if outcome.cost > evals.MaxCost {
+			failure = fmt.Sprintf("cost $%.6f", outcome.cost)
+		}
+		check(fmt.Sprintf("cost <= $%.6f", evals.MaxCost), failure)
+	}
+	checkTokens := func(kind string, tokens, limit int64) {
+		if limit <= 0 {
+			return
+		}
+		failure := ""
+		if tokens > limit {
+			failure = fmt.Sprintf("%d %s tokens", tokens, kind)
+		}
+		check(fmt.Sprintf("%s tokens <= %d", kind, limit), failure)
+	}
+	checkTokens("input", outcome.inputTokens, evals.MaxInputTokens)
+	checkTokens("output", outcome.outputTokens, evals.MaxOutputTokens)
+	checkTokens("total", outcome.inputTokens+outcome.outputTokens, evals.MaxTotalTokens)
+
+	return results
+}
+
+func checkContains(text, s string) string {
+	if !strings.Contains(text, s) {
+		return "not found"
+	}
+	return ""
+}
+
+func checkMatches(text, expr string) string {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return fmt.Sprintf("invalid regular expression: %v", err)
+	}
+	if !re.MatchString(text) {
+		return "no match"
+	}
+	return ""
+}
+
+// checkSchema validates the response, parsed as JSON, against a JSON schema.
+// Markdown code fences around the JSON are ignored.
+func checkSchema(response string, schema map[string]any) string {
+	buf, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Sprintf("invalid schema: %v", err)
+	}
+	var s jsonschema.Schema
+	if err := json.Unmarshal(buf, &s); err != nil {
+		return fmt.Sprintf("invalid schema: %v", err)
+	}
+	resolved, err := s.Resolve(nil)
+	if err != nil {
+		return fmt.Sprintf("invalid schema: %v", err)
+	}
+
+	var instance any
+	if err := json.Unmarshal([]byte(trimCodeFence(response)), &instance); err != nil {
+		return fmt.Sprintf("the response is not valid JSON: %v", err)
+	}
+	if err := resolved.Validate(instance); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// trimCodeFence removes a markdown code fence, such as ```json ... ```,
+// around text.
+func trimCodeFence(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") || !strings.HasSuffix(text, "```") || len(text) < 6 {
+		return text
+	}
+	text = strings.TrimSuffix(strings.TrimPrefix(text, "```"), "```")
+	if _, rest, found := strings.Cut(text, "\n"); found {
+		text = rest
+	}
+	return strings.TrimSpace(text)
+}
+
+func checkFile(expected session.EvalFile, files map[string]string) string {
+	content, exists := files[expected.Path]
+	if expected.Absent {
+		if exists {
+			return "the file exists"
+		}
+		return ""
+	}
+	if !exists {
+		return "the file does not exist"
+	}
+
+	if expected.Content != nil && content != *expected.Content {
+		return "unexpected content"
+	}
+	for _, s := range expected.Contains {
+		if failure := checkContains(content, s); failure != "" {
+			return fmt.Sprintf("%q %s", s, failure)
+		}
+	}
+	for _, expr := range expected.Matches {
+		if failure := checkMatches(content, expr); failure != "" {
+			return expr + ": " + failure
+		}
+	}
+	return ""
+}
+
+// checkToolCalls checks that the expected tool calls were made in order.
+// Other tool calls can be made before, between or after them.
+func checkToolCalls(expected []session.EvalToolCall, actual []toolCall) string {
+	next := 0
+	for i, want := range expected {
+		found := false
+		for next < len(actual) {
+			call := actual[next]
+			next++
+			if matchToolCall(want, call) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Sprintf("expected tool call #%d %s not found", i+1, describeToolCall(want))
+		}
+	}
+	return ""
+}
+
+func matchToolCall(want session.EvalToolCall, call toolCall) bool {
+	if want.Name != call.name {
+		return false
+	}
+	if len(want.Arguments) == 0 {
+		return true
+	}
+
+	var args map[string]any
+	if err := json.Unmarshal([]byte(call.arguments), &args); err != nil {
+		return false
+	}
+	for name, expr := range want.Arguments {
+		value, ok := args[name]
+		if !ok {
+			return false
+		}
+		str, ok := value.(string)
+		if !ok {
+			buf, _ := json.Marshal(value)
+			str = string(buf)
+		}
+		if checkMatches(str, expr) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// describeToolCall formats an expected tool call, e.g. shell(cmd~=^go test).
+func describeToolCall(call session.EvalToolCall) string {
+	if len(call.Arguments) == 0 {
+		return call.Name
+	}
+	var args []string
+	for name, expr := range call.Arguments {
+		args = append(args, name+"~="+expr)
+	}
+	slices.Sort(args)
+	return call.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// extractToolCallsFromEvents returns the tool calls of the events, in order.
+func extractToolCallsFromEvents(events []map[string]any) []toolCall {
+	var calls []toolCall
+	for _, event := range events {
+		if event["type"] != "tool_call" {
+			continue
+		}
+		name, args := getToolCallInfo(event)
+		calls = append(calls, toolCall{name: name, arguments: args})
+	}
+	return calls
+}
@@ -0,0 +1,151 @@
+package evaluation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+func TestCheckAssertions(t *testing.T) {
+	t.Parallel()
+
+	content := "package main\n"
+	evals := &session.EvalCriteria{
+		Contains:    []string{"42", "missing"},
+		NotContains: []string{"error"},
+		Matches:     []string{`answer is \d+`},
+		Files: []session.EvalFile{
+			{Path: "main.go", Content: &content},
+			{Path: "notes.txt", Absent: true},
+			{Path: "out.txt"},
+		},
+		MaxCost:         0.01,
+		MaxInputTokens:  1000,
+		MaxOutputTokens: 100,
+		MaxTotalTokens:  1000,
+	}
+	outcome := runOutcome{
+		response:     "The answer is 42.",
+		cost:         0.02,
+		inputTokens:  980,
+		outputTokens: 50,
+		files:        map[string]string{"main.go": content},
+	}
+
+	assert.Equal(t, []AssertionResult{
+		{Name: `response contains "42"`, Passed: true},
+		{Name: `response contains "missing"`, Message: "not found"},
+		{Name: `response does not contain "error"`, Passed: true},
+		{Name: `response matches answer is \d+`, Passed: true},
+		{Name: "file main.go", Passed: true},
+		{Name: "file notes.txt", Passed: true},
+		{Name: "file out.txt", Message: "the file does not exist"},
+		{Name: "cost <= $0.010000", Message: "cost $0.020000"},
+		{Name: "input tokens <= 1000", Passed: true},
+		{Name: "output tokens <= 100", Passed: true},
+		{Name: "total tokens <= 1000", Message: "1030 total tokens"},
+	}, checkAssertions(evals, outcome))
+}
+
+func TestCheckAssertionsNone(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, checkAssertions(&session.EvalCriteria{Relevance: []string{"judged"}}, runOutcome{response: "hello"}))
+}
+
+func TestCheckSchema(t *testing.T) {
+	t.Parallel()
+
+	schema := map[string]any{
+		"type":     "object",
+		"required": []any{"name"},
+		"properties": map[string]any{
+			"name": map[string]any{"type": "string"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		response string
+		wantPass bool
+	}{
+		{name: "valid", response: `{"name": "docker"}`, wantPass: true},
+		{name: "valid in code fence", response: "```json\n{\"name\": \"docker\"}\n```", wantPass: true},
+		{name: "missing property", response: `{"other": 1}`},
+		{name: "wrong type", response: `{"name": 1}`},
+		{name: "not json", response: "The name is docker"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			failure := checkSchema(tt.response, schema)
+			if tt.wantPass {
+				assert.Empty(t, failure)
+			} else {
+				assert.NotEmpty(t, failure)
+			}
+		})
+	}
+}
+
+func TestCheckFile(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{"go.mod": "module example.com/app\n\ngo 1.24\n"}
+
+	assert.Empty(t, checkFile(session.EvalFile{Path: "go.mod", Contains: []string{"example.com/app"}, Matches: []string{`go 1\.\d+`}}, files))
+	assert.Equal(t, `"example.org" not found`, checkFile(session.EvalFile{Path: "go.mod", Contains: []string{"example.org"}}, files))
+	assert.Equal(t, `toolchain: no match`, checkFile(session.EvalFile{Path: "go.mod", Matches: []string{"toolchain"}}, files))
+	assert.Equal(t, "the file exists", checkFile(session.EvalFile{Path: "go.mod", Absent: true}, files))
+}
+
+func TestCheckToolCalls(t *testing.T) {
+	t.Parallel()
+
+	actual := []toolCall{
+		{name: "read_file", arguments: `{"path":"main.go"}`},
+		{name: "edit_file", arguments: `{"path":"main.go"}`},
+		{name: "shell", arguments: `{"cmd":"go build ./..."}`},
+		{name: "shell", arguments: `{"cmd":"go test ./...","timeout":60}`},
+	}
+
+	tests := []struct {
+		name     string
+		expected []session.EvalToolCall
+		want     string
+	}{
+		{
+			name:     "subsequence in order",
+			expected: []session.EvalToolCall{{Name: "edit_file"}, {Name: "shell", Arguments: map[string]string{"cmd": "^go test"}}},
+		},
+		{
+			name:     "non-string argument",
+			expected: []session.EvalToolCall{{Name: "shell", Arguments: map[string]string{"timeout": "^60$"}}},
+		},
+		{
+			name:     "wrong order",
+			expected: []session.EvalToolCall{{Name: "edit_file"}, {Name: "read_file"}},
+			want:     "expected tool call #2 read_file not found",
+		},
+		{
+			name:     "argument mismatch",
+			expected: []session.EvalToolCall{{Name: "shell", Arguments: map[string]string{"cmd": "^rm"}}},
+			want:     "expected tool call #1 shell(cmd~=^rm) not found",
+		},
+		{
+			name:     "missing argument",
+			expected: []session.EvalToolCall{{Name: "read_file", Arguments: map[string]string{"offset": "."}}},
+			want:     "expected tool call #1 read_file(offset~=.) not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, checkToolCalls(tt.expected, actual))
+		})
+	}
+}
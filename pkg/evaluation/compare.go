@@ -0,0 +1,314 @@
+package evaluation
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// CompareOptions configures how two eval runs are compared.
+type CompareOptions struct {
+	// Tolerance is the relative increase of cost or output tokens of an eval
+	// above which it is considered a regression, e.g. 0.2 for 20%.
+	Tolerance float64
+}
+
+// CaseComparison compares the results of an eval in two runs.
+type CaseComparison struct {
+	Name         string   `json:"name"`
+	Title        string   `json:"title"`
+	Base         *Result  `json:"-"` // Nil if the eval is new
+	Head         *Result  `json:"-"` // Nil if the eval was removed
+	BasePassed   bool     `json:"base_passed"`
+	HeadPassed   bool     `json:"head_passed"`
+	Regressions  []string `json:"regressions,omitempty"`
+	Improvements []string `json:"improvements,omitempty"`
+}
+
+// Comparison is the comparison of two eval runs, eval by eval.
+type Comparison struct {
+	Base  string           `json:"base"`
+	Head  string           `json:"head"`
+	Cases []CaseComparison `json:"cases"`
+}
+
+// Regressions returns the evals that regressed.
+func (c *Comparison) Regressions() []CaseComparison {
+	var regressed []CaseComparison
+	for _, cc := range c.Cases {
+		if len(cc.Regressions) > 0 {
+			regressed = append(regressed, cc)
+		}
+	}
+	return regressed
+}
+
+// Compare compares two eval runs. Evals are matched by the name of their
+// eval file.
+func Compare(base, head *EvalRun, opts CompareOptions) *Comparison {
+	comparison := &Comparison{Base: base.Name, Head: head.Name}
+
+	baseResults := resultsByName(base.Results)
+	headResults := resultsByName(head.Results)
+
+	var names []string
+	for name := range baseResults {
+		names = append(names, name)
+	}
+	for name := range headResults {
+		if _, ok := baseResults[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		comparison.Cases = append(comparison.Cases, compareResults(name, baseResults[name], headResults[name], opts))
+	}
+
+	return comparison
+}
+
+func resultsByName(results []Result) map[string]*Result {
+	byName := make(map[string]*Result, len(results))
+	for i := range results {
+		byName[resultName(&results[i])] = &results[i]
+	}
+	return byName
+}
+
+// resultName identifies the eval of a result across runs.
+func resultName(r *Result) string {
+	if r.InputPath != "" {
+		return filepath.Base(r.InputPath)
+	}
+	return r.Title
+}
+
+func passed(r *Result) bool {
+	_, failures := r.checkResults()
+	return len(failures) == 0
+}
+
+func compareResults(name string, base, head *Result, opts CompareOptions) CaseComparison {
+	cc := CaseComparison{Name: name, Base: base, Head: head}
+
+	switch {
+	case base == nil:
+		cc.Title = head.Title
+		cc.HeadPassed = passed(head)
+		cc.Improvements = append(cc.Improvements, "new eval")
+		return cc
+	case head == nil:
+		cc.Title = base.Title
+		cc.BasePassed = passed(base)
+		cc.Regressions = append(cc.Regressions, "eval missing from the run")
+		return cc
+	}
+
+	cc.Title = head.Title
+	cc.BasePassed = passed(base)
+	cc.HeadPassed = passed(head)
+
+	switch {
+	case cc.BasePassed && !cc.HeadPassed:
+		_, failures := head.checkResults()
+		cc.Regressions = append(cc.Regressions, "now failing: "+strings.Join(failures, "; "))
+	case !cc.BasePassed && cc.HeadPassed:
+		cc.Improvements = append(cc.Improvements, "now passing")
+	}
+
+	if head.RelevancePassed < base.RelevancePassed {
+		cc.Regressions = append(cc.Regressions, fmt.Sprintf("relevance %.0f/%.0f, was %.0f/%.0f", head.RelevancePassed, head.RelevanceExpected, base.RelevancePassed, base.RelevanceExpected))
+	} else if head.RelevancePassed > base.RelevancePassed {
+		cc.Improvements = append(cc.Improvements, fmt.Sprintf("relevance %.0f/%.0f, was %.0f/%.0f", head.RelevancePassed, head.RelevanceExpected, base.RelevancePassed, base.RelevanceExpected))
+	}
+
+	const epsilon = 1e-9
+	if head.ToolCallsScore < base.ToolCallsScore-epsilon {
+		cc.Regressions = append(cc.Regressions, fmt.Sprintf("tool calls score %.2f, was %.2f", head.ToolCallsScore, base.ToolCallsScore))
+	} else if head.ToolCallsScore > base.ToolCallsScore+epsilon {
+		cc.Improvements = append(cc.Improvements, fmt.Sprintf("tool calls score %.2f, was %.2f", head.ToolCallsScore, base.ToolCallsScore))
+	}
+
+	if exceedsTolerance(head.Cost, base.Cost, opts.Tolerance) {
+		cc.Regressions = append(cc.Regressions, fmt.Sprintf("cost $%.6f, was $%.6f", head.Cost, base.Cost))
+	}
+	if exceedsTolerance(float64(head.OutputTokens), float64(base.OutputTokens), opts.Tolerance) {
+		cc.Regressions = append(cc.Regressions, fmt.Sprintf("%d output tokens, was %d", head.OutputTokens, base.OutputTokens))
+	}
+
+	return cc
+}
+
+// exceedsTolerance reports whether value increased by more than the relative
+// tolerance compared to base. Nothing is compared to a base of zero.
+func exceedsTolerance(value, base, tolerance float64) bool {
+	if base <= 0 {
+		return false
+	}
+	return value > base*(1+tolerance)
+}
+
+// LoadRunJSON loads eval run results saved by [SaveRunJSON].
+func LoadRunJSON(path string) (*EvalRun, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var run EvalRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("%s is not an eval run results file: %w", path, err)
+	}
+	return &run, nil
+}
+
+// ResolveRun returns the path of the results file of a run, given either its
+// path or its name in the results directory.
+func ResolveRun(resultsDir, run string) (string, error) {
+	if strings.HasSuffix(run, ".json") {
+		if _, err := os.Stat(run); err == nil {
+			return run, nil
+		}
+	}
+
+	path := filepath.Join(resultsDir, run+".json")
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("run %q not found in %s", run, resultsDir)
+	}
+	return path, nil
+}
+
+// LatestRun returns the path of the results file of the most recent run in
+// the results directory, other than the given run.
+func LatestRun(resultsDir, except string) (string, error) {
+	entries, err := os.ReadDir(resultsDir)
+	if err != nil {
+		return "", err
+	}
+
+	var latest *EvalRun
+	var latestPath string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") || strings.TrimSuffix(name, ".json") == except {
+			continue
+		}
+
+		path := filepath.Join(resultsDir, name)
+		run, err := LoadRunJSON(path)
+		if err != nil || run.Name == "" {
+			continue // Not a results file, e.g. the sessions of a run
+		}
+		if latest == nil || run.Timestamp.After(latest.Timestamp) {
+			latest, latestPath = run, path
+		}
+	}
+
+	if latest == nil {
+		return "", errors.New("no previous run found in " + resultsDir)
+	}
+	return latestPath, nil
+}
+
+// WriteMarkdown writes the comparison as a markdown report.
+func (c *Comparison) WriteMarkdown(w io.Writer) error {
+	regressions := c.Regressions()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Eval comparison: %s → %s\n\n", c.Base, c.Head)
+	if len(regressions) == 0 {
+		fmt.Fprintf(&b, "No regressions in %d evals.\n\n", len(c.Cases))
+	} else {
+		fmt.Fprintf(&b, "**%d of %d evals regressed.**\n\n", len(regressions), len(c.Cases))
+	}
+
+	b.WriteString("| Eval | Status | Relevance | Tool calls | Cost | Output tokens |\n")
+	b.WriteString("| ---- | ------ | --------- | ---------- | ---- | ------------- |\n")
+	for _, cc := range c.Cases {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+			escapeMarkdownCell(cmp.Or(cc.Title, cc.Name)),
+			cc.status(),
+			compareCell(cc, func(r *Result) string { return fmt.Sprintf("%.0f/%.0f", r.RelevancePassed, r.RelevanceExpected) }),
+			compareCell(cc, func(r *Result) string { return fmt.Sprintf("%.2f", r.ToolCallsScore) }),
+			compareCell(cc, func(r *Result) string { return fmt.Sprintf("$%.6f", r.Cost) }),
+			compareCell(cc, func(r *Result) string { return fmt.Sprintf("%d", r.OutputTokens) }),
+		)
+	}
+
+	if len(regressions) > 0 {
+		b.WriteString("\n### Regressions\n\n")
+		for _, cc := range regressions {
+			fmt.Fprintf(&b, "- **%s**: %s\n", cmp.Or(cc.Title, cc.Name), strings.Join(cc.Regressions, "; "))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (cc *CaseComparison) status() string {
+	switch {
+	case len(cc.Regressions) > 0:
+		return "❌ regressed"
+	case cc.Base == nil:
+		return "🆕 new"
+	case len(cc.Improvements) > 0:
+		return "✅ improved"
+	case cc.HeadPassed:
+		return "✅ passed"
+	default:
+		return "✗ failing"
+	}
+}
+
+// compareCell formats a metric of both runs, e.g. "1/2 → 2/2".
+func compareCell(cc CaseComparison, format func(*Result) string) string {
+	switch {
+	case cc.Base == nil:
+		return format(cc.Head)
+	case cc.Head == nil:
+		return format(cc.Base) + " → -"
+	}
+	base, head := format(cc.Base), format(cc.Head)
+	if base == head {
+		return head
+	}
+	return base + " → " + head
+}
+
+func escapeMarkdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// WriteJUnit writes the comparison as a JUnit XML report, with one test case
+// per eval that fails when the eval regressed.
+func (c *Comparison) WriteJUnit(w io.Writer) error {
+	suite := junitTestSuite{
+		Name:  fmt.Sprintf("eval compare %s..%s", c.Base, c.Head),
+		Tests: len(c.Cases),
+	}
+	for _, cc := range c.Cases {
+		tc := junitTestCase{
+			Name:      cmp.Or(cc.Title, cc.Name),
+			ClassName: cc.Name,
+		}
+		if len(cc.Regressions) > 0 {
+			suite.Failures++
+			tc.Failure = &junitFailure{
+				Message: "regressed",
+				Text:    strings.Join(cc.Regressions, "\n"),
+			}
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	return writeJUnit(w, junitTestSuites{Suites: []junitTestSuite{suite}})
+}
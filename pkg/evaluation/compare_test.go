@@ -0,0 +1,114 @@
+package evaluation
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	base := &EvalRun{
+		Name: "base",
+		Results: []Result{
+			{InputPath: "evals/a.json", Title: "A", HandoffsMatch: true, RelevanceExpected: 2, RelevancePassed: 2, ToolCallsExpected: 1, ToolCallsScore: 1, Cost: 0.01, OutputTokens: 100},
+			{InputPath: "evals/b.json", Title: "B", HandoffsMatch: true, RelevanceExpected: 1, RelevancePassed: 0, Cost: 0.01, OutputTokens: 100, FailedRelevance: []RelevanceResult{{Criterion: "check", Reason: "no"}}},
+			{InputPath: "evals/c.json", Title: "C", HandoffsMatch: true, Cost: 0.01, OutputTokens: 100},
+			{InputPath: "evals/removed.json", Title: "Removed", HandoffsMatch: true},
+		},
+	}
+	head := &EvalRun{
+		Name: "head",
+		Results: []Result{
+			{InputPath: "other/a.json", Title: "A", HandoffsMatch: true, RelevanceExpected: 2, RelevancePassed: 1, ToolCallsExpected: 1, ToolCallsScore: 0.5, Cost: 0.01, OutputTokens: 100, FailedRelevance: []RelevanceResult{{Criterion: "check", Reason: "no"}}},
+			{InputPath: "other/b.json", Title: "B", HandoffsMatch: true, RelevanceExpected: 1, RelevancePassed: 1, Cost: 0.01, OutputTokens: 100},
+			{InputPath: "other/c.json", Title: "C", HandoffsMatch: true, Cost: 0.0115, OutputTokens: 200},
+			{InputPath: "other/new.json", Title: "New", HandoffsMatch: true},
+		},
+	}
+
+	comparison := Compare(base, head, CompareOptions{Tolerance: 0.2})
+	require.Len(t, comparison.Cases, 5)
+
+	byName := make(map[string]CaseComparison)
+	for _, cc := range comparison.Cases {
+		byName[cc.Name] = cc
+	}
+
+	assert.Equal(t, []string{
+		"now failing: tool calls score 0.50; relevance: check (reason: no)",
+		"relevance 1/2, was 2/2",
+		"tool calls score 0.50, was 1.00",
+	}, byName["a.json"].Regressions)
+	assert.Empty(t, byName["b.json"].Regressions)
+	assert.Equal(t, []string{"now passing", "relevance 1/1, was 0/1"}, byName["b.json"].Improvements)
+	assert.Equal(t, []string{"200 output tokens, was 100"}, byName["c.json"].Regressions)
+	assert.Equal(t, []string{"eval missing from the run"}, byName["removed.json"].Regressions)
+	assert.Equal(t, []string{"new eval"}, byName["new.json"].Improvements)
+
+	var names []string
+	for _, cc := range comparison.Regressions() {
+		names = append(names, cc.Name)
+	}
+	assert.Equal(t, []string{"a.json", "c.json", "removed.json"}, names)
+}
+
+func TestCompareReports(t *testing.T) {
+	t.Parallel()
+
+	base := &EvalRun{Name: "base", Results: []Result{
+		{InputPath: "a.json", Title: "A | B", HandoffsMatch: true, RelevanceExpected: 1, RelevancePassed: 1},
+		{InputPath: "b.json", Title: "Same", HandoffsMatch: true},
+	}}
+	head := &EvalRun{Name: "head", Results: []Result{
+		{InputPath: "a.json", Title: "A | B", HandoffsMatch: true, RelevanceExpected: 1, FailedRelevance: []RelevanceResult{{Criterion: "check", Reason: "no"}}},
+		{InputPath: "b.json", Title: "Same", HandoffsMatch: true},
+	}}
+	comparison := Compare(base, head, CompareOptions{})
+
+	var md bytes.Buffer
+	require.NoError(t, comparison.WriteMarkdown(&md))
+	assert.Contains(t, md.String(), "## Eval comparison: base → head")
+	assert.Contains(t, md.String(), "**1 of 2 evals regressed.**")
+	assert.Contains(t, md.String(), `| A \| B | ❌ regressed | 1/1 → 0/1 | 0.00 | $0.000000 | 0 |`)
+	assert.Contains(t, md.String(), "| Same | ✅ passed |")
+
+	var junit bytes.Buffer
+	require.NoError(t, comparison.WriteJUnit(&junit))
+	assert.Contains(t, junit.String(), `<testsuite name="eval compare base..head" tests="2" failures="1">`)
+	assert.Contains(t, junit.String(), `<testcase name="A | B" classname="a.json">`)
+	assert.Contains(t, junit.String(), `<failure message="regressed">now failing: relevance: check (reason: no)&#xA;relevance 0/1, was 1/1</failure>`)
+	assert.Contains(t, junit.String(), `<testcase name="Same" classname="b.json"></testcase>`)
+}
+
+func TestLatestRun(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	now := time.Now()
+	for i, name := range []string{"older", "newer", "current"} {
+		_, err := SaveRunJSON(&EvalRun{Name: name, Timestamp: now.Add(time.Duration(i) * time.Minute)}, dir)
+		require.NoError(t, err)
+	}
+	_, err := SaveRunSessionsJSON(&EvalRun{Name: "current"}, dir)
+	require.NoError(t, err)
+
+	latest, err := LatestRun(dir, "current")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "newer.json"), latest)
+
+	path, err := ResolveRun(dir, "older")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "older.json"), path)
+
+	_, err = ResolveRun(dir, "missing")
+	require.Error(t, err)
+
+	_, err = LatestRun(t.TempDir(), "")
+	require.Error(t, err)
+}
@@ -2,7 +2,9 @@
 package evaluation
 
 import (
+	"archive/tar"
 	"bufio"
+	"bytes"
 	"cmp"
 	"context"
 	"encoding/json"
@@ -12,6 +14,7 @@ import (
 	"log/slog"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -119,8 +122,10 @@ func (r *Runner) Run(ctx context.Context, ttyOut, out io.Writer, isTTY bool) ([]
 
 	// Pre-build all unique Docker images in parallel before running evaluations.
 	// This avoids serialized builds when multiple workers need the same image.
-	if err := r.preBuildImages(ctx, out, evals); err != nil {
-		return nil, fmt.Errorf("pre-building images: %w", err)
+	if !r.Local {
+		if err := r.preBuildImages(ctx, out, evals); err != nil {
+			return nil, fmt.Errorf("pre-building images: %w", err)
+		}
 	}
 
 	fmt.Fprintf(out, "Running %d evaluations with concurrency %d\n\n", len(evals), r.Concurrency)
@@ -146,7 +151,9 @@ func (r *Runner) Run(ctx context.Context, ttyOut, out io.Writer, isTTY bool) ([]
 				}
 
 				progress.setRunning(item.eval.Title)
+				startTime := time.Now()
 				result, runErr := r.runSingleEval(ctx, item.eval)
+				result.Duration = time.Since(startTime)
 				if runErr != nil {
 					result.Error = runErr.Error()
 					slog.Error("Evaluation failed", "title", item.eval.Title, "error", runErr)
@@ -311,20 +318,38 @@ func (r *Runner) runSingleEval(ctx context.Context, evalSess *InputSession) (Res
 
 	workingDir := evals.WorkingDir
 
-	imageID, err := r.getOrBuildImage(ctx, workingDir)
-	if err != nil {
-		return result, fmt.Errorf("building eval image: %w", err)
+	var filePaths []string
+	for _, file := range evals.Files {
+		filePaths = append(filePaths, file.Path)
 	}
 
-	events, err := r.runDockerAgentInContainer(ctx, imageID, userMessages, evals.Setup)
-	if err != nil {
-		return result, fmt.Errorf("running docker agent in container: %w", err)
+	var (
+		events []map[string]any
+		files  map[string]string
+	)
+	if r.Local {
+		var err error
+		events, files, err = r.runAgentInProcess(ctx, userMessages, workingDir, evals.Setup, filePaths)
+		if err != nil {
+			return result, fmt.Errorf("running agent in process: %w", err)
+		}
+	} else {
+		imageID, err := r.getOrBuildImage(ctx, workingDir)
+		if err != nil {
+			return result, fmt.Errorf("building eval image: %w", err)
+		}
+
+		events, files, err = r.runDockerAgentInContainer(ctx, imageID, userMessages, evals.Setup, filePaths)
+		if err != nil {
+			return result, fmt.Errorf("running docker agent in container: %w", err)
+		}
 	}
 
-	response, cost, outputTokens, actualToolCalls := parseContainerEvents(events)
+	response, cost, inputTokens, outputTokens, actualToolCalls := parseContainerEvents(events)
 
 	result.Response = response
 	result.Cost = cost
+	result.InputTokens = inputTokens
 	result.OutputTokens = outputTokens
 	result.Size = getResponseSize(result.Response)
 
@@ -338,6 +363,15 @@ func (r *Runner) runSingleEval(ctx context.Context, evalSess *InputSession) (Res
 
 	result.HandoffsMatch = countHandoffs(expectedToolCalls) == countHandoffs(actualToolCalls)
 
+	result.Assertions = checkAssertions(evals, runOutcome{
+		response:     result.Response,
+		cost:         result.Cost,
+		inputTokens:  result.InputTokens,
+		outputTokens: result.OutputTokens,
+		toolCalls:    extractToolCallsFromEvents(events),
+		files:        files,
+	})
+
 	if r.judge != nil && len(evals.Relevance) > 0 {
 		// Use transcript for relevance checking to preserve temporal ordering
 		transcript := buildTranscript(events)
@@ -353,7 +387,10 @@ func (r *Runner) runSingleEval(ctx context.Context, evalSess *InputSession) (Res
 	return result, nil
 }
 
-func (r *Runner) runDockerAgentInContainer(ctx context.Context, imageID string, questions []string, setup string) ([]map[string]any, error) {
+// runDockerAgentInContainer runs the agent in a container and returns its
+// events, along with the content of the given files of the working directory
+// after the run, by path.
+func (r *Runner) runDockerAgentInContainer(ctx context.Context, imageID string, questions []string, setup string, files []string) ([]map[string]any, map[string]string, error) {
 	agentDir := r.agentSource.ParentDir()
 	agentFile := filepath.Base(r.agentSource.Name())
 	containerName := fmt.Sprintf("docker-agent-eval-%d", uuid.New().ID())
@@ -364,9 +401,13 @@ func (r *Runner) runDockerAgentInContainer(ctx context.Context, imageID string,
 		"--privileged",
 		"--init",
 	}
-	if !r.KeepContainers {
+	// Files are read back from the stopped container, which is then removed.
+	if !r.KeepContainers && len(files) == 0 {
 		args = append(args, "--rm")
 	}
+	if !r.KeepContainers && len(files) > 0 {
+		defer removeContainer(containerName)
+	}
 	args = append(args,
 		"-i",
 		"-v", agentDir+":/configs:ro",
@@ -410,7 +451,7 @@ func (r *Runner) runDockerAgentInContainer(ctx context.Context, imageID string,
 	if setup != "" {
 		setupFile := filepath.Join(os.TempDir(), fmt.Sprintf("docker-agent-eval-setup-%d.sh", uuid.New().ID()))
 		if err := os.WriteFile(setupFile, []byte(setup), 0o600); err != nil {
-			return nil, fmt.Errorf("writing setup script: %w", err)
+			return nil, nil, fmt.Errorf("writing setup script: %w", err)
 		}
 		defer os.Remove(setupFile)
 
@@ -434,15 +475,15 @@ func (r *Runner) runDockerAgentInContainer(ctx context.Context, imageID string,
 	cmd.Env = append(env, os.Environ()...)
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		return nil, fmt.Errorf("creating stdout pipe: %w", err)
+		return nil, nil, fmt.Errorf("creating stdout pipe: %w", err)
 	}
 	stderr, err := cmd.StderrPipe()
 	if err != nil {
-		return nil, fmt.Errorf("creating stderr pipe: %w", err)
+		return nil, nil, fmt.Errorf("creating stderr pipe: %w", err)
 	}
 
 	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("starting docker run: %w", err)
+		return nil, nil, fmt.Errorf("starting docker run: %w", err)
 	}
 
 	var stderrData []byte
@@ -450,8 +491,37 @@ func (r *Runner) runDockerAgentInContainer(ctx context.Context, imageID string,
 		stderrData, _ = io.ReadAll(stderr)
 	}()
 
+	events := parseEventLines(stdout)
+
+	waitErr := cmd.Wait()
+	if waitErr != nil {
+		slog.Debug("Container exited with error", "stderr", string(stderrData), "error", waitErr)
+	}
+
+	if len(events) == 0 {
+		stderrStr := strings.TrimSpace(string(stderrData))
+		if waitErr != nil {
+			return nil, nil, fmt.Errorf("container failed: %w (stderr: %s)", waitErr, stderrStr)
+		}
+		if stderrStr != "" {
+			return nil, nil, fmt.Errorf("no events received from container (stderr: %s)", stderrStr)
+		}
+		return nil, nil, errors.New("no events received from container")
+	}
+
+	fileContents, err := readContainerFiles(ctx, containerName, files)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return events, fileContents, nil
+}
+
+// parseEventLines parses the JSON events written one per line by
+// docker-agent run --exec --json.
+func parseEventLines(r io.Reader) []map[string]any {
 	var events []map[string]any
-	scanner := bufio.NewScanner(stdout)
+	scanner := bufio.NewScanner(r)
 	scanner.Buffer(make([]byte, 0, 1024*1024), 10*1024*1024)
 
 	for scanner.Scan() {
@@ -469,29 +539,56 @@ func (r *Runner) runDockerAgentInContainer(ctx context.Context, imageID string,
 	}
 
 	if err := scanner.Err(); err != nil {
-		slog.Warn("Error reading container output", "error", err)
+		slog.Warn("Error reading agent output", "error", err)
 	}
 
-	waitErr := cmd.Wait()
-	if waitErr != nil {
-		slog.Debug("Container exited with error", "stderr", string(stderrData), "error", waitErr)
-	}
+	return events
+}
 
-	if len(events) == 0 {
-		stderrStr := strings.TrimSpace(string(stderrData))
-		if waitErr != nil {
-			return nil, fmt.Errorf("container failed: %w (stderr: %s)", waitErr, stderrStr)
+// readContainerFiles reads files of the working directory of a stopped
+// container. Files that don't exist are left out of the returned map.
+func readContainerFiles(ctx context.Context, containerName string, files []string) (map[string]string, error) {
+	contents := make(map[string]string, len(files))
+	for _, file := range files {
+		src := containerName + ":" + path.Join("/working_dir", file)
+
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, "docker", "cp", src, "-")
+		cmd.Stderr = &stderr
+		output, err := cmd.Output()
+		if err != nil {
+			if strings.Contains(stderr.String(), "Could not find the file") || strings.Contains(stderr.String(), "No such container:path") {
+				continue
+			}
+			return nil, fmt.Errorf("reading %s from container: %w (stderr: %s)", file, err, strings.TrimSpace(stderr.String()))
 		}
-		if stderrStr != "" {
-			return nil, fmt.Errorf("no events received from container (stderr: %s)", stderrStr)
+
+		// docker cp writes a tar archive to stdout
+		tr := tar.NewReader(bytes.NewReader(output))
+		header, err := tr.Next()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s from container: %w", file, err)
 		}
-		return nil, errors.New("no events received from container")
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s from container: %w", file, err)
+		}
+		contents[file] = string(content)
 	}
+	return contents, nil
+}
 
-	return events, nil
+// removeContainer removes a stopped container, logging failures.
+func removeContainer(containerName string) {
+	if output, err := exec.Command("docker", "rm", "-f", containerName).CombinedOutput(); err != nil {
+		slog.Warn("Failed to remove eval container", "container", containerName, "error", err, "output", string(output))
+	}
 }
 
-func parseContainerEvents(events []map[string]any) (response string, cost float64, outputTokens int64, toolCalls []string) {
+func parseContainerEvents(events []map[string]any) (response string, cost float64, inputTokens, outputTokens int64, toolCalls []string) {
 	var responseBuf strings.Builder
 	for _, event := range events {
 		eventType, _ := event["type"].(string)
@@ -514,6 +611,9 @@ func parseContainerEvents(events []map[string]any) (response string, cost float6
 				if c, ok := usage["cost"].(float64); ok {
 					cost = c
 				}
+				if tokens, ok := usage["input_tokens"].(float64); ok {
+					inputTokens += int64(tokens)
+				}
 				if tokens, ok := usage["output_tokens"].(float64); ok {
 					outputTokens += int64(tokens)
 				}
@@ -521,7 +621,7 @@ func parseContainerEvents(events []map[string]any) (response string, cost float6
 		}
 	}
 
-	return responseBuf.String(), cost, outputTokens, toolCalls
+	return responseBuf.String(), cost, inputTokens, outputTokens, toolCalls
 }
 
 // buildTranscript creates a chronological transcript of agent interactions.
@@ -228,6 +228,12 @@ func TestResultCheckResults(t *testing.T) {
 			wantSuccess:  []string{"handoffs"},
 			wantFailures: []string{"relevance: check A (reason: reason A)", "relevance: check B (reason: reason B)"},
 		},
+		{
+			name:         "assertions listed",
+			result:       Result{HandoffsMatch: true, Assertions: []AssertionResult{{Name: "response matches ^ok", Passed: true}, {Name: "file out.txt", Message: "the file does not exist"}}},
+			wantSuccess:  []string{"handoffs", "response matches ^ok"},
+			wantFailures: []string{"file out.txt: the file does not exist"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -432,6 +438,7 @@ func TestParseContainerEvents(t *testing.T) {
 		events           []map[string]any
 		wantResponse     string
 		wantCost         float64
+		wantInputTokens  int64
 		wantOutputTokens int64
 		wantToolCalls    []string
 	}{
@@ -516,12 +523,14 @@ func TestParseContainerEvents(t *testing.T) {
 					"type": "token_usage",
 					"usage": map[string]any{
 						"cost":          0.01,
+						"input_tokens":  float64(1000),
 						"output_tokens": float64(200),
 					},
 				},
 			},
 			wantResponse:     "Let me help.",
 			wantCost:         0.01,
+			wantInputTokens:  1000,
 			wantOutputTokens: 200,
 			wantToolCalls:    []string{"search"},
 		},
@@ -541,9 +550,10 @@ func TestParseContainerEvents(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			response, cost, outputTokens, toolCalls := parseContainerEvents(tt.events)
+			response, cost, inputTokens, outputTokens, toolCalls := parseContainerEvents(tt.events)
 			assert.Equal(t, tt.wantResponse, response)
 			assert.InDelta(t, tt.wantCost, cost, 0.0001)
+			assert.Equal(t, tt.wantInputTokens, inputTokens)
 			assert.Equal(t, tt.wantOutputTokens, outputTokens)
 			assert.Equal(t, tt.wantToolCalls, toolCalls)
 		})
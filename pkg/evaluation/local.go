@@ -0,0 +1,129 @@
+package evaluation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/cli"
+	"github.com/docker/docker-agent/pkg/runtime"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/teamloader"
+)
+
+// runAgentInProcess runs the agent with a local runtime, in a temporary copy
+// of the eval's working directory, instead of in a container. It returns the
+// same events as runDockerAgentInContainer, along with the content of the
+// given files of the working directory after the run, by path.
+func (r *Runner) runAgentInProcess(ctx context.Context, questions []string, workingDir, setup string, files []string) ([]map[string]any, map[string]string, error) {
+	dir, err := os.MkdirTemp("", "docker-agent-eval-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating working directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := r.prepareWorkingDir(ctx, dir, workingDir, setup); err != nil {
+		return nil, nil, err
+	}
+
+	runConfig := r.runConfig.Clone()
+	runConfig.WorkingDir = dir
+
+	loadResult, err := teamloader.LoadWithConfig(ctx, r.agentSource, runConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading agent: %w", err)
+	}
+	defer func() {
+		stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		if err := loadResult.Team.StopToolSets(stopCtx); err != nil {
+			slog.Warn("Failed to stop tool sets", "error", err)
+		}
+	}()
+
+	rt, err := runtime.NewLocalRuntime(loadResult.Team)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating runtime: %w", err)
+	}
+	defer rt.Close()
+
+	agent := rt.CurrentAgent()
+	sess := session.New(
+		session.WithMaxIterations(agent.MaxIterations()),
+		session.WithToolsApproved(true),
+		session.WithThinking(agent.ThinkingConfigured()),
+		session.WithWorkingDir(dir),
+	)
+
+	// Run the agent the same way the container does, with docker-agent run
+	// --exec --yolo --json, so that both modes produce the same events.
+	var stdout bytes.Buffer
+	runErr := cli.Run(ctx, cli.NewPrinter(&stdout), cli.Config{
+		AutoApprove: true,
+		OutputJSON:  true,
+	}, rt, sess, questions)
+
+	events := parseEventLines(&stdout)
+	if len(events) == 0 {
+		if runErr != nil {
+			return nil, nil, fmt.Errorf("running agent: %w", runErr)
+		}
+		return nil, nil, errors.New("no events received from agent")
+	}
+	if runErr != nil {
+		slog.Debug("Agent exited with error", "error", runErr)
+	}
+
+	fileContents, err := readWorkingDirFiles(dir, files)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return events, fileContents, nil
+}
+
+// prepareWorkingDir fills dir with a copy of the eval's working directory, if
+// any, and runs the setup script in it.
+func (r *Runner) prepareWorkingDir(ctx context.Context, dir, workingDir, setup string) error {
+	if workingDir != "" {
+		src := filepath.Join(r.EvalsDir, "working_dirs", workingDir)
+		if err := os.CopyFS(dir, os.DirFS(src)); err != nil {
+			return fmt.Errorf("copying working directory %s: %w", src, err)
+		}
+	}
+
+	if setup != "" {
+		cmd := exec.CommandContext(ctx, "sh", "-c", setup)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("running setup script: %w (output: %s)", err, strings.TrimSpace(string(output)))
+		}
+	}
+
+	return nil
+}
+
+// readWorkingDirFiles reads files of a working directory. Files that don't
+// exist are left out of the returned map.
+func readWorkingDirFiles(dir string, files []string) (map[string]string, error) {
+	contents := make(map[string]string, len(files))
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file)))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", file, err)
+		}
+		contents[file] = string(content)
+	}
+	return contents, nil
+}
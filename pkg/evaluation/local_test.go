@@ -0,0 +1,50 @@
+package evaluation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepareWorkingDir(t *testing.T) {
+	t.Parallel()
+
+	evalsDir := t.TempDir()
+	src := filepath.Join(evalsDir, "working_dirs", "project")
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "src"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "src", "main.go"), []byte("package main\n"), 0o644))
+
+	r := &Runner{Config: Config{EvalsDir: evalsDir}}
+	dir := t.TempDir()
+	require.NoError(t, r.prepareWorkingDir(t.Context(), dir, "project", "echo hello > hello.txt"))
+
+	files, err := readWorkingDirFiles(dir, []string{"src/main.go", "hello.txt", "missing.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"src/main.go": "package main\n",
+		"hello.txt":   "hello\n",
+	}, files)
+}
+
+func TestPrepareWorkingDirSetupFailure(t *testing.T) {
+	t.Parallel()
+
+	r := &Runner{Config: Config{EvalsDir: t.TempDir()}}
+	err := r.prepareWorkingDir(t.Context(), t.TempDir(), "", "echo broken >&2 && exit 3")
+	require.ErrorContains(t, err, "running setup script")
+	assert.ErrorContains(t, err, "broken")
+}
+
+func TestParseEventLines(t *testing.T) {
+	t.Parallel()
+
+	events := parseEventLines(strings.NewReader("{\"type\":\"agent_choice\",\"content\":\"hi\"}\n\nnot json\n{\"type\":\"stream_stopped\"}\n"))
+	assert.Equal(t, []map[string]any{
+		{"type": "agent_choice", "content": "hi"},
+		{"type": "stream_stopped"},
+	}, events)
+}
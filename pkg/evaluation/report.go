@@ -0,0 +1,116 @@
+package evaluation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReportFormats lists the formats of the reports of eval runs.
+var ReportFormats = []string{"junit", "tap", "json"}
+
+// WriteReport writes an eval run as a report in the given format: junit,
+// tap or json. Each eval is reported as a test case that fails with the
+// failures of its checks.
+func WriteReport(w io.Writer, run *EvalRun, format string) error {
+	switch format {
+	case "junit":
+		return writeJUnitReport(w, run)
+	case "tap":
+		return writeTAPReport(w, run)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(run)
+	default:
+		return fmt.Errorf("unsupported report format %q: expected one of %s", format, strings.Join(ReportFormats, ", "))
+	}
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
+
+func writeJUnitReport(w io.Writer, run *EvalRun) error {
+	suite := junitTestSuite{
+		Name:      run.Name,
+		Tests:     len(run.Results),
+		Time:      seconds(run.Duration),
+		Timestamp: run.Timestamp.UTC().Format(time.RFC3339),
+		Properties: &junitProperties{Properties: []junitProperty{
+			{Name: "total_cost", Value: fmt.Sprintf("%.6f", run.Summary.TotalCost)},
+		}},
+	}
+
+	for i := range run.Results {
+		r := &run.Results[i]
+		tc := junitTestCase{
+			Name:      r.Title,
+			ClassName: resultName(r),
+			Time:      seconds(r.Duration),
+			Properties: &junitProperties{Properties: []junitProperty{
+				{Name: "cost", Value: fmt.Sprintf("%.6f", r.Cost)},
+				{Name: "output_tokens", Value: strconv.FormatInt(r.OutputTokens, 10)},
+			}},
+		}
+
+		_, failures := r.checkResults()
+		switch {
+		case r.Error != "":
+			suite.Errors++
+			tc.Error = &junitFailure{Message: r.Error, Text: r.Error}
+		case len(failures) > 0:
+			suite.Failures++
+			tc.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d check(s) failed", len(failures)),
+				Text:    strings.Join(failures, "\n"),
+			}
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	return writeJUnit(w, junitTestSuites{Suites: []junitTestSuite{suite}})
+}
+
+// writeTAPReport writes a Test Anything Protocol report, with the details of
+// each eval in a YAML block.
+func writeTAPReport(w io.Writer, run *EvalRun) error {
+	var b strings.Builder
+	b.WriteString("TAP version 14\n")
+	fmt.Fprintf(&b, "1..%d\n", len(run.Results))
+
+	for i := range run.Results {
+		r := &run.Results[i]
+		_, failures := r.checkResults()
+
+		status := "ok"
+		if len(failures) > 0 {
+			status = "not ok"
+		}
+		fmt.Fprintf(&b, "%s %d - %s\n", status, i+1, tapDescription(r.Title))
+
+		b.WriteString("  ---\n")
+		if len(failures) > 0 {
+			b.WriteString("  failures:\n")
+			for _, failure := range failures {
+				fmt.Fprintf(&b, "    - %s\n", strconv.Quote(failure))
+			}
+		}
+		fmt.Fprintf(&b, "  cost: %.6f\n", r.Cost)
+		fmt.Fprintf(&b, "  output_tokens: %d\n", r.OutputTokens)
+		fmt.Fprintf(&b, "  duration_ms: %d\n", r.Duration.Milliseconds())
+		b.WriteString("  ...\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// tapDescription escapes a test description: # starts a directive in TAP.
+func tapDescription(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	return strings.ReplaceAll(s, "#", `\#`)
+}
@@ -0,0 +1,114 @@
+package evaluation
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reportRun() *EvalRun {
+	return &EvalRun{
+		Name:      "happy-panda-1234",
+		Timestamp: time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC),
+		Duration:  90 * time.Second,
+		Results: []Result{
+			{InputPath: "evals/ok.json", Title: "Counting files", HandoffsMatch: true, Cost: 0.0012, OutputTokens: 120, Duration: 1500 * time.Millisecond},
+			{InputPath: "evals/fail.json", Title: "Issue #42", HandoffsMatch: true, SizeExpected: "S", Size: "L", Cost: 0.002, OutputTokens: 300, Duration: 2 * time.Second},
+			{InputPath: "evals/error.json", Title: "Broken", Error: "container failed"},
+		},
+		Summary: Summary{TotalCost: 0.0032},
+	}
+}
+
+func TestWriteReportJUnit(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteReport(&buf, reportRun(), "junit"))
+
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="happy-panda-1234" tests="3" failures="1" errors="1" time="90.000" timestamp="2026-01-02T15:04:05Z">
+    <properties>
+      <property name="total_cost" value="0.003200"></property>
+    </properties>
+    <testcase name="Counting files" classname="ok.json" time="1.500">
+      <properties>
+        <property name="cost" value="0.001200"></property>
+        <property name="output_tokens" value="120"></property>
+      </properties>
+    </testcase>
+    <testcase name="Issue #42" classname="fail.json" time="2.000">
+      <properties>
+        <property name="cost" value="0.002000"></property>
+        <property name="output_tokens" value="300"></property>
+      </properties>
+      <failure message="1 check(s) failed">size expected S, got L</failure>
+    </testcase>
+    <testcase name="Broken" classname="error.json" time="0.000">
+      <properties>
+        <property name="cost" value="0.000000"></property>
+        <property name="output_tokens" value="0"></property>
+      </properties>
+      <error message="container failed">container failed</error>
+    </testcase>
+  </testsuite>
+</testsuites>
+`, buf.String())
+}
+
+func TestWriteReportTAP(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteReport(&buf, reportRun(), "tap"))
+
+	assert.Equal(t, `TAP version 14
+1..3
+ok 1 - Counting files
+  ---
+  cost: 0.001200
+  output_tokens: 120
+  duration_ms: 1500
+  ...
+not ok 2 - Issue \#42
+  ---
+  failures:
+    - "size expected S, got L"
+  cost: 0.002000
+  output_tokens: 300
+  duration_ms: 2000
+  ...
+not ok 3 - Broken
+  ---
+  failures:
+    - "container failed"
+  cost: 0.000000
+  output_tokens: 0
+  duration_ms: 0
+  ...
+`, buf.String())
+}
+
+func TestWriteReportJSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteReport(&buf, reportRun(), "json"))
+
+	var run EvalRun
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &run))
+	assert.Equal(t, "happy-panda-1234", run.Name)
+	assert.Len(t, run.Results, 3)
+	assert.Equal(t, 1500*time.Millisecond, run.Results[0].Duration)
+}
+
+func TestWriteReportUnsupportedFormat(t *testing.T) {
+	t.Parallel()
+
+	require.ErrorContains(t, WriteReport(&bytes.Buffer{}, reportRun(), "html"), `unsupported report format "html"`)
+}
@@ -341,7 +341,7 @@ func parseEventTimestamp(event map[string]any) string {
 }
 
 // SaveRunJSON saves the eval run results to a JSON file.
-// This is kept for backward compatibility and debugging purposes.
+// Runs saved this way can be compared with [Compare].
 func SaveRunJSON(run *EvalRun, outputDir string) (string, error) {
 	return saveJSON(run, filepath.Join(outputDir, run.Name+".json"))
 }
@@ -359,7 +359,7 @@ func SaveRunSessionsJSON(run *EvalRun, outputDir string) (string, error) {
 		}
 	}
 
-	outputPath := filepath.Join(outputDir, run.Name+".json")
+	outputPath := filepath.Join(outputDir, run.Name+"-sessions.json")
 	return saveJSON(sessions, outputPath)
 }
 
@@ -169,7 +169,7 @@ func TestSaveRunSessionsJSON(t *testing.T) {
 	// Save sessions to JSON
 	sessionsPath, err := SaveRunSessionsJSON(run, outputDir)
 	require.NoError(t, err)
-	assert.Equal(t, filepath.Join(outputDir, "test-json-001.json"), sessionsPath)
+	assert.Equal(t, filepath.Join(outputDir, "test-json-001-sessions.json"), sessionsPath)
 	assert.FileExists(t, sessionsPath)
 
 	// Read and parse the JSON file
@@ -103,6 +103,13 @@ func computeSummary(results []Result) Summary {
 
 		summary.RelevanceTotal += r.RelevanceExpected
 		summary.RelevancePassed += r.RelevancePassed
+
+		summary.AssertionsTotal += len(r.Assertions)
+		for _, assertion := range r.Assertions {
+			if assertion.Passed {
+				summary.AssertionsPassed++
+			}
+		}
 	}
 
 	return summary
@@ -120,6 +127,7 @@ func printSummary(out io.Writer, summary Summary, duration time.Duration) {
 	printF1Score(out, "Tool Calls", summary.ToolsF1Sum, summary.ToolsCount)
 	printMetric(out, "Handoffs", summary.HandoffsPassed, summary.HandoffsTotal)
 	printMetric(out, "Relevance", int(summary.RelevancePassed), int(summary.RelevanceTotal))
+	printMetric(out, "Assertions", summary.AssertionsPassed, summary.AssertionsTotal)
 
 	fmt.Fprintf(out, "\nTotal Cost: $%.6f\n", summary.TotalCost)
 	fmt.Fprintf(out, "Total Time: %s\n", duration.Round(time.Second))
@@ -20,7 +20,9 @@ type Result struct {
 	Question          string            `json:"question"`
 	Response          string            `json:"response"`
 	Cost              float64           `json:"cost"`
+	InputTokens       int64             `json:"input_tokens"`
 	OutputTokens      int64             `json:"output_tokens"`
+	Duration          time.Duration     `json:"duration"`
 	Size              string            `json:"size"`
 	SizeExpected      string            `json:"size_expected"`
 	ToolCallsScore    float64           `json:"tool_calls_score"`
@@ -29,6 +31,7 @@ type Result struct {
 	RelevancePassed   float64           `json:"relevance"`
 	RelevanceExpected float64           `json:"relevance_expected"`
 	FailedRelevance   []RelevanceResult `json:"failed_relevance,omitempty"`
+	Assertions        []AssertionResult `json:"assertions,omitempty"`
 	Error             string            `json:"error,omitempty"`
 	RawOutput         []map[string]any  `json:"raw_output,omitempty"`
 	Session           *session.Session  `json:"-"` // Full session for database storage (not in JSON)
@@ -80,22 +83,33 @@ func (r *Result) checkResults() (successes, failures []string) {
 		}
 	}
 
+	// Check deterministic assertions
+	for _, assertion := range r.Assertions {
+		if assertion.Passed {
+			successes = append(successes, assertion.Name)
+		} else {
+			failures = append(failures, fmt.Sprintf("%s: %s", assertion.Name, assertion.Message))
+		}
+	}
+
 	return successes, failures
 }
 
 // Summary contains aggregate statistics across all evaluations.
 type Summary struct {
-	TotalEvals      int     `json:"total_evals"`
-	FailedEvals     int     `json:"failed_evals"`
-	TotalCost       float64 `json:"total_cost"`
-	SizesPassed     int     `json:"sizes_passed"`
-	SizesTotal      int     `json:"sizes_total"`
-	ToolsF1Sum      float64 `json:"tools_f1_sum"`
-	ToolsCount      int     `json:"tools_count"`
-	HandoffsPassed  int     `json:"handoffs_passed"`
-	HandoffsTotal   int     `json:"handoffs_total"`
-	RelevancePassed float64 `json:"relevance_passed"`
-	RelevanceTotal  float64 `json:"relevance_total"`
+	TotalEvals       int     `json:"total_evals"`
+	FailedEvals      int     `json:"failed_evals"`
+	TotalCost        float64 `json:"total_cost"`
+	SizesPassed      int     `json:"sizes_passed"`
+	SizesTotal       int     `json:"sizes_total"`
+	ToolsF1Sum       float64 `json:"tools_f1_sum"`
+	ToolsCount       int     `json:"tools_count"`
+	HandoffsPassed   int     `json:"handoffs_passed"`
+	HandoffsTotal    int     `json:"handoffs_total"`
+	RelevancePassed  float64 `json:"relevance_passed"`
+	RelevanceTotal   float64 `json:"relevance_total"`
+	AssertionsPassed int     `json:"assertions_passed"`
+	AssertionsTotal  int     `json:"assertions_total"`
 }
 
 // EvalRun contains the results and metadata for an evaluation run.
@@ -118,6 +132,7 @@ type Config struct {
 	BaseImage      string   // Custom base Docker image for running evaluations
 	KeepContainers bool     // If true, don't remove containers after evaluation (skip --rm)
 	EnvVars        []string // Environment variables to pass: KEY (value from env) or KEY=VALUE (explicit)
+	Local          bool     // If true, run the agent in process in a temporary directory instead of in a container
 }
 
 // Session helper functions
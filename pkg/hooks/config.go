@@ -19,11 +19,7 @@ func FromConfig(cfg *latest.HooksConfig) *Config {
 			Hooks:   make([]Hook, 0, len(matcher.Hooks)),
 		}
 		for _, h := range matcher.Hooks {
-			mc.Hooks = append(mc.Hooks, Hook{
-				Type:    HookType(h.Type),
-				Command: h.Command,
-				Timeout: h.Timeout,
-			})
+			mc.Hooks = append(mc.Hooks, fromDefinition(h))
 		}
 		result.PreToolUse = append(result.PreToolUse, mc)
 	}
@@ -35,41 +31,55 @@ func FromConfig(cfg *latest.HooksConfig) *Config {
 			Hooks:   make([]Hook, 0, len(matcher.Hooks)),
 		}
 		for _, h := range matcher.Hooks {
-			mc.Hooks = append(mc.Hooks, Hook{
-				Type:    HookType(h.Type),
-				Command: h.Command,
-				Timeout: h.Timeout,
-			})
+			mc.Hooks = append(mc.Hooks, fromDefinition(h))
 		}
 		result.PostToolUse = append(result.PostToolUse, mc)
 	}
 
 	// Convert SessionStart
 	for _, h := range cfg.SessionStart {
-		result.SessionStart = append(result.SessionStart, Hook{
-			Type:    HookType(h.Type),
-			Command: h.Command,
-			Timeout: h.Timeout,
-		})
+		result.SessionStart = append(result.SessionStart, fromDefinition(h))
 	}
 
 	// Convert SessionEnd
 	for _, h := range cfg.SessionEnd {
-		result.SessionEnd = append(result.SessionEnd, Hook{
-			Type:    HookType(h.Type),
-			Command: h.Command,
-			Timeout: h.Timeout,
-		})
+		result.SessionEnd = append(result.SessionEnd, fromDefinition(h))
 	}
 
 	// Convert OnUserInput
 	for _, h := range cfg.OnUserInput {
-		result.OnUserInput = append(result.OnUserInput, Hook{
-			Type:    HookType(h.Type),
-			Command: h.Command,
-			Timeout: h.Timeout,
-		})
+		result.OnUserInput = append(result.OnUserInput, fromDefinition(h))
 	}
 
+	// Convert model call, compaction and delegation hooks
+	result.PreModelCall = fromDefinitions(cfg.PreModelCall)
+	result.PostModelCall = fromDefinitions(cfg.PostModelCall)
+	result.PreCompaction = fromDefinitions(cfg.PreCompaction)
+	result.PostCompaction = fromDefinitions(cfg.PostCompaction)
+	result.PreDelegation = fromDefinitions(cfg.PreDelegation)
+
+	return result
+}
+
+// fromDefinitions converts a list of latest.HookDefinition to hooks
+func fromDefinitions(defs []latest.HookDefinition) []Hook {
+	var result []Hook
+	for _, h := range defs {
+		result = append(result, fromDefinition(h))
+	}
 	return result
 }
+
+// fromDefinition converts a latest.HookDefinition to a Hook
+func fromDefinition(h latest.HookDefinition) Hook {
+	return Hook{
+		Type:    HookType(h.Type),
+		Command: h.Command,
+		URL:     h.URL,
+		Headers: h.Headers,
+		Retries: h.Retries,
+		Prompt:  h.Prompt,
+		Model:   h.Model,
+		Timeout: h.Timeout,
+	}
+}
@@ -26,11 +26,24 @@ type Executor struct {
 	shell           string
 	shellArgsPrefix []string
 
+	// resolveModel returns the model of prompt hooks
+	resolveModel ModelResolver
+
 	// Cached compiled regexes
 	preToolUseMatchers  []compiledMatcher
 	postToolUseMatchers []compiledMatcher
 }
 
+// Opt configures an Executor
+type Opt func(*Executor)
+
+// WithModelResolver sets how prompt hooks get their model
+func WithModelResolver(resolve ModelResolver) Opt {
+	return func(e *Executor) {
+		e.resolveModel = resolve
+	}
+}
+
 type compiledMatcher struct {
 	config  MatcherConfig
 	pattern *regexp.Regexp
@@ -43,10 +56,12 @@ type hookResult struct {
 	stderr   string
 	exitCode int
 	err      error
+	// failClosed is set for hooks whose errors block the operation
+	failClosed bool
 }
 
 // NewExecutor creates a new hook executor
-func NewExecutor(config *Config, workingDir string, env []string) *Executor {
+func NewExecutor(config *Config, workingDir string, env []string, opts ...Opt) *Executor {
 	if config == nil {
 		config = &Config{}
 	}
@@ -56,6 +71,9 @@ func NewExecutor(config *Config, workingDir string, env []string) *Executor {
 		workingDir: workingDir,
 		env:        env,
 	}
+	for _, opt := range opts {
+		opt(e)
+	}
 
 	e.initShell()
 	e.compileMatchers()
@@ -188,13 +206,49 @@ func (e *Executor) ExecuteOnUserInput(ctx context.Context, input *Input) (*Resul
 	return e.executeHooks(ctx, e.config.OnUserInput, input, EventOnUserInput)
 }
 
+// ExecutePreModelCall runs pre-model-call hooks
+func (e *Executor) ExecutePreModelCall(ctx context.Context, input *Input) (*Result, error) {
+	return e.executeEvent(ctx, e.config.PreModelCall, input, EventPreModelCall)
+}
+
+// ExecutePostModelCall runs post-model-call hooks
+func (e *Executor) ExecutePostModelCall(ctx context.Context, input *Input) (*Result, error) {
+	return e.executeEvent(ctx, e.config.PostModelCall, input, EventPostModelCall)
+}
+
+// ExecutePreCompaction runs pre-compaction hooks
+func (e *Executor) ExecutePreCompaction(ctx context.Context, input *Input) (*Result, error) {
+	return e.executeEvent(ctx, e.config.PreCompaction, input, EventPreCompaction)
+}
+
+// ExecutePostCompaction runs post-compaction hooks
+func (e *Executor) ExecutePostCompaction(ctx context.Context, input *Input) (*Result, error) {
+	return e.executeEvent(ctx, e.config.PostCompaction, input, EventPostCompaction)
+}
+
+// ExecutePreDelegation runs pre-delegation hooks
+func (e *Executor) ExecutePreDelegation(ctx context.Context, input *Input) (*Result, error) {
+	return e.executeEvent(ctx, e.config.PreDelegation, input, EventPreDelegation)
+}
+
+// executeEvent runs the hooks of an event that has no matcher
+func (e *Executor) executeEvent(ctx context.Context, hooks []Hook, input *Input, eventType EventType) (*Result, error) {
+	if len(hooks) == 0 {
+		return &Result{Allowed: true}, nil
+	}
+
+	input.HookEventName = eventType
+
+	return e.executeHooks(ctx, hooks, input, eventType)
+}
+
 // executeHooks runs a list of hooks in parallel and aggregates results
 func (e *Executor) executeHooks(ctx context.Context, hooks []Hook, input *Input, eventType EventType) (*Result, error) {
-	// Deduplicate hooks by command
+	// Deduplicate hooks by command, URL or prompt
 	seen := make(map[string]bool)
 	var uniqueHooks []Hook
 	for _, h := range hooks {
-		key := fmt.Sprintf("%s:%s", h.Type, h.Command)
+		key := fmt.Sprintf("%s:%s:%s:%s:%s", h.Type, h.Command, h.URL, h.Model, h.Prompt)
 		if !seen[key] {
 			seen[key] = true
 			uniqueHooks = append(uniqueHooks, h)
@@ -217,13 +271,15 @@ func (e *Executor) executeHooks(ctx context.Context, hooks []Hook, input *Input,
 
 	for i, hook := range uniqueHooks {
 		wg.Go(func() {
-			output, stdout, stderr, exitCode, err := e.executeHook(ctx, hook, inputJSON)
+			output, stdout, stderr, exitCode, err := e.executeHook(ctx, hook, input, inputJSON)
 			results[i] = hookResult{
 				output:   output,
 				stdout:   stdout,
 				stderr:   stderr,
 				exitCode: exitCode,
 				err:      err,
+				// A policy that can't be evaluated must not let the call through
+				failClosed: hook.Type == HookTypePrompt || (hook.Type == HookTypeWebhook && eventType == EventPreToolUse),
 			}
 		})
 	}
@@ -235,11 +291,21 @@ func (e *Executor) executeHooks(ctx context.Context, hooks []Hook, input *Input,
 }
 
 // executeHook runs a single hook and returns its output
-func (e *Executor) executeHook(ctx context.Context, hook Hook, inputJSON []byte) (*Output, string, string, int, error) {
-	if hook.Type != HookTypeCommand {
+func (e *Executor) executeHook(ctx context.Context, hook Hook, input *Input, inputJSON []byte) (*Output, string, string, int, error) {
+	switch hook.Type {
+	case HookTypeCommand:
+		return e.executeCommand(ctx, hook, inputJSON)
+	case HookTypeWebhook:
+		return e.executeWebhook(ctx, hook, inputJSON)
+	case HookTypePrompt:
+		return e.executePrompt(ctx, hook, input)
+	default:
 		return nil, "", "", 0, fmt.Errorf("unsupported hook type: %s", hook.Type)
 	}
+}
 
+// executeCommand runs a command hook with the input on its stdin
+func (e *Executor) executeCommand(ctx context.Context, hook Hook, inputJSON []byte) (*Output, string, string, int, error) {
 	// Create timeout context
 	timeoutCtx, cancel := context.WithTimeout(ctx, hook.GetTimeout())
 	defer cancel()
@@ -270,20 +336,26 @@ func (e *Executor) executeHook(ctx context.Context, hook Hook, inputJSON []byte)
 
 	// Parse output if exit code is 0
 	var output *Output
-	if exitCode == 0 && stdout.Len() > 0 {
-		stdoutStr := strings.TrimSpace(stdout.String())
-		// Try to parse as JSON
-		if strings.HasPrefix(stdoutStr, "{") {
-			var parsed Output
-			if err := json.Unmarshal([]byte(stdoutStr), &parsed); err == nil {
-				output = &parsed
-			}
-		}
+	if exitCode == 0 {
+		output = parseOutput(stdout.String())
 	}
 
 	return output, stdout.String(), stderr.String(), exitCode, nil
 }
 
+// parseOutput parses the JSON output of a hook, or returns nil for plain text
+func parseOutput(raw string) *Output {
+	raw = strings.TrimSpace(raw)
+	if !strings.HasPrefix(raw, "{") {
+		return nil
+	}
+	var parsed Output
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		return nil
+	}
+	return &parsed
+}
+
 // aggregateResults combines results from multiple hooks
 func (e *Executor) aggregateResults(results []hookResult, eventType EventType) (*Result, error) {
 	finalResult := &Result{
@@ -291,12 +363,17 @@ func (e *Executor) aggregateResults(results []hookResult, eventType EventType) (
 	}
 
 	var messages []string
+	var askMessages []string
 	var additionalContexts []string
 	var systemMessages []string
 
 	for _, r := range results {
 		if r.err != nil {
-			slog.Warn("Hook execution error", "error", r.err)
+			slog.Warn("Hook execution error", "error", r.err, "fail_closed", r.failClosed)
+			if r.failClosed {
+				finalResult.Allowed = false
+				messages = append(messages, r.err.Error())
+			}
 			continue
 		}
 
@@ -353,8 +430,10 @@ func (e *Executor) aggregateResults(results []hookResult, eventType EventType) (
 							messages = append(messages, hso.PermissionDecisionReason)
 						}
 					case DecisionAsk:
-						// Ask leaves it up to the normal approval flow
-						// Don't change Allowed
+						finalResult.Ask = true
+						if hso.PermissionDecisionReason != "" {
+							askMessages = append(askMessages, hso.PermissionDecisionReason)
+						}
 					}
 
 					// Merge updated input
@@ -373,15 +452,17 @@ func (e *Executor) aggregateResults(results []hookResult, eventType EventType) (
 			}
 		} else if r.stdout != "" {
 			// Plain text stdout is added as context for some events
-			if eventType == EventSessionStart || eventType == EventPostToolUse {
+			if acceptsPlainContext(eventType) {
 				additionalContexts = append(additionalContexts, strings.TrimSpace(r.stdout))
 			}
 		}
 	}
 
-	// Combine messages
+	// Combine messages. The reasons to ask only matter when no hook blocked.
 	if len(messages) > 0 {
 		finalResult.Message = strings.Join(messages, "\n")
+	} else if finalResult.Ask && len(askMessages) > 0 {
+		finalResult.Message = strings.Join(askMessages, "\n")
 	}
 	if len(additionalContexts) > 0 {
 		finalResult.AdditionalContext = strings.Join(additionalContexts, "\n")
@@ -393,6 +474,16 @@ func (e *Executor) aggregateResults(results []hookResult, eventType EventType) (
 	return finalResult, nil
 }
 
+// acceptsPlainContext returns whether the plain text output of a hook is added as context for an event
+func acceptsPlainContext(eventType EventType) bool {
+	switch eventType {
+	case EventSessionStart, EventPostToolUse, EventPreModelCall, EventPreCompaction, EventPostCompaction, EventPreDelegation:
+		return true
+	default:
+		return false
+	}
+}
+
 // HasPreToolUseHooks returns true if there are any pre-tool-use hooks configured
 func (e *Executor) HasPreToolUseHooks() bool {
 	return e.config != nil && len(e.preToolUseMatchers) > 0
@@ -417,3 +508,28 @@ func (e *Executor) HasSessionEndHooks() bool {
 func (e *Executor) HasOnUserInputHooks() bool {
 	return e.config != nil && len(e.config.OnUserInput) > 0
 }
+
+// HasPreModelCallHooks returns true if there are any pre-model-call hooks configured
+func (e *Executor) HasPreModelCallHooks() bool {
+	return e.config != nil && len(e.config.PreModelCall) > 0
+}
+
+// HasPostModelCallHooks returns true if there are any post-model-call hooks configured
+func (e *Executor) HasPostModelCallHooks() bool {
+	return e.config != nil && len(e.config.PostModelCall) > 0
+}
+
+// HasPreCompactionHooks returns true if there are any pre-compaction hooks configured
+func (e *Executor) HasPreCompactionHooks() bool {
+	return e.config != nil && len(e.config.PreCompaction) > 0
+}
+
+// HasPostCompactionHooks returns true if there are any post-compaction hooks configured
+func (e *Executor) HasPostCompactionHooks() bool {
+	return e.config != nil && len(e.config.PostCompaction) > 0
+}
+
+// HasPreDelegationHooks returns true if there are any pre-delegation hooks configured
+func (e *Executor) HasPreDelegationHooks() bool {
+	return e.config != nil && len(e.config.PreDelegation) > 0
+}
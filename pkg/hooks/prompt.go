@@ -0,0 +1,131 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/model/provider"
+	"github.com/docker/docker-agent/pkg/model/provider/options"
+)
+
+// ModelResolver returns the model for a model reference of a prompt hook.
+// An empty reference stands for the agent's own model.
+type ModelResolver func(ctx context.Context, modelRef string) (provider.Provider, error)
+
+// promptHookInstruction is the system prompt of the model evaluating a prompt hook.
+const promptHookInstruction = `You are a security policy checker for the tool calls of an AI agent.
+You receive a policy and a tool call the agent is about to make.
+Decide whether the tool call complies with the policy:
+- "allow" if it complies;
+- "deny" if it violates the policy;
+- "ask" if it is unclear and a human should confirm it.
+Explain your decision in one short sentence.`
+
+// promptHookSchema is the structured output of the model evaluating a prompt hook.
+var promptHookSchema = &latest.StructuredOutput{
+	Name:        "tool_call_decision",
+	Description: "Decision on whether a tool call complies with a policy",
+	Schema: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"decision": map[string]any{
+				"type":        "string",
+				"enum":        []string{string(DecisionAllow), string(DecisionDeny), string(DecisionAsk)},
+				"description": "Whether the tool call may run",
+			},
+			"reason": map[string]any{
+				"type":        "string",
+				"description": "Brief explanation of the decision",
+			},
+		},
+		"required":             []string{"decision", "reason"},
+		"additionalProperties": false,
+	},
+	Strict: true,
+}
+
+// promptVerdict is the structured response of the model evaluating a prompt hook.
+type promptVerdict struct {
+	Decision Decision `json:"decision"`
+	Reason   string   `json:"reason"`
+}
+
+// executePrompt asks a model whether a tool call complies with the policy of
+// a prompt hook. The verdict becomes the permission decision of the hook.
+func (e *Executor) executePrompt(ctx context.Context, hook Hook, input *Input) (*Output, string, string, int, error) {
+	if e.resolveModel == nil {
+		return nil, "", "", -1, errors.New("prompt hooks are not supported without a model")
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, hook.GetTimeout())
+	defer cancel()
+
+	model, err := e.resolveModel(timeoutCtx, hook.Model)
+	if err != nil {
+		return nil, "", "", -1, fmt.Errorf("resolving model of prompt hook: %w", err)
+	}
+	model = provider.CloneWithOptions(timeoutCtx, model, options.WithStructuredOutput(promptHookSchema))
+
+	toolInput, err := json.MarshalIndent(input.ToolInput, "", "  ")
+	if err != nil {
+		return nil, "", "", -1, fmt.Errorf("serializing tool input: %w", err)
+	}
+	messages := []chat.Message{
+		{Role: chat.MessageRoleSystem, Content: promptHookInstruction},
+		{Role: chat.MessageRoleUser, Content: fmt.Sprintf("Policy:\n<policy>\n%s\n</policy>\n\nTool: %s\nArguments:\n<arguments>\n%s\n</arguments>", hook.Prompt, input.ToolName, toolInput)},
+	}
+
+	raw, err := complete(timeoutCtx, model, messages)
+	if err != nil {
+		return nil, "", "", -1, fmt.Errorf("evaluating prompt hook: %w", err)
+	}
+
+	var verdict promptVerdict
+	if err := json.Unmarshal([]byte(raw), &verdict); err != nil {
+		return nil, raw, "", -1, fmt.Errorf("parsing prompt hook verdict: %w", err)
+	}
+	switch verdict.Decision {
+	case DecisionAllow, DecisionDeny, DecisionAsk:
+	default:
+		return nil, raw, "", -1, fmt.Errorf("unexpected prompt hook decision %q", verdict.Decision)
+	}
+
+	return &Output{
+		HookSpecificOutput: &HookSpecificOutput{
+			HookEventName:            input.HookEventName,
+			PermissionDecision:       verdict.Decision,
+			PermissionDecisionReason: verdict.Reason,
+		},
+	}, raw, "", 0, nil
+}
+
+// complete runs a chat completion and returns the text of the response
+func complete(ctx context.Context, model provider.Provider, messages []chat.Message) (string, error) {
+	stream, err := model.CreateChatCompletionStream(ctx, messages, nil)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	var content strings.Builder
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		for _, choice := range resp.Choices {
+			content.WriteString(choice.Delta.Content)
+		}
+	}
+
+	return strings.TrimSpace(content.String()), nil
+}
@@ -0,0 +1,151 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/model/provider"
+	"github.com/docker/docker-agent/pkg/model/provider/base"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// fakeModel answers every chat completion with a fixed response.
+type fakeModel struct {
+	response string
+	messages []chat.Message
+}
+
+func (m *fakeModel) ID() string { return "fake/model" }
+
+func (m *fakeModel) CreateChatCompletionStream(_ context.Context, messages []chat.Message, _ []tools.Tool) (chat.MessageStream, error) {
+	m.messages = messages
+	return &fakeStream{response: m.response}, nil
+}
+
+func (m *fakeModel) BaseConfig() base.Config { return base.Config{} }
+
+type fakeStream struct {
+	response string
+	done     bool
+}
+
+func (s *fakeStream) Recv() (chat.MessageStreamResponse, error) {
+	if s.done {
+		return chat.MessageStreamResponse{}, io.EOF
+	}
+	s.done = true
+	return chat.MessageStreamResponse{
+		Choices: []chat.MessageStreamChoice{{Delta: chat.MessageDelta{Content: s.response}}},
+	}, nil
+}
+
+func (s *fakeStream) Close() {}
+
+func promptExecutor(t *testing.T, model provider.Provider, modelRef *string) *Executor {
+	t.Helper()
+
+	config := webhookConfig(Hook{
+		Type:   HookTypePrompt,
+		Prompt: "Deny shell commands that push to main",
+		Model:  "openai/gpt-4o-mini",
+	})
+	return NewExecutor(config, t.TempDir(), nil, WithModelResolver(func(_ context.Context, ref string) (provider.Provider, error) {
+		if modelRef != nil {
+			*modelRef = ref
+		}
+		return model, nil
+	}))
+}
+
+func TestPromptHook(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response string
+		allowed  bool
+		ask      bool
+		message  string
+	}{
+		{
+			name:     "allow",
+			response: `{"decision":"allow","reason":"Read-only command"}`,
+			allowed:  true,
+		},
+		{
+			name:     "deny",
+			response: `{"decision":"deny","reason":"Pushes to main"}`,
+			allowed:  false,
+			message:  "Pushes to main",
+		},
+		{
+			name:     "ask",
+			response: `{"decision":"ask","reason":"Unclear target branch"}`,
+			allowed:  true,
+			ask:      true,
+			message:  "Unclear target branch",
+		},
+		{
+			name:     "invalid verdict blocks",
+			response: `not json`,
+			allowed:  false,
+			message:  "parsing prompt hook verdict: invalid character 'o' in literal null (expecting 'u')",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := &fakeModel{response: tt.response}
+			var modelRef string
+			exec := promptExecutor(t, model, &modelRef)
+
+			result, err := exec.ExecutePreToolUse(t.Context(), &Input{
+				ToolName:  "shell",
+				ToolInput: map[string]any{"cmd": "git push origin main"},
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.allowed, result.Allowed)
+			assert.Equal(t, tt.ask, result.Ask)
+			assert.Equal(t, tt.message, result.Message)
+			assert.Equal(t, "openai/gpt-4o-mini", modelRef)
+
+			require.Len(t, model.messages, 2)
+			assert.Contains(t, model.messages[1].Content, "Deny shell commands that push to main")
+			assert.Contains(t, model.messages[1].Content, "Tool: shell")
+			assert.Contains(t, model.messages[1].Content, "git push origin main")
+		})
+	}
+}
+
+func TestPromptHookWithoutModel(t *testing.T) {
+	t.Parallel()
+
+	exec := NewExecutor(webhookConfig(Hook{Type: HookTypePrompt, Prompt: "Deny everything"}), t.TempDir(), nil)
+
+	result, err := exec.ExecutePreToolUse(t.Context(), &Input{ToolName: "shell"})
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, "prompt hooks are not supported without a model", result.Message)
+}
+
+func TestPromptHookModelError(t *testing.T) {
+	t.Parallel()
+
+	exec := NewExecutor(webhookConfig(Hook{Type: HookTypePrompt, Prompt: "Deny everything"}), t.TempDir(), nil,
+		WithModelResolver(func(context.Context, string) (provider.Provider, error) {
+			return nil, errors.New("unknown model")
+		}))
+
+	result, err := exec.ExecutePreToolUse(t.Context(), &Input{ToolName: "shell"})
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, "resolving model of prompt hook: unknown model", result.Message)
+}
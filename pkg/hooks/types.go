@@ -1,5 +1,5 @@
 // Package hooks provides lifecycle hooks for agent tool execution.
-// Hooks allow users to run shell commands or prompts at various points
+// Hooks allow users to run shell commands, call webhooks or ask a model at various points
 // during the agent's execution lifecycle, providing deterministic control
 // over agent behavior.
 package hooks
@@ -32,6 +32,27 @@ const (
 	// OnUserInput is triggered when the agent needs input from the user.
 	// Can log, notify, or perform actions before user interaction.
 	EventOnUserInput EventType = "on_user_input"
+
+	// PreModelCall is triggered before each model request.
+	// Can add context to the request, or stop the agent (e.g. to cap its cost).
+	EventPreModelCall EventType = "pre_model_call"
+
+	// PostModelCall is triggered after each model response, with its usage and cost.
+	// Can stop the agent before it runs the tools the model called.
+	EventPostModelCall EventType = "post_model_call"
+
+	// PreCompaction is triggered before the session is summarized.
+	// Can add instructions for the summary, or skip the compaction.
+	EventPreCompaction EventType = "pre_compaction"
+
+	// PostCompaction is triggered after the session was summarized.
+	// Can add context to the summary.
+	EventPostCompaction EventType = "post_compaction"
+
+	// PreDelegation is triggered before an agent transfers a task to a
+	// sub-agent or hands the conversation off to another agent.
+	// Can add context for the other agent, or block the delegation.
+	EventPreDelegation EventType = "pre_delegation"
 )
 
 // HookType represents the type of hook action
@@ -40,16 +61,39 @@ type HookType string
 const (
 	// HookTypeCommand executes a shell command
 	HookTypeCommand HookType = "command"
+
+	// HookTypeWebhook POSTs the hook input to a URL
+	HookTypeWebhook HookType = "webhook"
+
+	// HookTypePrompt asks a model to decide on a tool call following a policy prompt
+	HookTypePrompt HookType = "prompt"
 )
 
 // Hook represents a single hook configuration
 type Hook struct {
-	// Type specifies whether this is a command or prompt hook
+	// Type specifies whether this is a command, webhook or prompt hook
 	Type HookType `json:"type" yaml:"type"`
 
 	// Command is the shell command to execute (for command hooks)
 	Command string `json:"command,omitempty" yaml:"command,omitempty"`
 
+	// URL is the endpoint the input is POSTed to (for webhook hooks)
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// Headers are added to the webhook request. Values can reference
+	// environment variables with $VAR or ${VAR}.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// Retries is the number of times a failed webhook request is retried (default: 0)
+	Retries int `json:"retries,omitempty" yaml:"retries,omitempty"`
+
+	// Prompt is the policy the model checks the tool call against (for prompt hooks)
+	Prompt string `json:"prompt,omitempty" yaml:"prompt,omitempty"`
+
+	// Model is the model that evaluates the prompt, either a model name from
+	// the config or a "provider/model" reference. It defaults to the agent's model.
+	Model string `json:"model,omitempty" yaml:"model,omitempty"`
+
 	// Timeout is the execution timeout in seconds (default: 60)
 	Timeout int `json:"timeout,omitempty" yaml:"timeout,omitempty"`
 }
@@ -88,6 +132,21 @@ type Config struct {
 
 	// OnUserInput hooks run when the agent needs user input
 	OnUserInput []Hook `json:"on_user_input,omitempty" yaml:"on_user_input,omitempty"`
+
+	// PreModelCall hooks run before each model request
+	PreModelCall []Hook `json:"pre_model_call,omitempty" yaml:"pre_model_call,omitempty"`
+
+	// PostModelCall hooks run after each model response
+	PostModelCall []Hook `json:"post_model_call,omitempty" yaml:"post_model_call,omitempty"`
+
+	// PreCompaction hooks run before the session is summarized
+	PreCompaction []Hook `json:"pre_compaction,omitempty" yaml:"pre_compaction,omitempty"`
+
+	// PostCompaction hooks run after the session was summarized
+	PostCompaction []Hook `json:"post_compaction,omitempty" yaml:"post_compaction,omitempty"`
+
+	// PreDelegation hooks run before a transfer_task or a handoff
+	PreDelegation []Hook `json:"pre_delegation,omitempty" yaml:"pre_delegation,omitempty"`
 }
 
 // IsEmpty returns true if no hooks are configured
@@ -96,10 +155,15 @@ func (c *Config) IsEmpty() bool {
 		len(c.PostToolUse) == 0 &&
 		len(c.SessionStart) == 0 &&
 		len(c.SessionEnd) == 0 &&
-		len(c.OnUserInput) == 0
+		len(c.OnUserInput) == 0 &&
+		len(c.PreModelCall) == 0 &&
+		len(c.PostModelCall) == 0 &&
+		len(c.PreCompaction) == 0 &&
+		len(c.PostCompaction) == 0 &&
+		len(c.PreDelegation) == 0
 }
 
-// Input represents the JSON input passed to hooks via stdin
+// Input represents the JSON input passed to hooks via stdin, or as the body of webhook requests
 type Input struct {
 	// Common fields for all hooks
 	SessionID     string    `json:"session_id"`
@@ -119,6 +183,32 @@ type Input struct {
 
 	// SessionEnd specific
 	Reason string `json:"reason,omitempty"` // "clear", "logout", "prompt_input_exit", "other"
+
+	// AgentName is the agent that triggered the event (model call, compaction and delegation events)
+	AgentName string `json:"agent_name,omitempty"`
+
+	// Model call specific
+	Model        string `json:"model,omitempty"`
+	Usage        *Usage `json:"usage,omitempty"`         // PostModelCall: usage of the response
+	SessionUsage *Usage `json:"session_usage,omitempty"` // Usage of the session so far
+
+	// PostCompaction specific
+	Summary string `json:"summary,omitempty"`
+
+	// PreDelegation specific
+	TargetAgent     string   `json:"target_agent,omitempty"`
+	DelegationType  string   `json:"delegation_type,omitempty"` // "transfer_task", "handoff"
+	Task            string   `json:"task,omitempty"`
+	DelegationChain []string `json:"delegation_chain,omitempty"` // Agents from the root agent to the delegating one
+}
+
+// Usage is the token usage and cost of a model response or a session
+type Usage struct {
+	InputTokens       int64   `json:"input_tokens"`
+	OutputTokens      int64   `json:"output_tokens"`
+	CachedInputTokens int64   `json:"cached_input_tokens,omitempty"`
+	CacheWriteTokens  int64   `json:"cache_write_tokens,omitempty"`
+	Cost              float64 `json:"cost"`
 }
 
 // ToJSON serializes the input to JSON
@@ -140,7 +230,7 @@ const (
 	DecisionAsk Decision = "ask"
 )
 
-// Output represents the JSON output from a hook
+// Output represents the JSON output from a hook: the stdout of a command, or the body of a webhook response
 type Output struct {
 	// Continue indicates whether to continue execution (default: true)
 	Continue *bool `json:"continue,omitempty"`
@@ -199,10 +289,13 @@ type Result struct {
 	// Message is feedback to include in the response
 	Message string
 
+	// Ask is set when a hook asks the user to confirm the tool call (PreToolUse only)
+	Ask bool
+
 	// ModifiedInput contains any modifications to tool input (PreToolUse only)
 	ModifiedInput map[string]any
 
-	// AdditionalContext is context to add (PostToolUse/SessionStart)
+	// AdditionalContext is context to add (PostToolUse, SessionStart, PreModelCall, PreCompaction, PostCompaction, PreDelegation)
 	AdditionalContext string
 
 	// SystemMessage is a warning to show the user
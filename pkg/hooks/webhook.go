@@ -0,0 +1,112 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// maxWebhookResponseSize caps how much of a webhook response is read.
+	maxWebhookResponseSize = 1 << 20
+
+	// webhookRetryDelay is the delay before the first retry; it doubles on every attempt.
+	webhookRetryDelay = 500 * time.Millisecond
+)
+
+// executeWebhook POSTs the input to the URL of a webhook hook. The response
+// maps to the same results as the exit code of a command hook:
+//   - 2xx is a success, and its body is parsed as the hook Output;
+//   - 403 is a blocking error, like exit code 2, and its body is the reason;
+//   - any other status is an error.
+//
+// Network errors, 429 and 5xx responses are retried up to hook.Retries times.
+// Errors block pre_tool_use hooks, so that tool calls aren't let through
+// while the policy service is down, and are only logged for other events.
+func (e *Executor) executeWebhook(ctx context.Context, hook Hook, inputJSON []byte) (*Output, string, string, int, error) {
+	var (
+		status int
+		body   string
+		err    error
+	)
+	delay := webhookRetryDelay
+	for attempt := 0; ; attempt++ {
+		status, body, err = e.postWebhook(ctx, hook, inputJSON)
+		retryable := err != nil || status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+		if !retryable || attempt >= hook.Retries || ctx.Err() != nil {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+	if err != nil {
+		return nil, "", "", -1, err
+	}
+
+	switch {
+	case status >= 200 && status < 300:
+		return parseOutput(body), body, "", 0, nil
+	case status == http.StatusForbidden:
+		reason := strings.TrimSpace(body)
+		if output := parseOutput(body); output != nil {
+			reason = output.Reason
+			if output.HookSpecificOutput != nil && output.HookSpecificOutput.PermissionDecisionReason != "" {
+				reason = output.HookSpecificOutput.PermissionDecisionReason
+			}
+		}
+		return nil, "", reason, 2, nil
+	default:
+		return nil, "", "", -1, fmt.Errorf("webhook returned %d: %s", status, strings.TrimSpace(body))
+	}
+}
+
+// postWebhook sends a single webhook request and returns the status and body of the response
+func (e *Executor) postWebhook(ctx context.Context, hook Hook, inputJSON []byte) (int, string, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, hook.GetTimeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(timeoutCtx, http.MethodPost, hook.URL, bytes.NewReader(inputJSON))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range hook.Headers {
+		req.Header.Set(name, os.Expand(value, e.lookupEnv))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponseSize))
+	if err != nil {
+		return 0, "", err
+	}
+
+	return resp.StatusCode, string(body), nil
+}
+
+// lookupEnv returns the value of an environment variable from the hook
+// environment, or from the process environment when none was set
+func (e *Executor) lookupEnv(name string) string {
+	if e.env == nil {
+		return os.Getenv(name)
+	}
+	for i := len(e.env) - 1; i >= 0; i-- {
+		if key, value, ok := strings.Cut(e.env[i], "="); ok && key == name {
+			return value
+		}
+	}
+	return ""
+}
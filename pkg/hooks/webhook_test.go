@@ -0,0 +1,186 @@
+package hooks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func webhookConfig(hook Hook) *Config {
+	return &Config{
+		PreToolUse: []MatcherConfig{{Matcher: "*", Hooks: []Hook{hook}}},
+	}
+}
+
+func TestWebhookHook_UpdatedInput(t *testing.T) {
+	t.Parallel()
+
+	var received Input
+	var authorization string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+		_, _ = w.Write([]byte(`{"hook_specific_output":{"permission_decision":"allow","updated_input":{"cmd":"ls -la"}}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	exec := NewExecutor(webhookConfig(Hook{
+		Type:    HookTypeWebhook,
+		URL:     srv.URL,
+		Headers: map[string]string{"Authorization": "Bearer ${POLICY_TOKEN}"},
+	}), t.TempDir(), []string{"POLICY_TOKEN=secret"})
+
+	result, err := exec.ExecutePreToolUse(t.Context(), &Input{
+		SessionID: "test-session",
+		ToolName:  "shell",
+		ToolInput: map[string]any{"cmd": "ls"},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, result.Allowed)
+	assert.Equal(t, map[string]any{"cmd": "ls -la"}, result.ModifiedInput)
+	assert.Equal(t, "Bearer secret", authorization)
+	assert.Equal(t, EventPreToolUse, received.HookEventName)
+	assert.Equal(t, "shell", received.ToolName)
+}
+
+func TestWebhookHook_Deny(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{
+			name:   "deny decision",
+			status: http.StatusOK,
+			body:   `{"hook_specific_output":{"permission_decision":"deny","permission_decision_reason":"Blocked by policy"}}`,
+		},
+		{
+			name:   "forbidden status",
+			status: http.StatusForbidden,
+			body:   `{"reason":"Blocked by policy"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			t.Cleanup(srv.Close)
+
+			exec := NewExecutor(webhookConfig(Hook{Type: HookTypeWebhook, URL: srv.URL}), t.TempDir(), nil)
+
+			result, err := exec.ExecutePreToolUse(t.Context(), &Input{ToolName: "shell"})
+			require.NoError(t, err)
+			assert.False(t, result.Allowed)
+			assert.Equal(t, "Blocked by policy", result.Message)
+		})
+	}
+}
+
+func TestWebhookHook_Retries(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"decision":"block","reason":"Denied after retry"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	exec := NewExecutor(webhookConfig(Hook{Type: HookTypeWebhook, URL: srv.URL, Retries: 1}), t.TempDir(), nil)
+
+	result, err := exec.ExecutePreToolUse(t.Context(), &Input{ToolName: "shell"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+	assert.False(t, result.Allowed)
+	assert.Equal(t, "Denied after retry", result.Message)
+}
+
+func TestWebhookHook_ErrorsBlockToolCalls(t *testing.T) {
+	t.Parallel()
+
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(failing.Close)
+
+	release := make(chan struct{})
+	hanging := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-release
+	}))
+	t.Cleanup(hanging.Close)
+	t.Cleanup(func() { close(release) })
+
+	tests := []struct {
+		name    string
+		hook    Hook
+		message string
+	}{
+		{
+			name:    "unreachable",
+			hook:    Hook{Type: HookTypeWebhook, URL: unreachable.URL},
+			message: "connection refused",
+		},
+		{
+			name:    "server error after retries",
+			hook:    Hook{Type: HookTypeWebhook, URL: failing.URL, Retries: 1},
+			message: "webhook returned 500",
+		},
+		{
+			name:    "timeout",
+			hook:    Hook{Type: HookTypeWebhook, URL: hanging.URL, Timeout: 1},
+			message: "deadline exceeded",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			exec := NewExecutor(webhookConfig(tt.hook), t.TempDir(), nil)
+
+			result, err := exec.ExecutePreToolUse(t.Context(), &Input{ToolName: "shell"})
+			require.NoError(t, err)
+			assert.False(t, result.Allowed)
+			assert.Contains(t, result.Message, tt.message)
+		})
+	}
+}
+
+func TestWebhookHook_ErrorsDontBlockOtherEvents(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(srv.Close)
+
+	exec := NewExecutor(&Config{
+		PreModelCall: []Hook{{Type: HookTypeWebhook, URL: srv.URL}},
+	}, t.TempDir(), nil)
+
+	result, err := exec.ExecutePreModelCall(t.Context(), &Input{})
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, int32(1), calls.Load())
+}
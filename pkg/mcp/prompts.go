@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/config/types"
+	"github.com/docker/docker-agent/pkg/runtime"
+	"github.com/docker/docker-agent/pkg/team"
+)
+
+// addCommandPrompts exposes the commands of an agent as prompts. The prompts
+// are named after the commands, prefixed with the name of the agent when the
+// server exposes several agents.
+func addCommandPrompts(server *mcp.Server, t *team.Team, ag *agent.Agent, prefixed bool) {
+	commands := ag.Commands()
+	for _, name := range slices.Sorted(maps.Keys(commands)) {
+		command := commands[name]
+
+		promptName := name
+		if prefixed {
+			promptName = ag.Name() + "_" + name
+		}
+
+		server.AddPrompt(&mcp.Prompt{
+			Name:        promptName,
+			Description: command.DisplayText(),
+			Arguments: []*mcp.PromptArgument{{
+				Name:        "args",
+				Description: "Arguments of the command, separated by spaces",
+			}},
+		}, commandPromptHandler(t, ag.Name(), name, command))
+	}
+}
+
+// commandPromptHandler returns the handler of the prompt of an agent command.
+// The prompt is the instruction of the command, resolved as in the TUI.
+func commandPromptHandler(t *team.Team, agentName, commandName string, command types.Command) mcp.PromptHandler {
+	return func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		rt, err := runtime.New(t, runtime.WithCurrentAgent(agentName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create runtime: %w", err)
+		}
+		defer rt.Close()
+
+		input := "/" + commandName
+		if args := req.Params.Arguments["args"]; args != "" {
+			input += " " + args
+		}
+
+		return &mcp.GetPromptResult{
+			Description: command.DisplayText(),
+			Messages: []*mcp.PromptMessage{{
+				Role:    "user",
+				Content: &mcp.TextContent{Text: runtime.ResolveCommand(ctx, rt, input)},
+			}},
+		}, nil
+	}
+}
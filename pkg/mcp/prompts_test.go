@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config/types"
+	"github.com/docker/docker-agent/pkg/model/provider/base"
+	"github.com/docker/docker-agent/pkg/team"
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/tools/builtin"
+)
+
+// mockProvider is a model that is never called.
+type mockProvider struct{}
+
+func (mockProvider) ID() string { return "test/mock-model" }
+
+func (mockProvider) CreateChatCompletionStream(context.Context, []chat.Message, []tools.Tool) (chat.MessageStream, error) {
+	panic("unexpected model call")
+}
+
+func (mockProvider) BaseConfig() base.Config { return base.Config{} }
+
+func (mockProvider) MaxTokens() int { return 0 }
+
+// connect connects a client to server with in-memory transports.
+func connect(t *testing.T, server *mcp.Server) *mcp.ClientSession {
+	t.Helper()
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	_, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = clientSession.Close() })
+
+	return clientSession
+}
+
+func TestCommandPrompts(t *testing.T) {
+	t.Parallel()
+
+	root := agent.New("root", "You are a test agent", agent.WithModel(mockProvider{}), agent.WithCommands(types.Commands{
+		"fix-lint": {Description: "Fix linting errors", Instruction: "Fix the lint issues"},
+		"df":       {Instruction: "check disk space"},
+	}))
+	tm := team.New(team.WithAgents(root))
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	addCommandPrompts(server, tm, root, false)
+	client := connect(t, server)
+
+	prompts, err := client.ListPrompts(t.Context(), nil)
+	require.NoError(t, err)
+	require.Len(t, prompts.Prompts, 2)
+	assert.Equal(t, "df", prompts.Prompts[0].Name)
+	assert.Equal(t, "check disk space", prompts.Prompts[0].Description)
+	assert.Equal(t, "fix-lint", prompts.Prompts[1].Name)
+	assert.Equal(t, "Fix linting errors", prompts.Prompts[1].Description)
+
+	prompt, err := client.GetPrompt(t.Context(), &mcp.GetPromptParams{
+		Name:      "fix-lint",
+		Arguments: map[string]string{"args": "main.go"},
+	})
+	require.NoError(t, err)
+	require.Len(t, prompt.Messages, 1)
+	assert.Equal(t, mcp.Role("user"), prompt.Messages[0].Role)
+	assert.Equal(t, &mcp.TextContent{Text: "Fix the lint issues main.go"}, prompt.Messages[0].Content)
+}
+
+func TestCommandPrompts_Prefixed(t *testing.T) {
+	t.Parallel()
+
+	root := agent.New("root", "You are a test agent", agent.WithCommands(types.Commands{
+		"df": {Instruction: "check disk space"},
+	}))
+	tm := team.New(team.WithAgents(root))
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	addCommandPrompts(server, tm, root, true)
+	client := connect(t, server)
+
+	prompts, err := client.ListPrompts(t.Context(), nil)
+	require.NoError(t, err)
+	require.Len(t, prompts.Prompts, 1)
+	assert.Equal(t, "root_df", prompts.Prompts[0].Name)
+}
+
+func TestAgentResources(t *testing.T) {
+	t.Parallel()
+
+	todos := builtin.NewTodoTool()
+	root := agent.New("root", "You are a test agent", agent.WithToolSets(todos))
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	require.NoError(t, addAgentResources(t.Context(), server, root))
+	client := connect(t, server)
+
+	resources, err := client.ListResources(t.Context(), nil)
+	require.NoError(t, err)
+	require.Len(t, resources.Resources, 1)
+	assert.Equal(t, "docker-agent://agents/root/todos", resources.Resources[0].URI)
+	assert.Equal(t, "Todo list of the root agent", resources.Resources[0].Title)
+
+	result, err := client.ReadResource(t.Context(), &mcp.ReadResourceParams{URI: "docker-agent://agents/root/todos"})
+	require.NoError(t, err)
+	require.Len(t, result.Contents, 1)
+	assert.Equal(t, "application/json", result.Contents[0].MIMEType)
+	assert.JSONEq(t, `{"todos":[]}`, result.Contents[0].Text)
+}
@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/tools"
+	"github.com/docker/docker-agent/pkg/tools/builtin"
+)
+
+// agentResources are the resources exposed for the agents that have the
+// read-only tool they are read with.
+var agentResources = []struct {
+	name  string
+	tool  string
+	title string
+}{
+	{name: "todos", tool: builtin.ToolNameListTodos, title: "Todo list of the %s agent"},
+	{name: "tasks", tool: builtin.ToolNameListTasks, title: "Tasks of the %s agent"},
+	{name: "memories", tool: builtin.ToolNameGetMemories, title: "Memories of the %s agent"},
+}
+
+// addAgentResources exposes the todo list, tasks and memories of an agent as
+// resources, when it has them.
+func addAgentResources(ctx context.Context, server *mcp.Server, ag *agent.Agent) error {
+	agentTools, err := ag.Tools(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, res := range agentResources {
+		if !slices.ContainsFunc(agentTools, func(t tools.Tool) bool { return t.Name == res.tool }) {
+			continue
+		}
+
+		server.AddResource(&mcp.Resource{
+			URI:      fmt.Sprintf("docker-agent://agents/%s/%s", ag.Name(), res.name),
+			Name:     ag.Name() + "_" + res.name,
+			Title:    fmt.Sprintf(res.title, ag.Name()),
+			MIMEType: "application/json",
+		}, toolResourceHandler(ag, res.tool))
+	}
+
+	return nil
+}
+
+// toolResourceHandler returns the handler of a resource whose content is
+// the output of a read-only tool of an agent, called without arguments.
+func toolResourceHandler(ag *agent.Agent, toolName string) mcp.ResourceHandler {
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		agentTools, err := ag.Tools(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tools of agent %s: %w", ag.Name(), err)
+		}
+
+		i := slices.IndexFunc(agentTools, func(t tools.Tool) bool { return t.Name == toolName })
+		if i < 0 {
+			return nil, mcp.ResourceNotFoundError(req.Params.URI)
+		}
+
+		result, err := agentTools[i].Handler(ctx, tools.ToolCall{
+			Type:     "function",
+			Function: tools.FunctionCall{Name: toolName, Arguments: "{}"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", req.Params.URI, err)
+		}
+		if result.IsError {
+			return nil, errors.New(result.Output)
+		}
+
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{{
+				URI:      req.Params.URI,
+				MIMEType: "application/json",
+				Text:     result.Output,
+			}},
+		}, nil
+	}
+}
@@ -9,13 +9,13 @@ import (
 	"net"
 	"net/http"
 	"slices"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"github.com/docker/docker-agent/pkg/agent"
 	"github.com/docker/docker-agent/pkg/config"
 	"github.com/docker/docker-agent/pkg/runtime"
-	"github.com/docker/docker-agent/pkg/session"
 	"github.com/docker/docker-agent/pkg/team"
 	"github.com/docker/docker-agent/pkg/teamloader"
 	"github.com/docker/docker-agent/pkg/tools"
@@ -23,22 +23,33 @@ import (
 )
 
 type ToolInput struct {
-	Message string `json:"message" jsonschema:"the message to send to the agent"`
+	Message   string `json:"message" jsonschema:"the message to send to the agent"`
+	SessionID string `json:"session_id,omitempty" jsonschema:"the ID of the session to continue, as returned by a previous call. A new session is started when empty"`
 }
 
 type ToolOutput struct {
-	Response string `json:"response" jsonschema:"the response from the agent"`
+	Response  string `json:"response" jsonschema:"the response from the agent"`
+	SessionID string `json:"session_id" jsonschema:"the ID of the session, to continue it in a later call"`
 }
 
+// sessionTimeout is how long an HTTP client can stay idle before its MCP
+// session, and the agent sessions it started, are dropped.
+const sessionTimeout = 30 * time.Minute
+
 func StartMCPServer(ctx context.Context, agentFilename, agentName string, runConfig *config.RuntimeConfig) error {
 	slog.Debug("Starting MCP server", "agent", agentFilename)
 
-	server, cleanup, err := createMCPServer(ctx, agentFilename, agentName, runConfig)
+	newServer, cleanup, err := createMCPServer(ctx, agentFilename, agentName, runConfig)
 	if err != nil {
 		return err
 	}
 	defer cleanup()
 
+	server, err := newServer(ctx)
+	if err != nil {
+		return err
+	}
+
 	slog.Debug("MCP server starting with stdio transport")
 
 	if err := server.Run(ctx, &mcp.StdioTransport{}); err != nil {
@@ -48,11 +59,13 @@ func StartMCPServer(ctx context.Context, agentFilename, agentName string, runCon
 	return nil
 }
 
-// StartHTTPServer starts a streaming HTTP MCP server on the given listener
+// StartHTTPServer starts a streaming HTTP MCP server on the given listener.
+// Each client gets its own server, so that clients don't see each other's
+// sessions.
 func StartHTTPServer(ctx context.Context, agentFilename, agentName string, runConfig *config.RuntimeConfig, ln net.Listener) error {
 	slog.Debug("Starting HTTP MCP server", "agent", agentFilename, "addr", ln.Addr())
 
-	server, cleanup, err := createMCPServer(ctx, agentFilename, agentName, runConfig)
+	newServer, cleanup, err := createMCPServer(ctx, agentFilename, agentName, runConfig)
 	if err != nil {
 		return err
 	}
@@ -61,9 +74,16 @@ func StartHTTPServer(ctx context.Context, agentFilename, agentName string, runCo
 	fmt.Printf("MCP HTTP server listening on http://%s\n", ln.Addr())
 
 	httpServer := &http.Server{
-		Handler: mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server {
+		Handler: mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
+			server, err := newServer(r.Context())
+			if err != nil {
+				slog.Error("Failed to create MCP server", "error", err)
+				return nil
+			}
 			return server
-		}, nil),
+		}, &mcp.StreamableHTTPOptions{
+			SessionTimeout: sessionTimeout,
+		}),
 	}
 
 	errCh := make(chan error, 1)
@@ -82,7 +102,9 @@ func StartHTTPServer(ctx context.Context, agentFilename, agentName string, runCo
 	}
 }
 
-func createMCPServer(ctx context.Context, agentFilename, agentName string, runConfig *config.RuntimeConfig) (*mcp.Server, func(), error) {
+// createMCPServer loads the agents and returns a function creating an MCP
+// server that exposes them, with its own sessions.
+func createMCPServer(ctx context.Context, agentFilename, agentName string, runConfig *config.RuntimeConfig) (func(context.Context) (*mcp.Server, error), func(), error) {
 	agentSource, err := config.Resolve(agentFilename, nil)
 	if err != nil {
 		return nil, nil, err
@@ -99,11 +121,6 @@ func createMCPServer(ctx context.Context, agentFilename, agentName string, runCo
 		}
 	}
 
-	server := mcp.NewServer(&mcp.Implementation{
-		Name:    "docker agent",
-		Version: version.Version,
-	}, nil)
-
 	agentNames := t.AgentNames()
 	if agentName != "" {
 		if !slices.Contains(agentNames, agentName) {
@@ -113,8 +130,10 @@ func createMCPServer(ctx context.Context, agentFilename, agentName string, runCo
 		agentNames = []string{agentName}
 	}
 
-	slog.Debug("Adding MCP tools for agents", "count", len(agentNames))
-
+	var (
+		agents   []*agent.Agent
+		toolDefs []*mcp.Tool
+	)
 	for _, agentName := range agentNames {
 		ag, err := t.Agent(agentName)
 		if err != nil {
@@ -124,8 +143,6 @@ func createMCPServer(ctx context.Context, agentFilename, agentName string, runCo
 
 		description := cmp.Or(ag.Description(), fmt.Sprintf("Run the %s agent", agentName))
 
-		slog.Debug("Adding MCP tool", "agent", agentName, "description", description)
-
 		annotations, err := agentToolAnnotations(ctx, ag)
 		if err != nil {
 			cleanup()
@@ -134,35 +151,61 @@ func createMCPServer(ctx context.Context, agentFilename, agentName string, runCo
 
 		annotations.Title = description
 
-		toolDef := &mcp.Tool{
+		agents = append(agents, ag)
+		toolDefs = append(toolDefs, &mcp.Tool{
 			Name:         agentName,
 			Description:  description,
 			Annotations:  annotations,
 			InputSchema:  tools.MustSchemaFor[ToolInput](),
 			OutputSchema: tools.MustSchemaFor[ToolOutput](),
+		})
+	}
+
+	newServer := func(ctx context.Context) (*mcp.Server, error) {
+		server := mcp.NewServer(&mcp.Implementation{
+			Name:    "docker agent",
+			Version: version.Version,
+		}, nil)
+
+		sessions := NewSessions()
+		sessions.server = server
+
+		slog.Debug("Adding MCP tools for agents", "count", len(agents))
+
+		for i, ag := range agents {
+			slog.Debug("Adding MCP tool", "agent", ag.Name(), "description", toolDefs[i].Description)
+
+			mcp.AddTool(server, toolDefs[i], CreateToolHandler(t, ag.Name(), sessions))
+
+			addCommandPrompts(server, t, ag, len(agents) > 1)
+			if err := addAgentResources(ctx, server, ag); err != nil {
+				return nil, fmt.Errorf("failed to add resources for agent %s: %w", ag.Name(), err)
+			}
 		}
 
-		mcp.AddTool(server, toolDef, CreateToolHandler(t, agentName))
+		return server, nil
 	}
 
-	return server, cleanup, nil
+	return newServer, cleanup, nil
 }
 
-func CreateToolHandler(t *team.Team, agentName string) func(context.Context, *mcp.CallToolRequest, ToolInput) (*mcp.CallToolResult, ToolOutput, error) {
+// CreateToolHandler returns the handler of the tool of an agent. Each call
+// starts a new session, kept in sessions, or continues the session of the
+// agent given by its session_id.
+func CreateToolHandler(t *team.Team, agentName string, sessions *Sessions) func(context.Context, *mcp.CallToolRequest, ToolInput) (*mcp.CallToolResult, ToolOutput, error) {
 	return func(ctx context.Context, req *mcp.CallToolRequest, input ToolInput) (*mcp.CallToolResult, ToolOutput, error) {
-		slog.Debug("MCP tool called", "agent", agentName, "message", input.Message)
+		slog.Debug("MCP tool called", "agent", agentName, "message", input.Message, "session_id", input.SessionID)
 
 		ag, err := t.Agent(agentName)
 		if err != nil {
 			return nil, ToolOutput{}, fmt.Errorf("failed to get agent: %w", err)
 		}
 
-		sess := session.New(
-			session.WithTitle("MCP tool call"),
-			session.WithMaxIterations(ag.MaxIterations()),
-			session.WithUserMessage(input.Message),
-			session.WithToolsApproved(true),
-		)
+		sess, release, err := sessions.acquire(ctx, input.SessionID, ag, input.Message)
+		if err != nil {
+			return nil, ToolOutput{}, err
+		}
+		defer release()
 
 		rt, err := runtime.New(t,
 			runtime.WithCurrentAgent(agentName),
@@ -170,6 +213,7 @@ func CreateToolHandler(t *team.Team, agentName string) func(context.Context, *mc
 		if err != nil {
 			return nil, ToolOutput{}, fmt.Errorf("failed to create runtime: %w", err)
 		}
+		defer rt.Close()
 
 		_, err = rt.Run(ctx, sess)
 		if err != nil {
@@ -181,7 +225,7 @@ func CreateToolHandler(t *team.Team, agentName string) func(context.Context, *mc
 
 		slog.Debug("Agent execution completed", "agent", agentName, "response_length", len(result))
 
-		return nil, ToolOutput{Response: result}, nil
+		return nil, ToolOutput{Response: result, SessionID: sess.ID}, nil
 	}
 }
 
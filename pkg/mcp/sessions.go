@@ -0,0 +1,171 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/app/transcript"
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+// sessionURIPrefix is the prefix of the URIs of the session transcripts.
+const sessionURIPrefix = "docker-agent://sessions/"
+
+// maxSessions is the number of sessions kept. Starting a session beyond it
+// drops the oldest session that isn't running.
+const maxSessions = 100
+
+// Sessions keeps the sessions of the agents called by an MCP client, so that
+// later tool calls can continue them with their session_id, and their
+// transcripts can be read as resources.
+type Sessions struct {
+	store session.Store
+	// server, when set, exposes the transcript of each new session as a
+	// resource.
+	server *mcp.Server
+
+	mu sync.Mutex
+	// ids are the IDs of the sessions, oldest first.
+	ids []string
+	// agents are the names of the agents of the sessions, by session ID.
+	agents map[string]string
+	// running are the IDs of the sessions a tool call is running.
+	running map[string]bool
+}
+
+// NewSessions creates an empty, in-memory set of sessions.
+func NewSessions() *Sessions {
+	return &Sessions{
+		store:   session.NewInMemorySessionStore(),
+		agents:  make(map[string]string),
+		running: make(map[string]bool),
+	}
+}
+
+// acquire returns the session to send a message to the agent in: the session
+// of the agent with the given ID, or a new one when the ID is empty. Other
+// tool calls can't use the session until release is called.
+func (s *Sessions) acquire(ctx context.Context, id string, ag *agent.Agent, message string) (sess *session.Session, release func(), err error) {
+	if id == "" {
+		sess = session.New(
+			session.WithTitle("MCP tool call"),
+			session.WithMaxIterations(ag.MaxIterations()),
+			session.WithUserMessage(message),
+			session.WithToolsApproved(true),
+		)
+		if err := s.add(ctx, sess, ag.Name()); err != nil {
+			return nil, nil, err
+		}
+		s.addTranscriptResource(sess, ag.Name())
+	} else {
+		s.mu.Lock()
+		owner := s.agents[id]
+		s.mu.Unlock()
+		// Sessions of other agents are reported as not found, as tools
+		// are only meant to continue the sessions they started.
+		if owner != ag.Name() {
+			return nil, nil, fmt.Errorf("session %s not found", id)
+		}
+
+		sess, err = s.store.GetSession(ctx, id)
+		if errors.Is(err, session.ErrNotFound) {
+			return nil, nil, fmt.Errorf("session %s not found", id)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get session %s: %w", id, err)
+		}
+
+		s.mu.Lock()
+		if s.running[sess.ID] {
+			s.mu.Unlock()
+			return nil, nil, fmt.Errorf("session %s is already running", sess.ID)
+		}
+		s.running[sess.ID] = true
+		s.mu.Unlock()
+
+		sess.AddMessage(session.UserMessage(message))
+	}
+
+	return sess, func() {
+		s.mu.Lock()
+		delete(s.running, sess.ID)
+		s.mu.Unlock()
+	}, nil
+}
+
+// add stores a new session of an agent, marked as running, dropping the
+// oldest session that isn't running when there are already maxSessions.
+func (s *Sessions) add(ctx context.Context, sess *session.Session, agentName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.ids) >= maxSessions {
+		i := slices.IndexFunc(s.ids, func(id string) bool { return !s.running[id] })
+		if i < 0 {
+			return fmt.Errorf("too many running sessions (max %d)", maxSessions)
+		}
+		s.removeLocked(ctx, s.ids[i])
+	}
+
+	if err := s.store.AddSession(ctx, sess); err != nil {
+		return fmt.Errorf("failed to store session: %w", err)
+	}
+	s.ids = append(s.ids, sess.ID)
+	s.agents[sess.ID] = agentName
+	s.running[sess.ID] = true
+
+	return nil
+}
+
+// removeLocked drops a session and its transcript resource. s.mu must be
+// held.
+func (s *Sessions) removeLocked(ctx context.Context, id string) {
+	s.ids = slices.DeleteFunc(s.ids, func(other string) bool { return other == id })
+	delete(s.agents, id)
+	if err := s.store.DeleteSession(ctx, id); err != nil {
+		slog.Warn("Failed to delete MCP session", "session_id", id, "error", err)
+	}
+	if s.server != nil {
+		s.server.RemoveResources(sessionURIPrefix + id)
+	}
+}
+
+// addTranscriptResource exposes the transcript of a session as a resource.
+func (s *Sessions) addTranscriptResource(sess *session.Session, agentName string) {
+	if s.server == nil {
+		return
+	}
+
+	s.server.AddResource(&mcp.Resource{
+		URI:         sessionURIPrefix + sess.ID,
+		Name:        sess.ID,
+		Title:       fmt.Sprintf("Session with the %s agent", agentName),
+		Description: "Transcript of the session, continued with the session_id argument of the agent's tool",
+		MIMEType:    "text/markdown",
+	}, s.readTranscript)
+}
+
+func (s *Sessions) readTranscript(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+
+	sess, err := s.store.GetSession(ctx, strings.TrimPrefix(uri, sessionURIPrefix))
+	if err != nil {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{
+			URI:      uri,
+			MIMEType: "text/markdown",
+			Text:     transcript.PlainText(sess),
+		}},
+	}, nil
+}
@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/agent"
+)
+
+func TestSessions_Acquire(t *testing.T) {
+	t.Parallel()
+
+	root := agent.New("root", "You are a test agent")
+	sessions := NewSessions()
+
+	sess, release, err := sessions.acquire(t.Context(), "", root, "hello")
+	require.NoError(t, err)
+	assert.Len(t, sess.GetAllMessages(), 1)
+
+	_, _, err = sessions.acquire(t.Context(), sess.ID, root, "again")
+	require.ErrorContains(t, err, "is already running")
+
+	release()
+
+	continued, release, err := sessions.acquire(t.Context(), sess.ID, root, "again")
+	require.NoError(t, err)
+	defer release()
+	assert.Equal(t, sess.ID, continued.ID)
+	assert.Len(t, continued.GetAllMessages(), 2)
+
+	_, _, err = sessions.acquire(t.Context(), "unknown", root, "hello")
+	require.ErrorContains(t, err, "session unknown not found")
+}
+
+func TestSessions_Transcript(t *testing.T) {
+	t.Parallel()
+
+	root := agent.New("root", "You are a test agent")
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	sessions := NewSessions()
+	sessions.server = server
+
+	sess, release, err := sessions.acquire(t.Context(), "", root, "hello")
+	require.NoError(t, err)
+	release()
+
+	client := connect(t, server)
+
+	resources, err := client.ListResources(t.Context(), nil)
+	require.NoError(t, err)
+	require.Len(t, resources.Resources, 1)
+	assert.Equal(t, sessionURIPrefix+sess.ID, resources.Resources[0].URI)
+	assert.Equal(t, "Session with the root agent", resources.Resources[0].Title)
+
+	result, err := client.ReadResource(t.Context(), &mcp.ReadResourceParams{URI: sessionURIPrefix + sess.ID})
+	require.NoError(t, err)
+	require.Len(t, result.Contents, 1)
+	assert.Equal(t, "text/markdown", result.Contents[0].MIMEType)
+	assert.Contains(t, result.Contents[0].Text, "hello")
+}
+
+func TestSessions_OtherAgent(t *testing.T) {
+	t.Parallel()
+
+	root := agent.New("root", "You are a test agent")
+	other := agent.New("other", "You are another test agent")
+	sessions := NewSessions()
+
+	sess, release, err := sessions.acquire(t.Context(), "", root, "hello")
+	require.NoError(t, err)
+	release()
+
+	_, _, err = sessions.acquire(t.Context(), sess.ID, other, "hello")
+	require.ErrorContains(t, err, "session "+sess.ID+" not found")
+}
+
+func TestSessions_Max(t *testing.T) {
+	t.Parallel()
+
+	root := agent.New("root", "You are a test agent")
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	sessions := NewSessions()
+	sessions.server = server
+
+	running, _, err := sessions.acquire(t.Context(), "", root, "hello")
+	require.NoError(t, err)
+	oldest, release, err := sessions.acquire(t.Context(), "", root, "hello")
+	require.NoError(t, err)
+	release()
+
+	for range maxSessions - 1 {
+		_, release, err := sessions.acquire(t.Context(), "", root, "hello")
+		require.NoError(t, err)
+		release()
+	}
+
+	_, _, err = sessions.acquire(t.Context(), running.ID, root, "again")
+	require.ErrorContains(t, err, "is already running")
+	_, _, err = sessions.acquire(t.Context(), oldest.ID, root, "again")
+	require.ErrorContains(t, err, "session "+oldest.ID+" not found")
+
+	client := connect(t, server)
+	resources, err := client.ListResources(t.Context(), nil)
+	require.NoError(t, err)
+	assert.Len(t, resources.Resources, maxSessions)
+}
@@ -4,7 +4,9 @@ package permissions
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -47,18 +49,77 @@ type Checker struct {
 	allowPatterns []string
 	askPatterns   []string
 	denyPatterns  []string
+	workingDir    string
+	// regexps are the compiled regular expressions of the conditions, by
+	// expression. Invalid expressions are missing.
+	regexps map[string]*regexp.Regexp
+	// invalid is the error of the first invalid regular expression, if any.
+	invalid error
+}
+
+// Opt configures a Checker.
+type Opt func(c *Checker)
+
+// WithWorkingDir sets the directory that relative paths, in path conditions
+// and in tool arguments, are resolved against.
+func WithWorkingDir(dir string) Opt {
+	return func(c *Checker) {
+		c.workingDir = dir
+	}
 }
 
 // NewChecker creates a new permission checker from config
-func NewChecker(cfg *latest.PermissionsConfig) *Checker {
-	if cfg == nil {
-		return &Checker{}
+func NewChecker(cfg *latest.PermissionsConfig, opts ...Opt) *Checker {
+	c := &Checker{}
+	if cfg != nil {
+		c.allowPatterns = cfg.Allow
+		c.askPatterns = cfg.Ask
+		c.denyPatterns = cfg.Deny
 	}
-	return &Checker{
-		allowPatterns: cfg.Allow,
-		askPatterns:   cfg.Ask,
-		denyPatterns:  cfg.Deny,
+	for _, opt := range opts {
+		opt(c)
 	}
+	c.compileRegexps()
+	return c
+}
+
+// compileRegexps compiles the regular expressions of the conditions once, so
+// that they are not compiled again on every tool call.
+func (c *Checker) compileRegexps() {
+	c.regexps = make(map[string]*regexp.Regexp)
+	for _, patterns := range [][]string{c.denyPatterns, c.allowPatterns, c.askPatterns} {
+		for _, pattern := range patterns {
+			_, argPatterns := parsePattern(pattern)
+			for key, value := range argPatterns {
+				cond := parseCondition(key, value)
+				if cond.op != opRegex {
+					continue
+				}
+				if _, ok := c.regexps[cond.value]; ok {
+					continue
+				}
+				re, err := regexp.Compile(cond.value)
+				if err != nil {
+					if c.invalid == nil {
+						c.invalid = fmt.Errorf("invalid permission pattern %q: %w", pattern, err)
+					}
+					continue
+				}
+				c.regexps[cond.value] = re
+			}
+		}
+	}
+}
+
+// ForWorkingDir returns a copy of the checker that resolves relative paths
+// against dir, e.g. the working directory of a session.
+func (c *Checker) ForWorkingDir(dir string) *Checker {
+	if dir == "" || dir == c.workingDir {
+		return c
+	}
+	clone := *c
+	clone.workingDir = dir
+	return &clone
 }
 
 // Check evaluates the permission for a given tool name without arguments.
@@ -79,34 +140,106 @@ func (c *Checker) Check(toolName string) Decision {
 // - Argument matching: "shell:cmd=ls*" matches shell tool with cmd argument starting with "ls"
 // - Multiple arguments: "shell:cmd=ls*:cwd=/home/*" matches both conditions
 // - Glob patterns in both tool names and argument values
+// - Regular expressions: "shell:cmd~=^git (status|diff)" matches when the expression matches the argument
+// - Path scoping: "edit_file:path^=./src" matches paths under ./src, after resolving
+// relative paths against the working directory and following symlinks
+// - Negation: "shell:cmd!~=rm\s+-rf", "shell:cmd!=sudo*" or "write_file:path!^=./src"
+// match when the argument does not match
 //
 // Returns ForceAsk when an explicit ask pattern matches. ForceAsk means the
 // tool must always be confirmed, even when it would normally be auto-approved
 // (e.g. read-only tools). Note that --yolo mode takes precedence over ForceAsk.
 func (c *Checker) CheckWithArgs(toolName string, args map[string]any) Decision {
+	decision, _ := c.Evaluate(toolName, args)
+	return decision
+}
+
+// Evaluate is like CheckWithArgs but also returns the pattern that decided,
+// or an empty string when no pattern matched.
+func (c *Checker) Evaluate(toolName string, args map[string]any) (Decision, string) {
 	// Deny patterns are checked first - they take priority
 	for _, pattern := range c.denyPatterns {
-		if matchToolPattern(pattern, toolName, args) {
-			return Deny
+		if c.matchToolPattern(pattern, toolName, args, true) {
+			return Deny, pattern
 		}
 	}
 
 	// Allow patterns are checked second
 	for _, pattern := range c.allowPatterns {
-		if matchToolPattern(pattern, toolName, args) {
-			return Allow
+		if c.matchToolPattern(pattern, toolName, args, false) {
+			return Allow, pattern
 		}
 	}
 
 	// Explicit ask patterns override auto-approval (e.g. read-only hints)
 	for _, pattern := range c.askPatterns {
-		if matchToolPattern(pattern, toolName, args) {
-			return ForceAsk
+		if c.matchToolPattern(pattern, toolName, args, false) {
+			return ForceAsk, pattern
 		}
 	}
 
 	// Default is Ask
-	return Ask
+	return Ask, ""
+}
+
+// RuleResult is the outcome of a single rule in an [Explanation].
+type RuleResult struct {
+	// List is the list the rule belongs to: Deny, Allow or ForceAsk (ask).
+	List    Decision
+	Pattern string
+	Matched bool
+	// Reason explains why the rule did not match.
+	Reason string
+}
+
+// Explanation details how the decision for a tool call was reached.
+type Explanation struct {
+	Decision Decision
+	// Pattern is the rule that decided, empty when no rule matched.
+	Pattern string
+	// Rules lists every rule, in evaluation order.
+	Rules []RuleResult
+}
+
+// Explain evaluates every rule against a tool call and returns the decision
+// along with the outcome of each rule. The decision is the same as
+// [Checker.Evaluate]'s.
+func (c *Checker) Explain(toolName string, args map[string]any) Explanation {
+	explanation := Explanation{Decision: Ask}
+
+	lists := []struct {
+		decision Decision
+		patterns []string
+	}{
+		{Deny, c.denyPatterns},
+		{Allow, c.allowPatterns},
+		{ForceAsk, c.askPatterns},
+	}
+	for _, list := range lists {
+		for _, pattern := range list.patterns {
+			reason := c.explainToolPattern(pattern, toolName, args, list.decision == Deny)
+			matched := reason == ""
+			if matched && explanation.Pattern == "" {
+				explanation.Decision = list.decision
+				explanation.Pattern = pattern
+			}
+			explanation.Rules = append(explanation.Rules, RuleResult{
+				List:    list.decision,
+				Pattern: pattern,
+				Matched: matched,
+				Reason:  reason,
+			})
+		}
+	}
+
+	return explanation
+}
+
+// Validate returns an error if a rule has an invalid regular expression.
+// Rules with an invalid expression never match, except deny rules which
+// always match so that a typo doesn't silently allow what they should deny.
+func (c *Checker) Validate() error {
+	return c.invalid
 }
 
 // IsEmpty returns true if no permissions are configured
@@ -143,16 +276,21 @@ func parsePattern(pattern string) (toolPattern string, argPatterns map[string]st
 	parts := strings.Split(pattern, ":")
 	toolParts := []string{parts[0]} // First part is always part of the tool name
 
+	var lastKey string
 	for _, part := range parts[1:] {
 		// Check if this part looks like an argument pattern (contains =)
 		if key, value, found := strings.Cut(part, "="); found && key != "" {
 			// This is an argument pattern - this and all remaining parts are args
 			argPatterns[key] = value
+			lastKey = key
 		} else if len(argPatterns) == 0 {
 			// No = found and we haven't started args yet, so it's part of tool name
 			toolParts = append(toolParts, part)
+		} else {
+			// We've started collecting args: the colon is part of the previous
+			// value, e.g. in a regular expression like "(?:a|b)" or a URL
+			argPatterns[lastKey] += ":" + part
 		}
-		// If we've started collecting args but this part has no =, skip it
 	}
 
 	toolPattern = strings.Join(toolParts, ":")
@@ -163,39 +301,159 @@ func parsePattern(pattern string) (toolPattern string, argPatterns map[string]st
 // The pattern can be:
 // - Simple: "shell" - matches tool name only
 // - With args: "shell:cmd=ls*" - matches tool name AND argument value
-func matchToolPattern(pattern, toolName string, args map[string]any) bool {
+//
+// When invalidMatches is true, a condition with an invalid regular expression
+// matches any value.
+func (c *Checker) matchToolPattern(pattern, toolName string, args map[string]any, invalidMatches bool) bool {
+	return c.explainToolPattern(pattern, toolName, args, invalidMatches) == ""
+}
+
+// explainToolPattern returns why a tool call doesn't match a pattern, or an
+// empty string if it does.
+func (c *Checker) explainToolPattern(pattern, toolName string, args map[string]any, invalidMatches bool) string {
 	toolPattern, argPatterns := parsePattern(pattern)
 
 	// First check if the tool name matches
 	if !matchGlob(toolPattern, toolName) {
-		return false
+		return "tool name does not match " + toolPattern
 	}
 
-	// If no argument patterns, we're done - tool name matched
-	if len(argPatterns) == 0 {
-		return true
+	// All argument conditions must match
+	for key, value := range argPatterns {
+		cond := parseCondition(key, value)
+
+		argValue, exists := args[cond.arg]
+		if !exists {
+			return "argument " + cond.arg + " is missing"
+		}
+
+		if reason := c.explainCondition(cond, argToString(argValue), invalidMatches); reason != "" {
+			return reason
+		}
 	}
 
-	// If pattern has argument conditions but no args provided, no match
-	if args == nil {
-		return false
+	return ""
+}
+
+// operator is the way an argument condition compares values.
+type operator int
+
+const (
+	// opGlob matches a glob pattern: "arg=pattern".
+	opGlob operator = iota
+	// opRegex matches a regular expression: "arg~=expr".
+	opRegex
+	// opPath matches paths under a directory: "arg^=dir".
+	opPath
+)
+
+// condition is an argument condition of a pattern.
+type condition struct {
+	arg     string
+	op      operator
+	negated bool
+	value   string
+}
+
+// parseCondition parses an argument condition from the key and value of a
+// "key=value" segment. The key ends with the operator: "cmd~" for a regular
+// expression, "path^" for a path prefix, optionally preceded by "!" to negate
+// the condition, e.g. "cmd!" or "cmd!~".
+func parseCondition(key, value string) condition {
+	cond := condition{value: value}
+
+	switch {
+	case strings.HasSuffix(key, "~"):
+		cond.op = opRegex
+		key = strings.TrimSuffix(key, "~")
+	case strings.HasSuffix(key, "^"):
+		cond.op = opPath
+		key = strings.TrimSuffix(key, "^")
+	}
+	if strings.HasSuffix(key, "!") {
+		cond.negated = true
+		key = strings.TrimSuffix(key, "!")
 	}
 
-	// All argument patterns must match
-	for argName, argPattern := range argPatterns {
-		argValue, exists := args[argName]
-		if !exists {
-			return false
+	cond.arg = key
+	return cond
+}
+
+// explainCondition returns why an argument value doesn't satisfy a
+// condition, or an empty string if it does.
+func (c *Checker) explainCondition(cond condition, value string, invalidMatches bool) string {
+	var matched bool
+	switch cond.op {
+	case opRegex:
+		re, ok := c.regexps[cond.value]
+		if !ok {
+			if invalidMatches {
+				return ""
+			}
+			return fmt.Sprintf("invalid regular expression %q", cond.value)
 		}
+		matched = re.MatchString(value)
+	case opPath:
+		matched = isPathUnder(c.resolvePath(value), c.resolvePath(cond.value))
+	default:
+		matched = matchGlob(cond.value, value)
+	}
 
-		// Convert argument value to string for matching
-		argStr := argToString(argValue)
-		if !matchGlob(argPattern, argStr) {
-			return false
+	if matched != cond.negated {
+		return ""
+	}
+	return "argument " + cond.arg + " " + cond.describe(matched)
+}
+
+// describe describes how an argument value relates to the condition's value.
+func (cond condition) describe(matched bool) string {
+	switch {
+	case cond.op == opPath && matched:
+		return "is under " + cond.value
+	case cond.op == opPath:
+		return "is not under " + cond.value
+	case matched:
+		return "matches " + cond.value
+	default:
+		return "does not match " + cond.value
+	}
+}
+
+// resolvePath makes a path absolute, relative to the working directory, and
+// resolves the symlinks of its longest existing prefix, so that paths that
+// don't exist yet (e.g. files about to be written) are still resolved.
+func (c *Checker) resolvePath(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(c.workingDir, path)
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	var missing []string
+	for dir := path; ; dir = filepath.Dir(dir) {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(append([]string{resolved}, missing...)...)
 		}
+		if filepath.Dir(dir) == dir {
+			return path
+		}
+		missing = append([]string{filepath.Base(dir)}, missing...)
 	}
+}
 
-	return true
+// isPathUnder reports whether path is dir or one of its descendants.
+func isPathUnder(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
 }
 
 // argToString converts an argument value to a string for pattern matching.
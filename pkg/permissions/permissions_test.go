@@ -1,6 +1,8 @@
 package permissions
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -459,6 +461,165 @@ func TestChecker_ForceAsk(t *testing.T) {
 	}
 }
 
+func TestChecker_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	checker := NewChecker(&latest.PermissionsConfig{
+		Allow: []string{"read_*", "shell:cmd=ls*"},
+		Ask:   []string{"fetch"},
+		Deny:  []string{"shell:cmd=rm*"},
+	})
+
+	tests := []struct {
+		toolName    string
+		args        map[string]any
+		wantResult  Decision
+		wantPattern string
+	}{
+		{toolName: "read_file", wantResult: Allow, wantPattern: "read_*"},
+		{toolName: "shell", args: map[string]any{"cmd": "ls -la"}, wantResult: Allow, wantPattern: "shell:cmd=ls*"},
+		{toolName: "shell", args: map[string]any{"cmd": "rm -rf /"}, wantResult: Deny, wantPattern: "shell:cmd=rm*"},
+		{toolName: "fetch", wantResult: ForceAsk, wantPattern: "fetch"},
+		{toolName: "write_file", wantResult: Ask},
+	}
+
+	for _, tt := range tests {
+		decision, pattern := checker.Evaluate(tt.toolName, tt.args)
+		assert.Equal(t, tt.wantResult, decision, tt.toolName)
+		assert.Equal(t, tt.wantPattern, pattern, tt.toolName)
+	}
+}
+
+func TestChecker_ArgumentOperators(t *testing.T) {
+	t.Parallel()
+
+	checker := NewChecker(&latest.PermissionsConfig{
+		Allow: []string{"shell:cmd!~=rm\\s+-rf", "fetch:url~=^https://docs\\.example\\.com(?:/|$)"},
+		Deny:  []string{"shell:cmd=sudo*", "write_file:path!=*.md"},
+	})
+
+	tests := []struct {
+		name     string
+		toolName string
+		args     map[string]any
+		want     Decision
+	}{
+		{"negated regex allows", "shell", map[string]any{"cmd": "make build"}, Allow},
+		{"negated regex excludes", "shell", map[string]any{"cmd": "rm  -rf build"}, Ask},
+		{"deny checked first", "shell", map[string]any{"cmd": "sudo make"}, Deny},
+		{"regex with colons", "fetch", map[string]any{"url": "https://docs.example.com/guide"}, Allow},
+		{"regex does not match", "fetch", map[string]any{"url": "https://docs.example.com.evil.io"}, Ask},
+		{"negated glob denies", "write_file", map[string]any{"path": "main.go"}, Deny},
+		{"negated glob does not deny", "write_file", map[string]any{"path": "README.md"}, Ask},
+		{"missing argument never matches", "write_file", map[string]any{}, Ask},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, checker.CheckWithArgs(tt.toolName, tt.args))
+		})
+	}
+}
+
+func TestChecker_PathScoping(t *testing.T) {
+	t.Parallel()
+
+	wd := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(wd, "src", "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(wd, "src", "main.go"), nil, 0o644))
+	require.NoError(t, os.Symlink(filepath.Join(wd, "src"), filepath.Join(wd, "link-to-src")))
+	require.NoError(t, os.Symlink(t.TempDir(), filepath.Join(wd, "src", "escape")))
+
+	checker := NewChecker(&latest.PermissionsConfig{
+		Allow: []string{"edit_file:path^=./src"},
+		Deny:  []string{"edit_file:path!^=."},
+	}, WithWorkingDir(wd))
+
+	tests := []struct {
+		path string
+		want Decision
+	}{
+		{"src/main.go", Allow},
+		{"./src/pkg/new_file.go", Allow},
+		{filepath.Join(wd, "src", "main.go"), Allow},
+		{"link-to-src/main.go", Allow},
+		{"src/../README.md", Ask},
+		{"srcfoo/main.go", Ask},
+		{"src/escape/secret", Deny},
+		{"../outside.txt", Deny},
+		{"/etc/passwd", Deny},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, checker.CheckWithArgs("edit_file", map[string]any{"path": tt.path}))
+		})
+	}
+}
+
+func TestChecker_Explain(t *testing.T) {
+	t.Parallel()
+
+	checker := NewChecker(&latest.PermissionsConfig{
+		Allow: []string{"shell:cmd=ls*", "shell"},
+		Ask:   []string{"shell:cmd~=^git push"},
+		Deny:  []string{"shell:cmd=sudo*", "edit_file"},
+	})
+
+	explanation := checker.Explain("shell", map[string]any{"cmd": "git push"})
+	assert.Equal(t, Allow, explanation.Decision)
+	assert.Equal(t, "shell", explanation.Pattern)
+	assert.Equal(t, []RuleResult{
+		{List: Deny, Pattern: "shell:cmd=sudo*", Reason: "argument cmd does not match sudo*"},
+		{List: Deny, Pattern: "edit_file", Reason: "tool name does not match edit_file"},
+		{List: Allow, Pattern: "shell:cmd=ls*", Reason: "argument cmd does not match ls*"},
+		{List: Allow, Pattern: "shell", Matched: true},
+		{List: ForceAsk, Pattern: "shell:cmd~=^git push", Matched: true},
+	}, explanation.Rules)
+
+	decision, pattern := checker.Evaluate("shell", map[string]any{"cmd": "git push"})
+	assert.Equal(t, explanation.Decision, decision)
+	assert.Equal(t, explanation.Pattern, pattern)
+}
+
+func TestChecker_Validate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, NewChecker(&latest.PermissionsConfig{Allow: []string{"shell:cmd~=^ls( |$)"}}).Validate())
+	require.Error(t, NewChecker(&latest.PermissionsConfig{Deny: []string{"shell:cmd!~=rm ("}}).Validate())
+}
+
+func TestChecker_InvalidRegexp(t *testing.T) {
+	t.Parallel()
+
+	// A deny rule with an invalid expression matches, other rules don't.
+	checker := NewChecker(&latest.PermissionsConfig{
+		Allow: []string{"shell:cmd~=ls ("},
+		Deny:  []string{"shell:cmd~=rm ("},
+	})
+	assert.Equal(t, Deny, checker.CheckWithArgs("shell", map[string]any{"cmd": "ls"}))
+	assert.Equal(t, Ask, checker.CheckWithArgs("read_file", map[string]any{"cmd": "ls"}))
+
+	checker = NewChecker(&latest.PermissionsConfig{Allow: []string{"shell:cmd~=ls ("}})
+	assert.Equal(t, Ask, checker.CheckWithArgs("shell", map[string]any{"cmd": "ls"}))
+	explanation := checker.Explain("shell", map[string]any{"cmd": "ls"})
+	assert.Equal(t, `invalid regular expression "ls ("`, explanation.Rules[0].Reason)
+}
+
+func TestChecker_ForWorkingDir(t *testing.T) {
+	t.Parallel()
+
+	loadDir, sessionDir := t.TempDir(), t.TempDir()
+	checker := NewChecker(&latest.PermissionsConfig{Allow: []string{"edit_file:path^=./src"}}, WithWorkingDir(loadDir))
+	args := map[string]any{"path": filepath.Join(sessionDir, "src", "main.go")}
+
+	assert.Equal(t, Ask, checker.CheckWithArgs("edit_file", args))
+	assert.Equal(t, Allow, checker.ForWorkingDir(sessionDir).CheckWithArgs("edit_file", args))
+	assert.Same(t, checker, checker.ForWorkingDir(""))
+}
+
 func TestParsePattern(t *testing.T) {
 	t.Parallel()
 
@@ -494,6 +655,17 @@ func TestParsePattern(t *testing.T) {
 			wantTool:       "mcp:github:create_issue",
 			wantArgPattern: map[string]string{"repo": "owner/*"},
 		},
+		{
+			// Colons after the first argument belong to its value
+			pattern:        "fetch:url~=^https://docs\\.example\\.com(?:/|$)",
+			wantTool:       "fetch",
+			wantArgPattern: map[string]string{"url~": "^https://docs\\.example\\.com(?:/|$)"},
+		},
+		{
+			pattern:        "shell:cmd!~=rm\\s+-rf",
+			wantTool:       "shell",
+			wantArgPattern: map[string]string{"cmd!~": "rm\\s+-rf"},
+		},
 	}
 
 	for _, tt := range tests {
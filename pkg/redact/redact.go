@@ -0,0 +1,292 @@
+// Package redact removes secrets from text before it reaches a model or is
+// stored in a session.
+//
+// Three kinds of secrets are detected: well-known key and token formats
+// (cloud provider keys, API tokens, private keys, ...), literal values of
+// secret looking assignments such as `API_TOKEN=...` in a .env file or
+// `password = "..."` in code, and the values of the secret environment
+// variables resolved for the agent. High-entropy strings that don't match
+// any known format can be detected too.
+package redact
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Placeholder replaces every redacted secret.
+const Placeholder = "[REDACTED]"
+
+const (
+	// minValueLength is the length under which environment variable values
+	// are not redacted: short values are too likely to appear in regular text.
+	minValueLength = 8
+
+	// minEntropyLength and minEntropy are the length and the Shannon entropy,
+	// in bits per character, from which a token is considered a secret.
+	minEntropyLength = 20
+	minEntropy       = 4.0
+)
+
+// detector finds a kind of secret. When group is not zero, only that
+// submatch of the pattern is redacted.
+type detector struct {
+	pattern *regexp.Regexp
+	group   int
+}
+
+var detectors = []detector{
+	{pattern: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY( BLOCK)?-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY( BLOCK)?-----`)},
+	{pattern: regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`)},
+	{pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{pattern: regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}\b`)},
+	{pattern: regexp.MustCompile(`\bsk-ant-[A-Za-z0-9_-]{20,}`)},
+	{pattern: regexp.MustCompile(`\bsk-(?:proj-|svcacct-|admin-)?[A-Za-z0-9_-]{20,}`)},
+	{pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{pattern: regexp.MustCompile(`\bxox[abposr]-[0-9A-Za-z-]{10,}`)},
+	{pattern: regexp.MustCompile(`\b[rs]k_live_[0-9A-Za-z]{16,}\b`)},
+	{pattern: regexp.MustCompile(`\bdckr_pat_[A-Za-z0-9_-]{20,}`)},
+	{pattern: regexp.MustCompile(`\bhf_[A-Za-z0-9]{30,}\b`)},
+	{pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{pattern: regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^/\s:@]+:([^/\s@]+)@`), group: 1},
+	// Secret looking assignments only match literal values, quoted or on a
+	// line of their own as in .env files, so that identifiers and calls in
+	// source code, such as `Token: c.accessToken`, are left alone.
+	{pattern: regexp.MustCompile(`(?i)\b[A-Z0-9_.-]*` + secretAssignmentName + `[A-Z0-9_]*["']?\s*(?::=|=>|[:=])\s*"([^"\s]{8,})"`), group: 1},
+	{pattern: regexp.MustCompile(`(?i)\b[A-Z0-9_.-]*` + secretAssignmentName + `[A-Z0-9_]*["']?\s*(?::=|=>|[:=])\s*'([^'\s]{8,})'`), group: 1},
+	{pattern: regexp.MustCompile(`(?m)^[ \t]*(?:export[ \t]+)?[A-Z0-9_]*` + secretAssignmentName + `[A-Z0-9_]*=([^\s"'$()\x60]{8,})[ \t]*\r?$`), group: 1},
+}
+
+// secretAssignmentName matches the part of the names of assigned variables
+// or keys that makes them look like secrets.
+const secretAssignmentName = `(?:SECRET|PASSWORD|PASSWD|TOKEN|API_?KEY|ACCESS_?KEY|PRIVATE_?KEY|CREDENTIALS?)`
+
+// secretName matches the names of the environment variables that hold secrets.
+var secretName = regexp.MustCompile(`(?i)(?:SECRET|PASSWORD|PASSWD|TOKEN|API_?KEY|ACCESS_?KEY|PRIVATE_?KEY|CREDENTIAL|AUTH)`)
+
+// entropyCandidate matches the tokens whose entropy is measured.
+var entropyCandidate = regexp.MustCompile(`[A-Za-z0-9+/=_-]{20,}`)
+
+// Options configures a [Redactor].
+type Options struct {
+	// Entropy enables the detection of high-entropy strings.
+	Entropy bool
+	// Allow lists regular expressions of values that are never redacted.
+	// Each expression must match the whole value.
+	Allow []string
+	// Env returns the environment variables resolved for the agent, by name.
+	// The values of the variables named like secrets (API_KEY, TOKEN, ...)
+	// are redacted wherever they appear. It is called on every redaction since
+	// variables can be resolved while the agent runs.
+	Env func() map[string]string
+}
+
+// Redactor replaces secrets in text with [Placeholder].
+type Redactor struct {
+	entropy bool
+	allow   []*regexp.Regexp
+	env     func() map[string]string
+}
+
+// New creates a redactor. It returns an error if an allowlist expression is invalid.
+func New(opts Options) (*Redactor, error) {
+	r := &Redactor{
+		entropy: opts.Entropy,
+		env:     opts.Env,
+	}
+	for _, expr := range opts.Allow {
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction allowlist expression %q: %w", expr, err)
+		}
+		r.allow = append(r.allow, re)
+	}
+	return r, nil
+}
+
+// span is the position of a secret in the redacted text.
+type span struct {
+	start, end int
+}
+
+// Redact returns text with its secrets replaced, and the number of secrets
+// that were replaced.
+func (r *Redactor) Redact(text string) (string, int) {
+	if text == "" {
+		return text, 0
+	}
+
+	var spans []span
+	add := func(start, end int) {
+		if !r.allowed(text[start:end]) {
+			spans = append(spans, span{start: start, end: end})
+		}
+	}
+
+	for _, d := range detectors {
+		for _, loc := range d.pattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := loc[2*d.group], loc[2*d.group+1]
+			if start >= 0 {
+				add(start, end)
+			}
+		}
+	}
+
+	if r.env != nil {
+		for name, value := range r.env() {
+			if len(value) < minValueLength || !secretName.MatchString(name) {
+				continue
+			}
+			for offset := 0; ; {
+				i := strings.Index(text[offset:], value)
+				if i < 0 {
+					break
+				}
+				add(offset+i, offset+i+len(value))
+				offset += i + len(value)
+			}
+		}
+	}
+
+	if r.entropy {
+		for _, loc := range entropyCandidate.FindAllStringIndex(text, -1) {
+			if isHighEntropy(text[loc[0]:loc[1]]) {
+				add(loc[0], loc[1])
+			}
+		}
+	}
+
+	if len(spans) == 0 {
+		return text, 0
+	}
+	spans = merge(spans)
+
+	var b strings.Builder
+	b.Grow(len(text))
+	last := 0
+	for _, s := range spans {
+		b.WriteString(text[last:s.start])
+		b.WriteString(Placeholder)
+		last = s.end
+	}
+	b.WriteString(text[last:])
+
+	return b.String(), len(spans)
+}
+
+func (r *Redactor) allowed(value string) bool {
+	for _, re := range r.allow {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// merge sorts the spans and merges the ones that overlap.
+func merge(spans []span) []span {
+	slices.SortFunc(spans, func(a, b span) int {
+		return cmp.Or(cmp.Compare(a.start, b.start), cmp.Compare(b.end, a.end))
+	})
+
+	merged := spans[:1]
+	for _, s := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if s.start < last.end {
+			last.end = max(last.end, s.end)
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// isHighEntropy reports whether token looks like a random secret: long
+// enough, mixing letters and digits, and with a high Shannon entropy. Hex
+// strings, such as git commit hashes and UUIDs, are not considered secrets.
+func isHighEntropy(token string) bool {
+	if len(token) < minEntropyLength {
+		return false
+	}
+	if !strings.ContainsAny(token, "0123456789") || strings.IndexFunc(token, isLetter) < 0 {
+		return false
+	}
+	if strings.Trim(token, "0123456789abcdefABCDEF-") == "" {
+		return false
+	}
+	return shannonEntropy(token) >= minEntropy
+}
+
+func isLetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+// shannonEntropy returns the entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	var counts [256]int
+	for i := range len(s) {
+		counts[s[i]]++
+	}
+
+	var entropy float64
+	n := float64(len(s))
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// secretKey matches the names of the tool call arguments that hold secrets.
+var secretKey = regexp.MustCompile(`(?i)(?:SECRET|PASSWORD|PASSWD|TOKEN|API_?KEY|ACCESS_?KEY|PRIVATE_?KEY|CREDENTIAL|AUTHORIZATION|COOKIE)`)
+
+// RedactArguments returns a copy of the arguments of a tool call where the
+// values of the arguments named like secrets (password, token, api_key, ...)
+// are replaced at any depth, and the secrets found in the other strings are
+// redacted as by [Redactor.Redact].
+func (r *Redactor) RedactArguments(args map[string]any) map[string]any {
+	if args == nil {
+		return nil
+	}
+	redacted := make(map[string]any, len(args))
+	for key, value := range args {
+		if secretKey.MatchString(key) && !r.allowedValue(value) {
+			redacted[key] = Placeholder
+		} else {
+			redacted[key] = r.redactValue(value)
+		}
+	}
+	return redacted
+}
+
+func (r *Redactor) redactValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		text, _ := r.Redact(v)
+		return text
+	case map[string]any:
+		return r.RedactArguments(v)
+	case []any:
+		values := make([]any, len(v))
+		for i, item := range v {
+			values[i] = r.redactValue(item)
+		}
+		return values
+	default:
+		return value
+	}
+}
+
+// allowedValue reports whether the value of a secret looking argument is
+// empty or allowlisted, and can be kept.
+func (r *Redactor) allowedValue(value any) bool {
+	s, ok := value.(string)
+	return ok && (s == "" || r.allowed(s))
+}
@@ -13,6 +13,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/hooks"
 	"github.com/docker/docker-agent/pkg/session"
 	"github.com/docker/docker-agent/pkg/tools"
 	"github.com/docker/docker-agent/pkg/tools/builtin"
@@ -145,6 +146,25 @@ func (r *LocalRuntime) handleTaskTransfer(ctx context.Context, sess *session.Ses
 		return errResult, nil
 	}
 
+	// Pre-delegation hooks can add context for the sub-agent or block the transfer.
+	ctx = withDelegation(ctx, a.Name())
+	if result := r.runLifecycleHooks(ctx, a, preDelegationEvent, evts, func() *hooks.Input {
+		return &hooks.Input{
+			SessionID:       sess.ID,
+			TargetAgent:     params.Agent,
+			DelegationType:  "transfer_task",
+			Task:            params.Task,
+			DelegationChain: delegationChain(ctx),
+		}
+	}); result != nil {
+		if !result.Allowed {
+			return tools.ResultError(hookMessage("Task transfer blocked by a hook", result.Message)), nil
+		}
+		if result.AdditionalContext != "" {
+			params.Task += "\n\n" + result.AdditionalContext
+		}
+	}
+
 	ctx, span := r.startSpan(ctx, "runtime.task_transfer", trace.WithAttributes(
 		attribute.String("from.agent", a.Name()),
 		attribute.String("to.agent", params.Agent),
@@ -220,7 +240,7 @@ func (r *LocalRuntime) runSubSession(ctx context.Context, parent, child *session
 	return tools.ResultSuccess(child.GetLastAssistantMessageContent()), nil
 }
 
-func (r *LocalRuntime) handleHandoff(_ context.Context, _ *session.Session, toolCall tools.ToolCall, _ chan Event) (*tools.ToolCallResult, error) {
+func (r *LocalRuntime) handleHandoff(ctx context.Context, sess *session.Session, toolCall tools.ToolCall, evts chan Event) (*tools.ToolCallResult, error) {
 	var params builtin.HandoffArgs
 	if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
 		return nil, fmt.Errorf("invalid arguments: %w", err)
@@ -242,6 +262,22 @@ func (r *LocalRuntime) handleHandoff(_ context.Context, _ *session.Session, tool
 		return nil, err
 	}
 
+	// Pre-delegation hooks can add context for the next agent or block the handoff.
+	var additionalContext string
+	if result := r.runLifecycleHooks(ctx, currentAgent, preDelegationEvent, evts, func() *hooks.Input {
+		return &hooks.Input{
+			SessionID:       sess.ID,
+			TargetAgent:     next.Name(),
+			DelegationType:  "handoff",
+			DelegationChain: append(delegationChain(ctx), ca),
+		}
+	}); result != nil {
+		if !result.Allowed {
+			return tools.ResultError(hookMessage("Handoff blocked by a hook", result.Message)), nil
+		}
+		additionalContext = result.AdditionalContext
+	}
+
 	r.setCurrentAgent(next.Name())
 	handoffMessage := "The agent " + ca + " handed off the conversation to you. " +
 		"Your available handoff agents and tools are specified in the system messages that follow. " +
@@ -251,5 +287,8 @@ func (r *LocalRuntime) handleHandoff(_ context.Context, _ *session.Session, tool
 		"handoff agents and tools that are listed in your system messages below. " +
 		"Complete your part of the task and hand off to the next appropriate agent in your workflow " +
 		"(if any are available to you), or respond directly to the user if you are the final agent."
+	if additionalContext != "" {
+		handoffMessage += "\n\n" + additionalContext
+	}
 	return tools.ResultSuccess(handoffMessage), nil
 }
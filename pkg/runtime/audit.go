@@ -0,0 +1,90 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/audit"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// WithAuditLog records in log every decision taken on a tool call, and the
+// outcome of the tool calls that ran.
+func WithAuditLog(log *audit.Log) Opt {
+	return func(r *LocalRuntime) {
+		r.auditLog = log
+	}
+}
+
+// toolApproval is who or what allowed a tool call to run.
+type toolApproval struct {
+	by      audit.Source
+	pattern string
+}
+
+type toolApprovalKey struct{}
+
+// withToolApproval records in the context of a tool call who or what allowed it to run.
+func withToolApproval(ctx context.Context, approval toolApproval) context.Context {
+	return context.WithValue(ctx, toolApprovalKey{}, approval)
+}
+
+func toolApprovalFrom(ctx context.Context) toolApproval {
+	approval, _ := ctx.Value(toolApprovalKey{}).(toolApproval)
+	return approval
+}
+
+// auditToolDenied records a tool call that was not allowed to run.
+func (r *LocalRuntime) auditToolDenied(sess *session.Session, a *agent.Agent, toolCall tools.ToolCall, decision audit.Decision, by audit.Source, pattern, reason string) {
+	r.appendAuditRecord(sess, a, toolCall, audit.Record{
+		Decision:  decision,
+		DecidedBy: by,
+		Pattern:   pattern,
+		Reason:    reason,
+	})
+}
+
+// auditToolResult records a tool call that ran, along with who or what allowed it.
+func (r *LocalRuntime) auditToolResult(ctx context.Context, sess *session.Session, a *agent.Agent, toolCall tools.ToolCall, approval toolApproval, res *tools.ToolCallResult, err error, duration time.Duration) {
+	status := audit.StatusSuccess
+	switch {
+	case err != nil && (errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled)):
+		status = audit.StatusCanceled
+	case err != nil || (res != nil && res.IsError):
+		status = audit.StatusError
+	}
+
+	r.appendAuditRecord(sess, a, toolCall, audit.Record{
+		Decision:   audit.DecisionAllow,
+		DecidedBy:  approval.by,
+		Pattern:    approval.pattern,
+		DurationMs: duration.Milliseconds(),
+		Status:     status,
+	})
+}
+
+func (r *LocalRuntime) appendAuditRecord(sess *session.Session, a *agent.Agent, toolCall tools.ToolCall, rec audit.Record) {
+	if r.auditLog == nil {
+		return
+	}
+
+	rec.SessionID = sess.ID
+	rec.Owner = sess.Owner
+	rec.Agent = a.Name()
+	rec.ToolCallID = toolCall.ID
+	rec.Tool = toolCall.Function.Name
+	rec.Arguments = parseToolInput(toolCall.Function.Arguments)
+	// The log redacts the well-known secrets; the agent's redactor also knows
+	// the values of its secret environment variables.
+	if redactor := a.Redactor(); redactor != nil {
+		rec.Arguments = redactor.RedactArguments(rec.Arguments)
+	}
+
+	if err := r.auditLog.Append(rec); err != nil {
+		slog.Error("Failed to write audit record", "tool", rec.Tool, "session_id", sess.ID, "error", err)
+	}
+}
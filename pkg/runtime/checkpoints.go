@@ -0,0 +1,73 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/checkpoint"
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+// ErrCheckpointsDisabled is returned when undoing a turn with a runtime that
+// doesn't snapshot files.
+var ErrCheckpointsDisabled = errors.New("checkpoints are not enabled")
+
+// Checkpointer is an optional interface for runtimes that snapshot the files
+// changed by tools, so that the workspace can be restored to its state before
+// a turn. This is used by the TUI for the /undo command.
+type Checkpointer interface {
+	// Checkpoints returns the turns of the session that changed files, oldest first.
+	Checkpoints(ctx context.Context, sess *session.Session) ([]checkpoint.Turn, error)
+
+	// RestoreCheckpoint restores the files changed since the start of the
+	// given turn and returns their paths.
+	RestoreCheckpoint(ctx context.Context, sess *session.Session, turn int) ([]string, error)
+}
+
+var _ Checkpointer = (*LocalRuntime)(nil)
+
+// WithCheckpoints snapshots the files changed by tools into store, grouped
+// by user turn.
+func WithCheckpoints(store *checkpoint.Store) Opt {
+	return func(r *LocalRuntime) {
+		r.checkpoints = store
+	}
+}
+
+// Checkpoints implements [Checkpointer].
+func (r *LocalRuntime) Checkpoints(_ context.Context, sess *session.Session) ([]checkpoint.Turn, error) {
+	if r.checkpoints == nil {
+		return nil, ErrCheckpointsDisabled
+	}
+	return r.checkpoints.Turns(sess.ID)
+}
+
+// RestoreCheckpoint implements [Checkpointer].
+func (r *LocalRuntime) RestoreCheckpoint(_ context.Context, sess *session.Session, turn int) ([]string, error) {
+	if r.checkpoints == nil {
+		return nil, ErrCheckpointsDisabled
+	}
+	return r.checkpoints.Restore(sess.ID, turn)
+}
+
+// withCheckpointRecorder makes the tools of this run snapshot the files they
+// change into the current turn of sess. Sub-sessions keep recording into the
+// turn of the session that started them.
+func (r *LocalRuntime) withCheckpointRecorder(ctx context.Context, sess *session.Session) context.Context {
+	if r.checkpoints == nil || checkpoint.HasRecorder(ctx) {
+		return ctx
+	}
+	return checkpoint.WithRecorder(ctx, checkpoint.NewRecorder(r.checkpoints, sess.ID, currentTurn(sess)))
+}
+
+// currentTurn returns the 1-based index of the last user message of sess.
+func currentTurn(sess *session.Session) int {
+	turn := 0
+	for _, item := range sess.Messages {
+		if item.IsMessage() && !item.Message.Implicit && item.Message.Message.Role == chat.MessageRoleUser {
+			turn++
+		}
+	}
+	return turn
+}
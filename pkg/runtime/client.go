@@ -63,6 +63,7 @@ func NewClient(baseURL string, opts ...ClientOption) (*Client, error) {
 			"tool_call":              func() Event { return &ToolCallEvent{} },
 			"tool_call_response":     func() Event { return &ToolCallResponseEvent{} },
 			"tool_call_confirmation": func() Event { return &ToolCallConfirmationEvent{} },
+			"tool_call_progress":     func() Event { return &ToolCallProgressEvent{} },
 			"token_usage":            func() Event { return &TokenUsageEvent{} },
 			"stream_stopped":         func() Event { return &StreamStoppedEvent{} },
 			"stream_started":         func() Event { return &StreamStartedEvent{} },
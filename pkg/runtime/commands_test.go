@@ -64,6 +64,16 @@ func (m *mockRuntime) ExecuteMCPPrompt(context.Context, string, map[string]strin
 	return "", nil
 }
 
+func (m *mockRuntime) CurrentMCPResources(context.Context) []mcptools.ResourceInfo {
+	return nil
+}
+
+func (m *mockRuntime) DetachMCPResources(string) {}
+
+func (m *mockRuntime) AttachMCPResource(context.Context, *session.Session, string) (string, error) {
+	return "", nil
+}
+
 func (m *mockRuntime) UpdateSessionTitle(context.Context, *session.Session, string) error {
 	return nil
 }
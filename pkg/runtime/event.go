@@ -2,6 +2,8 @@ package runtime
 
 import (
 	"cmp"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/docker/docker-agent/pkg/chat"
@@ -98,14 +100,17 @@ type ToolCallConfirmationEvent struct {
 	Type           string         `json:"type"`
 	ToolCall       tools.ToolCall `json:"tool_call"`
 	ToolDefinition tools.Tool     `json:"tool_definition"`
+	// Reason explains which permission rule, if any, requires the confirmation.
+	Reason string `json:"reason,omitempty"`
 	AgentContext
 }
 
-func ToolCallConfirmation(toolCall tools.ToolCall, toolDefinition tools.Tool, agentName string) Event {
+func ToolCallConfirmation(toolCall tools.ToolCall, toolDefinition tools.Tool, reason, agentName string) Event {
 	return &ToolCallConfirmationEvent{
 		Type:           "tool_call_confirmation",
 		ToolCall:       toolCall,
 		ToolDefinition: toolDefinition,
+		Reason:         reason,
 		AgentContext:   newAgentContext(agentName),
 	}
 }
@@ -130,6 +135,49 @@ func ToolCallResponse(toolCall tools.ToolCall, toolDefinition tools.Tool, result
 	}
 }
 
+// ToolCallProgressEvent is sent when a running tool call reports its
+// progress, like MCP tools sending progress notifications or log messages.
+// Total is 0 when it is unknown.
+type ToolCallProgressEvent struct {
+	Type       string  `json:"type"`
+	ToolCallID string  `json:"tool_call_id"`
+	Progress   float64 `json:"progress,omitempty"`
+	Total      float64 `json:"total,omitempty"`
+	Message    string  `json:"message,omitempty"`
+	AgentContext
+}
+
+func ToolCallProgress(toolCallID string, progress tools.ToolProgress, agentName string) Event {
+	return &ToolCallProgressEvent{
+		Type:         "tool_call_progress",
+		ToolCallID:   toolCallID,
+		Progress:     progress.Progress,
+		Total:        progress.Total,
+		Message:      progress.Message,
+		AgentContext: newAgentContext(agentName),
+	}
+}
+
+// Text describes the progress for display, e.g. "Building (42%)".
+func (e *ToolCallProgressEvent) Text() string {
+	var amount string
+	switch {
+	case e.Total > 0:
+		amount = fmt.Sprintf("%.0f%%", 100*min(e.Progress/e.Total, 1))
+	case e.Message == "" && e.Progress > 0:
+		amount = strconv.FormatFloat(e.Progress, 'f', -1, 64)
+	}
+
+	switch {
+	case e.Message == "":
+		return amount
+	case amount == "":
+		return e.Message
+	default:
+		return e.Message + " (" + amount + ")"
+	}
+}
+
 type StreamStartedEvent struct {
 	Type      string `json:"type"`
 	SessionID string `json:"session_id,omitempty"`
@@ -0,0 +1,28 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolCallProgressEvent_Text(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		event ToolCallProgressEvent
+		want  string
+	}{
+		{ToolCallProgressEvent{Message: "Building"}, "Building"},
+		{ToolCallProgressEvent{Progress: 42, Total: 100}, "42%"},
+		{ToolCallProgressEvent{Progress: 3, Total: 4, Message: "Scanning"}, "Scanning (75%)"},
+		{ToolCallProgressEvent{Progress: 12}, "12"},
+		{ToolCallProgressEvent{Progress: 12, Message: "Downloading"}, "Downloading"},
+		{ToolCallProgressEvent{Progress: 5, Total: 4}, "100%"},
+		{ToolCallProgressEvent{}, ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.event.Text())
+	}
+}
@@ -0,0 +1,135 @@
+package runtime
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/hooks"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// lifecycleEvent is a model call, compaction or delegation event hooks can run on.
+type lifecycleEvent struct {
+	// has reports whether an executor has hooks for the event.
+	has func(*hooks.Executor) bool
+	// execute runs the hooks of the event on an executor.
+	execute func(*hooks.Executor, context.Context, *hooks.Input) (*hooks.Result, error)
+}
+
+var (
+	preModelCallEvent   = lifecycleEvent{(*hooks.Executor).HasPreModelCallHooks, (*hooks.Executor).ExecutePreModelCall}
+	postModelCallEvent  = lifecycleEvent{(*hooks.Executor).HasPostModelCallHooks, (*hooks.Executor).ExecutePostModelCall}
+	preCompactionEvent  = lifecycleEvent{(*hooks.Executor).HasPreCompactionHooks, (*hooks.Executor).ExecutePreCompaction}
+	postCompactionEvent = lifecycleEvent{(*hooks.Executor).HasPostCompactionHooks, (*hooks.Executor).ExecutePostCompaction}
+	preDelegationEvent  = lifecycleEvent{(*hooks.Executor).HasPreDelegationHooks, (*hooks.Executor).ExecutePreDelegation}
+)
+
+// runLifecycleHooks runs the hooks of a model call, compaction or delegation
+// event for an agent. The hook input is only built when the agent has hooks
+// for the event. System messages of the hooks are emitted as warnings.
+//
+// Lifecycle hooks fail open: it returns nil when the agent has no hooks for
+// the event, and also when they failed to run, after logging a warning.
+func (r *LocalRuntime) runLifecycleHooks(ctx context.Context, a *agent.Agent, event lifecycleEvent, events chan Event, input func() *hooks.Input) *hooks.Result {
+	hooksExec := r.getHooksExecutor(a)
+	if hooksExec == nil || !event.has(hooksExec) {
+		return nil
+	}
+
+	in := input()
+	in.Cwd = r.workingDir
+	in.AgentName = a.Name()
+
+	result, err := event.execute(hooksExec, ctx, in)
+	if err != nil {
+		slog.Warn("Hook execution failed, continuing without it", "event", in.HookEventName, "agent", a.Name(), "error", err)
+		return nil
+	}
+	if result.SystemMessage != "" {
+		events <- Warning(result.SystemMessage, a.Name())
+	}
+	return result
+}
+
+// stopByHook ends the agent's turn with a message explaining that a hook stopped it.
+func stopByHook(sess *session.Session, a *agent.Agent, message string, events chan Event) {
+	slog.Debug("Hook stopped the agent", "agent", a.Name(), "session_id", sess.ID, "message", message)
+
+	assistantMessage := chat.Message{
+		Role:      chat.MessageRoleAssistant,
+		Content:   hookMessage("Execution stopped by a hook", message),
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+	addAgentMessage(sess, a, &assistantMessage, events)
+}
+
+// hookMessage appends the message of a hook, if any, to what the hook did.
+func hookMessage(what, message string) string {
+	if message == "" {
+		return what + "."
+	}
+	return what + ": " + message
+}
+
+// withAdditionalContext adds context from hooks to the messages of a single
+// model request, as a system message after the leading system messages.
+func withAdditionalContext(messages []chat.Message, additionalContext string) []chat.Message {
+	i := slices.IndexFunc(messages, func(m chat.Message) bool { return m.Role != chat.MessageRoleSystem })
+	if i < 0 {
+		i = len(messages)
+	}
+	return slices.Insert(slices.Clone(messages), i, chat.Message{Role: chat.MessageRoleSystem, Content: additionalContext})
+}
+
+// sessionHookUsage returns the token usage and cost of a session so far, sub-sessions included.
+func sessionHookUsage(sess *session.Session) *hooks.Usage {
+	return &hooks.Usage{
+		InputTokens:  sess.InputTokens,
+		OutputTokens: sess.OutputTokens,
+		Cost:         sess.TotalCost(),
+	}
+}
+
+// messageHookUsage returns the token usage and cost of a model response.
+func messageHookUsage(usage *MessageUsage) *hooks.Usage {
+	if usage == nil {
+		return nil
+	}
+	return &hooks.Usage{
+		InputTokens:       usage.InputTokens,
+		OutputTokens:      usage.OutputTokens,
+		CachedInputTokens: usage.CachedInputTokens,
+		CacheWriteTokens:  usage.CacheWriteTokens,
+		Cost:              usage.Cost,
+	}
+}
+
+type delegationChainKey struct{}
+
+// withDelegation records that agentName delegated a task in the context of the sub-session.
+func withDelegation(ctx context.Context, agentName string) context.Context {
+	return context.WithValue(ctx, delegationChainKey{}, append(delegationChain(ctx), agentName))
+}
+
+// delegationChain returns the agents that delegated the task being run, from the root agent down.
+func delegationChain(ctx context.Context) []string {
+	chain, _ := ctx.Value(delegationChainKey{}).([]string)
+	return slices.Clip(chain)
+}
+
+// skipToolCalls answers tool calls that won't run with an error, so that
+// every tool call of the session keeps a response.
+func (r *LocalRuntime) skipToolCalls(ctx context.Context, sess *session.Session, calls []tools.ToolCall, agentTools []tools.Tool, events chan Event, a *agent.Agent) {
+	for _, toolCall := range calls {
+		tool := tools.Tool{Name: toolCall.Function.Name}
+		if i := slices.IndexFunc(agentTools, func(t tools.Tool) bool { return t.Name == toolCall.Function.Name }); i >= 0 {
+			tool = agentTools[i]
+		}
+		r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, "Tool call skipped: execution was stopped by a hook.")
+	}
+}
@@ -0,0 +1,300 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/hooks"
+	"github.com/docker/docker-agent/pkg/model/provider/base"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/team"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// recordingProvider returns its stream and records the messages of each request.
+type recordingProvider struct {
+	mockProvider
+
+	mu       sync.Mutex
+	requests [][]chat.Message
+}
+
+func (p *recordingProvider) CreateChatCompletionStream(ctx context.Context, messages []chat.Message, agentTools []tools.Tool) (chat.MessageStream, error) {
+	p.mu.Lock()
+	p.requests = append(p.requests, messages)
+	p.mu.Unlock()
+	return p.mockProvider.CreateChatCompletionStream(ctx, messages, agentTools)
+}
+
+func (p *recordingProvider) BaseConfig() base.Config { return base.Config{} }
+
+func runWithHooks(t *testing.T, hooksCfg *latest.HooksConfig, prov *recordingProvider) *session.Session {
+	t.Helper()
+
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov), agent.WithHooks(hooksCfg))
+	rt, err := NewLocalRuntime(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Hello"))
+	for range rt.RunStream(t.Context(), sess) {
+	}
+	return sess
+}
+
+func commandHook(command string) []latest.HookDefinition {
+	return []latest.HookDefinition{{Type: "command", Command: command}}
+}
+
+func readHookInput(t *testing.T, path string) hooks.Input {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var input hooks.Input
+	require.NoError(t, json.Unmarshal(data, &input))
+	return input
+}
+
+func TestPreModelCallHookStopsAgent(t *testing.T) {
+	prov := &recordingProvider{mockProvider: mockProvider{id: "test/mock-model", stream: newStreamBuilder().AddContent("Hi").AddStopWithUsage(1, 1).Build()}}
+
+	sess := runWithHooks(t, &latest.HooksConfig{
+		PreModelCall: commandHook("echo 'Budget exceeded' >&2; exit 2"),
+	}, prov)
+
+	assert.Empty(t, prov.requests)
+	assert.Equal(t, "Execution stopped by a hook: Budget exceeded", sess.GetLastAssistantMessageContent())
+}
+
+func TestPreModelCallHookAddsContext(t *testing.T) {
+	prov := &recordingProvider{mockProvider: mockProvider{id: "test/mock-model", stream: newStreamBuilder().AddContent("Hi").AddStopWithUsage(1, 1).Build()}}
+
+	runWithHooks(t, &latest.HooksConfig{
+		PreModelCall: commandHook("echo 'Keep answers short'"),
+	}, prov)
+
+	require.Len(t, prov.requests, 1)
+	messages := prov.requests[0]
+	i := len(messages) - 2
+	assert.Equal(t, chat.MessageRoleSystem, messages[i].Role)
+	assert.Equal(t, "Keep answers short", messages[i].Content)
+	assert.Equal(t, chat.MessageRoleUser, messages[i+1].Role)
+}
+
+func TestPostModelCallHookReceivesUsage(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "input.json")
+	prov := &recordingProvider{mockProvider: mockProvider{id: "test/mock-model", stream: newStreamBuilder().AddContent("Hi").AddStopWithUsage(10, 5).Build()}}
+
+	sess := runWithHooks(t, &latest.HooksConfig{
+		PostModelCall: commandHook("cat > " + inputFile),
+	}, prov)
+
+	input := readHookInput(t, inputFile)
+	assert.Equal(t, hooks.EventPostModelCall, input.HookEventName)
+	assert.Equal(t, sess.ID, input.SessionID)
+	assert.Equal(t, "root", input.AgentName)
+	assert.Equal(t, "test/mock-model", input.Model)
+	require.NotNil(t, input.Usage)
+	assert.Equal(t, int64(10), input.Usage.InputTokens)
+	assert.Equal(t, int64(5), input.Usage.OutputTokens)
+	require.NotNil(t, input.SessionUsage)
+	assert.Equal(t, int64(10), input.SessionUsage.InputTokens)
+}
+
+func TestPreDelegationHookBlocksTransferTask(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "input.json")
+	prov := &mockProvider{id: "test/mock-model", stream: &mockStream{}}
+
+	librarian := agent.New("librarian", "Library agent", agent.WithModel(prov))
+	root := agent.New("root", "Root agent", agent.WithModel(prov), agent.WithSubAgents(librarian), agent.WithHooks(&latest.HooksConfig{
+		PreDelegation: commandHook("cat > " + inputFile + "; echo 'Librarian is off duty' >&2; exit 2"),
+	}))
+
+	rt, err := NewLocalRuntime(team.New(team.WithAgents(root, librarian)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Test"))
+	result, err := rt.handleTaskTransfer(t.Context(), sess, tools.ToolCall{
+		ID:       "call_1",
+		Type:     "function",
+		Function: tools.FunctionCall{Name: "transfer_task", Arguments: `{"agent":"librarian","task":"find a book"}`},
+	}, make(chan Event, 128))
+	require.NoError(t, err)
+
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Task transfer blocked by a hook: Librarian is off duty", result.Output)
+	assert.Equal(t, "root", rt.currentAgent)
+
+	input := readHookInput(t, inputFile)
+	assert.Equal(t, "librarian", input.TargetAgent)
+	assert.Equal(t, "transfer_task", input.DelegationType)
+	assert.Equal(t, "find a book", input.Task)
+	assert.Equal(t, []string{"root"}, input.DelegationChain)
+}
+
+func TestWithAdditionalContext(t *testing.T) {
+	t.Parallel()
+
+	messages := []chat.Message{
+		{Role: chat.MessageRoleSystem, Content: "instructions"},
+		{Role: chat.MessageRoleUser, Content: "hello"},
+	}
+
+	result := withAdditionalContext(messages, "context")
+
+	assert.Equal(t, []chat.Message{
+		{Role: chat.MessageRoleSystem, Content: "instructions"},
+		{Role: chat.MessageRoleSystem, Content: "context"},
+		{Role: chat.MessageRoleUser, Content: "hello"},
+	}, result)
+	assert.Len(t, messages, 2)
+}
+
+func TestDelegationChain(t *testing.T) {
+	t.Parallel()
+
+	ctx := withDelegation(t.Context(), "root")
+	nested := withDelegation(ctx, "planner")
+	sibling := withDelegation(ctx, "librarian")
+
+	assert.Empty(t, delegationChain(t.Context()))
+	assert.Equal(t, []string{"root"}, delegationChain(ctx))
+	assert.Equal(t, []string{"root", "planner"}, delegationChain(nested))
+	assert.Equal(t, []string{"root", "librarian"}, delegationChain(sibling))
+}
+
+// runToolWithPreToolHooks processes a call to a read-only tool, which would
+// be approved without asking, on an agent with the given pre-tool-use hooks.
+// respond is called with the confirmation requested by the runtime, if any.
+func runToolWithPreToolHooks(t *testing.T, hookDefs []latest.HookDefinition, sess *session.Session, respond func(rt *LocalRuntime, confirmation *ToolCallConfirmationEvent)) (called bool, response *ToolCallResponseEvent) {
+	t.Helper()
+
+	prov := &mockProvider{id: "test/mock-model", stream: newStreamBuilder().AddContent(`not a verdict`).AddStopWithUsage(1, 1).Build()}
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov), agent.WithHooks(&latest.HooksConfig{
+		PreToolUse: []latest.HookMatcherConfig{{Matcher: "*", Hooks: hookDefs}},
+	}))
+	rt, err := NewLocalRuntime(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	agentTools := []tools.Tool{{
+		Name:        "read",
+		Annotations: tools.ToolAnnotations{ReadOnlyHint: true},
+		Handler: func(context.Context, tools.ToolCall) (*tools.ToolCallResult, error) {
+			called = true
+			return tools.ResultSuccess("ok"), nil
+		},
+	}}
+	calls := []tools.ToolCall{{ID: "call_1", Type: "function", Function: tools.FunctionCall{Name: "read", Arguments: "{}"}}}
+
+	events := make(chan Event, 128)
+	go func() {
+		rt.processToolCalls(t.Context(), sess, calls, agentTools, events)
+		close(events)
+	}()
+	for ev := range events {
+		switch ev := ev.(type) {
+		case *ToolCallConfirmationEvent:
+			respond(rt, ev)
+		case *ToolCallResponseEvent:
+			response = ev
+		}
+	}
+	return called, response
+}
+
+func askHook(reason string) []latest.HookDefinition {
+	return commandHook(`echo '{"hook_specific_output":{"permission_decision":"ask","permission_decision_reason":"` + reason + `"}}'`)
+}
+
+func TestPreToolHookAskRequiresConfirmation(t *testing.T) {
+	t.Run("approved", func(t *testing.T) {
+		var reason string
+		called, response := runToolWithPreToolHooks(t, askHook("Looks risky"), session.New(), func(rt *LocalRuntime, confirmation *ToolCallConfirmationEvent) {
+			reason = confirmation.Reason
+			rt.resumeChan <- ResumeApprove()
+		})
+
+		assert.Equal(t, "Looks risky", reason)
+		assert.True(t, called)
+		require.NotNil(t, response)
+		assert.False(t, response.Result.IsError)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		called, response := runToolWithPreToolHooks(t, askHook("Looks risky"), session.New(), func(rt *LocalRuntime, _ *ToolCallConfirmationEvent) {
+			rt.resumeChan <- ResumeReject("")
+		})
+
+		assert.False(t, called)
+		require.NotNil(t, response)
+		assert.True(t, response.Result.IsError)
+	})
+
+	t.Run("auto-approved session", func(t *testing.T) {
+		called, response := runToolWithPreToolHooks(t, askHook("Looks risky"), session.New(session.WithToolsApproved(true)), func(*LocalRuntime, *ToolCallConfirmationEvent) {
+			t.Error("unexpected confirmation request")
+		})
+
+		assert.False(t, called)
+		require.NotNil(t, response)
+		assert.True(t, response.Result.IsError)
+		assert.Contains(t, response.Response, "nobody can confirm it")
+	})
+}
+
+func TestPromptHookErrorBlocksToolCall(t *testing.T) {
+	// The agent's model doesn't answer with a valid verdict.
+	called, response := runToolWithPreToolHooks(t, []latest.HookDefinition{{Type: "prompt", Prompt: "Deny everything risky"}}, session.New(), func(*LocalRuntime, *ToolCallConfirmationEvent) {
+		t.Error("unexpected confirmation request")
+	})
+
+	assert.False(t, called)
+	require.NotNil(t, response)
+	assert.True(t, response.Result.IsError)
+	assert.Contains(t, response.Response, "parsing prompt hook verdict")
+}
+
+func TestPostCompactionHookContextIsPersisted(t *testing.T) {
+	store, err := session.NewSQLiteSessionStore(filepath.Join(t.TempDir(), "sessions.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+
+	prov := &queueProvider{id: "test/mock-model", streams: []chat.MessageStream{
+		newStreamBuilder().AddContent("Hello there").AddStopWithUsage(101, 0).Build(),
+		newStreamBuilder().AddContent("The summary").AddStopWithUsage(1, 1).Build(),
+		newStreamBuilder().AddContent("Hi again").AddStopWithUsage(1, 1).Build(),
+	}}
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov), agent.WithHooks(&latest.HooksConfig{
+		PostCompaction: commandHook("echo 'The deadline is Friday'"),
+	}))
+	rt, err := New(team.New(team.WithAgents(root)), WithSessionStore(store), WithModelStore(mockModelStoreWithLimit{limit: 100}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Start"))
+	require.NoError(t, store.AddSession(t.Context(), sess))
+	for range rt.RunStream(t.Context(), sess) {
+	}
+	sess.AddMessage(session.UserMessage("Again"))
+	for range rt.RunStream(t.Context(), sess) {
+	}
+
+	reloaded, err := store.GetSession(t.Context(), sess.ID)
+	require.NoError(t, err)
+	var summaries []string
+	for _, item := range reloaded.Messages {
+		if item.Summary != "" {
+			summaries = append(summaries, item.Summary)
+		}
+	}
+	assert.Equal(t, []string{"The summary\n\nThe deadline is Friday"}, summaries)
+}
@@ -16,12 +16,14 @@ import (
 
 	"github.com/docker/docker-agent/pkg/agent"
 	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/hooks"
 	"github.com/docker/docker-agent/pkg/model/provider"
 	"github.com/docker/docker-agent/pkg/model/provider/options"
 	"github.com/docker/docker-agent/pkg/modelerrors"
 	"github.com/docker/docker-agent/pkg/modelsdev"
 	"github.com/docker/docker-agent/pkg/session"
 	"github.com/docker/docker-agent/pkg/telemetry"
+	"github.com/docker/docker-agent/pkg/tokenizer"
 	"github.com/docker/docker-agent/pkg/tools"
 	"github.com/docker/docker-agent/pkg/tools/builtin"
 )
@@ -77,6 +79,8 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 		))
 		defer sessionSpan.End()
 
+		ctx = r.withCheckpointRecorder(ctx, sess)
+
 		// Swap in this stream's events channel for elicitation and save the
 		// previous one so it can be restored on teardown. This allows nested
 		// RunStream calls to temporarily own elicitation without losing the
@@ -96,7 +100,7 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 		r.InitializeRAG(ctx, events)
 
 		r.emitAgentWarnings(a, chanSend(events))
-		r.configureToolsetHandlers(a, events)
+		r.configureToolsetHandlers(sess, a, events)
 
 		agentTools, err := r.getTools(ctx, a, sessionSpan, events)
 		if err != nil {
@@ -138,7 +142,7 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 			}
 
 			r.emitAgentWarnings(a, chanSend(events))
-			r.configureToolsetHandlers(a, events)
+			r.configureToolsetHandlers(sess, a, events)
 
 			agentTools, err := r.getTools(ctx, a, sessionSpan, events)
 			if err != nil {
@@ -259,7 +263,15 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 				}
 			}
 
-			messages := sess.GetMessages(a)
+			// Fit the conversation to the context window before sending it,
+			// counting tokens with the tokenizer of the model's family.
+			tok := tokenizer.ForModel(modelID)
+			var messagesOpts []session.MessagesOpt
+			if budget := contextBudget(model, contextLimit, tok, agentTools); budget > 0 {
+				messagesOpts = append(messagesOpts, session.WithTokenBudget(tok, budget))
+			}
+
+			messages := sess.GetMessages(a, messagesOpts...)
 			slog.Debug("Retrieved messages for processing", "agent", a.Name(), "message_count", len(messages))
 
 			// Strip image content from messages if the model doesn't support image input.
@@ -269,6 +281,30 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 				messages = stripImageContent(messages)
 			}
 
+			// Keep the model up to date with the MCP resources attached to the
+			// conversation that changed since.
+			if resourcesContext := r.resources.updatedContext(sess.ID); resourcesContext != "" {
+				messages = withAdditionalContext(messages, redactText(a, "MCP resources", resourcesContext))
+			}
+
+			// Pre-model-call hooks can add context to this request or stop the agent.
+			if result := r.runLifecycleHooks(ctx, a, preModelCallEvent, events, func() *hooks.Input {
+				return &hooks.Input{
+					SessionID:    sess.ID,
+					Model:        modelID,
+					SessionUsage: sessionHookUsage(sess),
+				}
+			}); result != nil {
+				if !result.Allowed {
+					streamSpan.End()
+					stopByHook(sess, a, result.Message, events)
+					return
+				}
+				if result.AdditionalContext != "" {
+					messages = withAdditionalContext(messages, result.AdditionalContext)
+				}
+			}
+
 			// Try primary model with fallback chain if configured
 			res, usedModel, err := r.tryModelWithFallback(streamCtx, a, model, messages, agentTools, sess, m, events)
 			if err != nil {
@@ -338,6 +374,24 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 			usage.LastMessage = msgUsage
 			events <- NewTokenUsageEvent(sess.ID, a.Name(), usage)
 
+			// Post-model-call hooks can stop the agent before it runs the tools the model called.
+			usedModelID := modelID
+			if usedModel != nil {
+				usedModelID = usedModel.ID()
+			}
+			if result := r.runLifecycleHooks(ctx, a, postModelCallEvent, events, func() *hooks.Input {
+				return &hooks.Input{
+					SessionID:    sess.ID,
+					Model:        usedModelID,
+					Usage:        messageHookUsage(msgUsage),
+					SessionUsage: sessionHookUsage(sess),
+				}
+			}); result != nil && !result.Allowed {
+				r.skipToolCalls(ctx, sess, res.Calls, agentTools, events, a)
+				stopByHook(sess, a, result.Message, events)
+				return
+			}
+
 			// Record the message count before tool calls so we can
 			// measure how much content was added by tool results.
 			messageCountBeforeTools := len(sess.GetAllMessages())
@@ -352,7 +406,7 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 				break
 			}
 
-			r.compactIfNeeded(ctx, sess, a, m, contextLimit, messageCountBeforeTools, events)
+			r.compactIfNeeded(ctx, sess, a, m, tok, contextLimit, messageCountBeforeTools, events)
 		}
 	}()
 
@@ -403,14 +457,7 @@ func (r *LocalRuntime) recordAssistantMessage(
 		}
 	}
 
-	// Calculate per-message cost when pricing information is available.
-	var messageCost float64
-	if res.Usage != nil && m != nil && m.Cost != nil {
-		messageCost = (float64(res.Usage.InputTokens)*m.Cost.Input +
-			float64(res.Usage.OutputTokens)*m.Cost.Output +
-			float64(res.Usage.CachedInputTokens)*m.Cost.CacheRead +
-			float64(res.Usage.CacheWriteTokens)*m.Cost.CacheWrite) / 1e6
-	}
+	messageCost := usageCost(res.Usage, m)
 
 	messageModel := cmp.Or(res.ActualModel, modelID)
 
@@ -446,7 +493,19 @@ func (r *LocalRuntime) recordAssistantMessage(
 	return msgUsage
 }
 
-// compactIfNeeded estimates the token impact of tool results added since
+// usageCost calculates the cost of the token usage of a model call when
+// pricing information is available.
+func usageCost(usage *chat.Usage, m *modelsdev.Model) float64 {
+	if usage == nil || m == nil || m.Cost == nil {
+		return 0
+	}
+	return (float64(usage.InputTokens)*m.Cost.Input +
+		float64(usage.OutputTokens)*m.Cost.Output +
+		float64(usage.CachedInputTokens)*m.Cost.CacheRead +
+		float64(usage.CacheWriteTokens)*m.Cost.CacheWrite) / 1e6
+}
+
+// compactIfNeeded counts the tokens of the tool results added since
 // messageCountBefore and triggers proactive compaction when the estimated
 // total exceeds 90% of the context window. This prevents sending an
 // oversized request on the next iteration.
@@ -455,6 +514,7 @@ func (r *LocalRuntime) compactIfNeeded(
 	sess *session.Session,
 	a *agent.Agent,
 	m *modelsdev.Model,
+	tok tokenizer.Tokenizer,
 	contextLimit int64,
 	messageCountBefore int,
 	events chan Event,
@@ -466,7 +526,7 @@ func (r *LocalRuntime) compactIfNeeded(
 	newMessages := sess.GetAllMessages()[messageCountBefore:]
 	var addedTokens int64
 	for _, msg := range newMessages {
-		addedTokens += estimateMessageTokens(&msg.Message)
+		addedTokens += int64(tokenizer.CountMessage(tok, &msg.Message))
 	}
 
 	estimatedTotal := sess.InputTokens + sess.OutputTokens + addedTokens
@@ -485,6 +545,26 @@ func (r *LocalRuntime) compactIfNeeded(
 	r.Summarize(ctx, sess, "", events)
 }
 
+// contextBudget returns the number of tokens the messages of a request may
+// take: the context window minus the tool definitions and the tokens reserved
+// for the response. It returns 0 when the context window is unknown.
+func contextBudget(model provider.Provider, contextLimit int64, tok tokenizer.Tokenizer, agentTools []tools.Tool) int {
+	if contextLimit <= 0 {
+		return 0
+	}
+
+	cfg := model.BaseConfig()
+	reserve := cfg.ModelOptions.MaxTokens()
+	if cfg.ModelConfig.MaxTokens != nil {
+		reserve = *cfg.ModelConfig.MaxTokens
+	}
+	// Don't let an oversized max_tokens leave no room for the conversation.
+	reserve = min(reserve, contextLimit/2)
+
+	budget := contextLimit - reserve - int64(tokenizer.CountTools(tok, agentTools))
+	return int(max(budget, 0))
+}
+
 // getTools executes tool retrieval with automatic OAuth handling
 func (r *LocalRuntime) getTools(ctx context.Context, a *agent.Agent, sessionSpan trace.Span, events chan Event) ([]tools.Tool, error) {
 	shouldEmitMCPInit := len(a.ToolSets()) > 0
@@ -510,14 +590,35 @@ func (r *LocalRuntime) getTools(ctx context.Context, a *agent.Agent, sessionSpan
 	return agentTools, nil
 }
 
-// configureToolsetHandlers sets up elicitation and OAuth handlers for all toolsets of an agent.
-func (r *LocalRuntime) configureToolsetHandlers(a *agent.Agent, events chan Event) {
+// configureToolsetHandlers sets up elicitation, OAuth and sampling handlers for all toolsets of an agent.
+func (r *LocalRuntime) configureToolsetHandlers(sess *session.Session, a *agent.Agent, events chan Event) {
 	for _, toolset := range a.ToolSets() {
 		tools.ConfigureHandlers(toolset,
 			r.elicitationHandler,
 			func() { events <- Authorization(tools.ElicitationActionAccept, a.Name()) },
 			r.managedOAuth,
 		)
+		if s, ok := tools.As[tools.SamplingCapable](toolset); ok {
+			s.SetSamplingHandler(r.samplingHandler(sess, a))
+		}
+	}
+	r.configureRoots(sess, a)
+}
+
+// configureRoots tells the toolsets of the agent, e.g. MCP servers, about
+// the working directory and the allowed directories of the session. MCP
+// servers are notified when they change, e.g. when another session with a
+// different working directory is loaded.
+func (r *LocalRuntime) configureRoots(sess *session.Session, a *agent.Agent) {
+	dirs := sess.AllowedDirectories()
+	if wd := cmp.Or(sess.WorkingDir, r.workingDir); wd != "" && !slices.Contains(dirs, wd) {
+		dirs = append([]string{wd}, dirs...)
+	}
+
+	for _, toolset := range a.ToolSets() {
+		if rc, ok := tools.As[tools.RootsCapable](toolset); ok {
+			rc.SetRoots(dirs)
+		}
 	}
 }
 
@@ -0,0 +1,45 @@
+package runtime
+
+import (
+	"log/slog"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// redactToolResult replaces the secrets found in the output of a tool call
+// with a placeholder, before the result is shown, stored in the session and
+// sent to the model. The result is returned unchanged when the agent doesn't
+// redact secrets or when the output contains none.
+func redactToolResult(a *agent.Agent, toolCall tools.ToolCall, res *tools.ToolCallResult) *tools.ToolCallResult {
+	redactor := a.Redactor()
+	if redactor == nil {
+		return res
+	}
+
+	output, count := redactor.Redact(res.Output)
+	if count == 0 {
+		return res
+	}
+	slog.Debug("Redacted secrets from tool result", "tool", toolCall.Function.Name, "agent", a.Name(), "count", count)
+
+	redacted := *res
+	redacted.Output = output
+	return &redacted
+}
+
+// redactText replaces the secrets found in text that doesn't come from a tool
+// call, such as the content of MCP resources and sampling requests, before it
+// is stored in the session or sent to a model. what describes the text in logs.
+func redactText(a *agent.Agent, what, text string) string {
+	redactor := a.Redactor()
+	if redactor == nil {
+		return text
+	}
+
+	redacted, count := redactor.Redact(text)
+	if count > 0 {
+		slog.Debug("Redacted secrets", "from", what, "agent", a.Name(), "count", count)
+	}
+	return redacted
+}
@@ -459,6 +459,19 @@ func (r *RemoteRuntime) ExecuteMCPPrompt(context.Context, string, map[string]str
 	return "", errors.New("MCP prompts are not supported by remote runtimes")
 }
 
+// CurrentMCPResources is not supported on remote runtimes.
+func (r *RemoteRuntime) CurrentMCPResources(context.Context) []mcp.ResourceInfo {
+	return nil
+}
+
+// AttachMCPResource is not supported on remote runtimes.
+func (r *RemoteRuntime) AttachMCPResource(context.Context, *session.Session, string) (string, error) {
+	return "", errors.New("MCP resources are not supported by remote runtimes")
+}
+
+// DetachMCPResources does nothing on remote runtimes, which can't attach MCP resources.
+func (r *RemoteRuntime) DetachMCPResources(string) {}
+
 // TitleGenerator is not supported on remote runtimes (titles are generated server-side).
 func (r *RemoteRuntime) TitleGenerator() *sessiontitle.Generator {
 	return nil
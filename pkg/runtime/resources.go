@@ -0,0 +1,267 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+	mcptools "github.com/docker/docker-agent/pkg/tools/mcp"
+)
+
+// resourceRequestTimeout bounds the requests made to MCP servers outside of
+// a stream, to read updated resources and to unsubscribe from them.
+const resourceRequestTimeout = 30 * time.Second
+
+// attachedResource is an MCP resource attached to a message of a session.
+type attachedResource struct {
+	toolset *mcptools.Toolset
+	uri     string
+	// content is the latest content of the resource.
+	content string
+	// updated is true once the content changed since it was attached.
+	updated bool
+}
+
+// resourceTracker keeps the MCP resources attached to the messages of
+// sessions up to date, so that their latest content can be added to the
+// context of the following model calls.
+type resourceTracker struct {
+	mu sync.Mutex
+	// attached are the resources attached to each session, by session ID.
+	attached map[string][]*attachedResource
+	// watched are the toolsets whose resource updates we handle.
+	watched map[*mcptools.Toolset]bool
+}
+
+// CurrentMCPResources returns the resources of the MCP servers of the current
+// agent's toolsets.
+func (r *LocalRuntime) CurrentMCPResources(ctx context.Context) []mcptools.ResourceInfo {
+	currentAgent := r.CurrentAgent()
+	if currentAgent == nil {
+		return nil
+	}
+
+	var resources []mcptools.ResourceInfo
+	for _, toolset := range currentAgent.ToolSets() {
+		mcpToolset, ok := tools.As[*mcptools.Toolset](toolset)
+		if !ok {
+			continue
+		}
+
+		toolsetResources, err := mcpToolset.ListResources(ctx)
+		if err != nil {
+			slog.Warn("Failed to list MCP resources from toolset", "error", err)
+			continue
+		}
+		resources = append(resources, toolsetResources...)
+	}
+
+	slog.Debug("Discovered MCP resources", "agent", currentAgent.Name(), "resource_count", len(resources))
+	return resources
+}
+
+// AttachMCPResource reads an MCP resource of the session's agent to attach it
+// to a message. The runtime subscribes to the updates of the resource and adds
+// its latest content to the context of the session when it changes.
+func (r *LocalRuntime) AttachMCPResource(ctx context.Context, sess *session.Session, uri string) (string, error) {
+	a := r.resolveSessionAgent(sess)
+	if a == nil {
+		return "", errors.New("no current agent available")
+	}
+
+	for _, toolset := range a.ToolSets() {
+		mcpToolset, ok := tools.As[*mcptools.Toolset](toolset)
+		if !ok || !hasResource(ctx, mcpToolset, uri) {
+			continue
+		}
+
+		content, err := mcpToolset.ReadResource(ctx, uri)
+		if err != nil {
+			return "", err
+		}
+		content = redactText(a, "MCP resource", content)
+
+		r.resources.attach(sess.ID, mcpToolset, uri, content)
+		if err := mcpToolset.SubscribeResource(ctx, uri); err != nil {
+			slog.Warn("Failed to subscribe to MCP resource", "uri", uri, "error", err)
+		}
+
+		return content, nil
+	}
+
+	return "", fmt.Errorf("MCP resource '%s' not found in any active toolset", uri)
+}
+
+// DetachMCPResources forgets the MCP resources attached to the messages of a
+// session, e.g. when the session is left or deleted, and unsubscribes from the
+// updates of the ones no other session uses.
+func (r *LocalRuntime) DetachMCPResources(sessionID string) {
+	unused := r.resources.release(sessionID)
+	if len(unused) == 0 {
+		return
+	}
+
+	// Don't make the caller wait for the servers.
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), resourceRequestTimeout)
+		defer cancel()
+		for _, res := range unused {
+			if err := res.toolset.UnsubscribeResource(ctx, res.uri); err != nil {
+				slog.Warn("Failed to unsubscribe from MCP resource", "uri", res.uri, "error", err)
+			}
+		}
+	}()
+}
+
+// hasResource returns whether the MCP server of a toolset has a resource.
+func hasResource(ctx context.Context, toolset *mcptools.Toolset, uri string) bool {
+	resources, err := toolset.ListResources(ctx)
+	if err != nil {
+		slog.Warn("Failed to list MCP resources from toolset", "error", err)
+		return false
+	}
+	for _, resource := range resources {
+		if resource.URI == uri {
+			return true
+		}
+	}
+	return false
+}
+
+// attach records a resource attached to a session and starts handling the
+// resource updates of its toolset.
+func (t *resourceTracker) attach(sessionID string, toolset *mcptools.Toolset, uri, content string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.attached == nil {
+		t.attached = make(map[string][]*attachedResource)
+		t.watched = make(map[*mcptools.Toolset]bool)
+	}
+
+	for _, res := range t.attached[sessionID] {
+		if res.toolset == toolset && res.uri == uri {
+			res.content = content
+			res.updated = false
+			return
+		}
+	}
+	t.attached[sessionID] = append(t.attached[sessionID], &attachedResource{
+		toolset: toolset,
+		uri:     uri,
+		content: content,
+	})
+
+	if !t.watched[toolset] {
+		t.watched[toolset] = true
+		toolset.SetResourceUpdatedHandler(func(uri string) {
+			// Read the resource outside of the notification handler so that
+			// we don't block the connection with the server.
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), resourceRequestTimeout)
+				defer cancel()
+				t.refresh(ctx, toolset, uri)
+			}()
+		})
+	}
+}
+
+// refresh reads the latest content of a resource after the server notified
+// us that it changed.
+func (t *resourceTracker) refresh(ctx context.Context, toolset *mcptools.Toolset, uri string) {
+	content, err := toolset.ReadResource(ctx, uri)
+	if err != nil {
+		slog.Warn("Failed to read updated MCP resource", "uri", uri, "error", err)
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, resources := range t.attached {
+		for _, res := range resources {
+			if res.toolset == toolset && res.uri == uri && res.content != content {
+				slog.Debug("MCP resource attached to a session was updated", "uri", uri)
+				res.content = content
+				res.updated = true
+			}
+		}
+	}
+}
+
+// resourceRef identifies a resource of an MCP server.
+type resourceRef struct {
+	toolset *mcptools.Toolset
+	uri     string
+}
+
+// release forgets the resources attached to a session and returns the ones
+// that are no longer attached to any session.
+func (t *resourceTracker) release(sessionID string) []resourceRef {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	resources, ok := t.attached[sessionID]
+	if !ok {
+		return nil
+	}
+	delete(t.attached, sessionID)
+
+	var unused []resourceRef
+	for _, res := range resources {
+		if !t.isAttachedLocked(res.toolset, res.uri) {
+			unused = append(unused, resourceRef{toolset: res.toolset, uri: res.uri})
+		}
+	}
+	return unused
+}
+
+// sessions returns the IDs of the sessions with attached resources.
+func (t *resourceTracker) sessions() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ids := make([]string, 0, len(t.attached))
+	for id := range t.attached {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// isAttachedLocked reports whether a resource is attached to a session. The
+// caller must hold t.mu.
+func (t *resourceTracker) isAttachedLocked(toolset *mcptools.Toolset, uri string) bool {
+	for _, resources := range t.attached {
+		for _, res := range resources {
+			if res.toolset == toolset && res.uri == uri {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// updatedContext returns the latest content of the resources attached to a
+// session that changed since they were attached, or "" if none did.
+func (t *resourceTracker) updatedContext(sessionID string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var builder strings.Builder
+	for _, res := range t.attached[sessionID] {
+		if !res.updated {
+			continue
+		}
+		if builder.Len() == 0 {
+			builder.WriteString("The following resources attached to the conversation changed since they were attached. This is their latest content:")
+		}
+		fmt.Fprintf(&builder, "\n\n<attached_resource uri=%q>\n%s\n</attached_resource>", res.uri, res.content)
+	}
+	return builder.String()
+}
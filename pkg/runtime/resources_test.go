@@ -0,0 +1,51 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	mcptools "github.com/docker/docker-agent/pkg/tools/mcp"
+)
+
+func TestResourceTracker_UpdatedContext(t *testing.T) {
+	t.Parallel()
+
+	var tracker resourceTracker
+	assert.Empty(t, tracker.updatedContext("session-1"))
+
+	tracker.attached = map[string][]*attachedResource{
+		"session-1": {
+			{uri: "docs://readme", content: "# Readme"},
+			{uri: "docs://todo", content: "- [x] ship it", updated: true},
+		},
+	}
+
+	assert.Empty(t, tracker.updatedContext("session-2"))
+	assert.Equal(t, "The following resources attached to the conversation changed since they were attached. This is their latest content:\n\n"+
+		"<attached_resource uri=\"docs://todo\">\n- [x] ship it\n</attached_resource>", tracker.updatedContext("session-1"))
+}
+
+func TestResourceTracker_Release(t *testing.T) {
+	t.Parallel()
+
+	toolset := &mcptools.Toolset{}
+	var tracker resourceTracker
+	tracker.attached = map[string][]*attachedResource{
+		"session-1": {
+			{toolset: toolset, uri: "docs://readme"},
+			{toolset: toolset, uri: "docs://todo"},
+		},
+		"session-2": {
+			{toolset: toolset, uri: "docs://readme"},
+		},
+	}
+
+	// The readme is still attached to the second session.
+	assert.Equal(t, []resourceRef{{toolset: toolset, uri: "docs://todo"}}, tracker.release("session-1"))
+	assert.Equal(t, []string{"session-2"}, tracker.sessions())
+	assert.Nil(t, tracker.release("session-1"))
+
+	assert.Equal(t, []resourceRef{{toolset: toolset, uri: "docs://readme"}}, tracker.release("session-2"))
+	assert.Empty(t, tracker.sessions())
+}
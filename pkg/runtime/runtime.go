@@ -15,8 +15,11 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/audit"
+	"github.com/docker/docker-agent/pkg/checkpoint"
 	"github.com/docker/docker-agent/pkg/config/types"
 	"github.com/docker/docker-agent/pkg/hooks"
+	"github.com/docker/docker-agent/pkg/model/provider"
 	"github.com/docker/docker-agent/pkg/modelsdev"
 	"github.com/docker/docker-agent/pkg/rag"
 	ragtypes "github.com/docker/docker-agent/pkg/rag/types"
@@ -134,6 +137,17 @@ type Runtime interface {
 	// ExecuteMCPPrompt executes a named MCP prompt with the given arguments.
 	ExecuteMCPPrompt(ctx context.Context, promptName string, arguments map[string]string) (string, error)
 
+	// CurrentMCPResources returns the MCP resources available from the current agent's toolsets.
+	CurrentMCPResources(ctx context.Context) []mcptools.ResourceInfo
+
+	// AttachMCPResource reads an MCP resource to attach it to a message of the session.
+	// The latest content of the resource is added to the session's context when it changes.
+	AttachMCPResource(ctx context.Context, sess *session.Session, uri string) (string, error)
+
+	// DetachMCPResources forgets the MCP resources attached to the messages of
+	// a session, when the session is left or deleted.
+	DetachMCPResources(sessionID string)
+
 	// UpdateSessionTitle persists a new title for the current session.
 	UpdateSessionTitle(ctx context.Context, sess *session.Session, title string) error
 
@@ -190,11 +204,13 @@ type LocalRuntime struct {
 	managedOAuth                bool
 	startupInfoEmitted          bool                   // Track if startup info has been emitted to avoid unnecessary duplication
 	elicitationRequestCh        chan ElicitationResult // Channel for receiving elicitation responses
-	elicitationEventsChannel    chan Event             // Current events channel for sending elicitation requests
+	elicitationEventsChannel    chan Event             // Current events channel for sending the elicitation and sampling requests of MCP servers
 	elicitationEventsChannelMux sync.RWMutex           // Protects elicitationEventsChannel
 	ragInitialized              atomic.Bool
 	sessionCompactor            *sessionCompactor
 	sessionStore                session.Store
+	checkpoints                 *checkpoint.Store
+	auditLog                    *audit.Log
 	workingDir                  string   // Working directory for hooks execution
 	env                         []string // Environment variables for hooks execution
 	modelSwitcherCfg            *ModelSwitcherConfig
@@ -214,6 +230,9 @@ type LocalRuntime struct {
 	// onToolsChanged is called when an MCP toolset reports a tool list change.
 	onToolsChanged func(Event)
 
+	// resources tracks the MCP resources attached to sessions.
+	resources resourceTracker
+
 	bgAgents *agenttool.Handler
 }
 
@@ -650,7 +669,12 @@ func (r *LocalRuntime) getHooksExecutor(a *agent.Agent) *hooks.Executor {
 	if hooksCfg == nil || hooksCfg.IsEmpty() {
 		return nil
 	}
-	return hooks.NewExecutor(hooksCfg, r.workingDir, r.env)
+	return hooks.NewExecutor(hooksCfg, r.workingDir, r.env, hooks.WithModelResolver(func(ctx context.Context, modelRef string) (provider.Provider, error) {
+		if modelRef == "" {
+			return a.Model(), nil
+		}
+		return r.resolveModelRef(ctx, modelRef)
+	}))
 }
 
 // executeOnUserInputHooks executes on-user-input hooks for the current agent
@@ -751,6 +775,9 @@ func (r *LocalRuntime) SessionStore() session.Store {
 // Close releases resources held by the runtime, including the session store.
 func (r *LocalRuntime) Close() error {
 	r.bgAgents.StopAll()
+	for _, sessionID := range r.resources.sessions() {
+		r.DetachMCPResources(sessionID)
+	}
 	if r.sessionStore != nil {
 		return r.sessionStore.Close()
 	}
@@ -825,6 +852,14 @@ func (r *LocalRuntime) emitToolsChanged() {
 // When sess is non-nil and contains token data, a TokenUsageEvent is also emitted so that the
 // sidebar can display context usage percentage on session restore.
 func (r *LocalRuntime) EmitStartupInfo(ctx context.Context, sess *session.Session, events chan Event) {
+	// Advertise the session's directories to MCP servers before they start,
+	// and whenever another session is loaded.
+	if sess != nil {
+		if a := r.CurrentAgent(); a != nil {
+			r.configureRoots(sess, a)
+		}
+	}
+
 	// Prevent duplicate emissions
 	if r.startupInfoEmitted {
 		return
@@ -1008,7 +1043,41 @@ func (r *LocalRuntime) startSpan(ctx context.Context, name string, opts ...trace
 // for the summarization (e.g., "focus on code changes" or "include action items").
 func (r *LocalRuntime) Summarize(ctx context.Context, sess *session.Session, additionalPrompt string, events chan Event) {
 	a := r.resolveSessionAgent(sess)
-	r.sessionCompactor.Compact(ctx, sess, additionalPrompt, events, a.Name())
+
+	// Pre-compaction hooks can add instructions for the summary or skip the compaction.
+	if result := r.runLifecycleHooks(ctx, a, preCompactionEvent, events, func() *hooks.Input {
+		return &hooks.Input{
+			SessionID:    sess.ID,
+			SessionUsage: sessionHookUsage(sess),
+		}
+	}); result != nil {
+		if !result.Allowed {
+			slog.Debug("Pre-compaction hook skipped the compaction", "session_id", sess.ID, "message", result.Message)
+			events <- Warning(hookMessage("Compaction skipped by a hook", result.Message), a.Name())
+			return
+		}
+		if result.AdditionalContext != "" {
+			additionalPrompt = strings.TrimSpace(additionalPrompt + "\n\n" + result.AdditionalContext)
+		}
+	}
+
+	// Post-compaction hooks can add context to the summary, before it's
+	// added to the session.
+	postCompaction := func(summary string) string {
+		result := r.runLifecycleHooks(ctx, a, postCompactionEvent, events, func() *hooks.Input {
+			return &hooks.Input{
+				SessionID:    sess.ID,
+				Summary:      summary,
+				SessionUsage: sessionHookUsage(sess),
+			}
+		})
+		if result == nil {
+			return ""
+		}
+		return result.AdditionalContext
+	}
+
+	r.sessionCompactor.Compact(ctx, sess, additionalPrompt, postCompaction, events, a.Name())
 
 	// Emit a TokenUsageEvent so the sidebar immediately reflects the
 	// compaction: tokens drop to the summary size, context % drops, and
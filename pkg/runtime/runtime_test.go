@@ -25,6 +25,7 @@ import (
 	ragtypes "github.com/docker/docker-agent/pkg/rag/types"
 	"github.com/docker/docker-agent/pkg/session"
 	"github.com/docker/docker-agent/pkg/team"
+	"github.com/docker/docker-agent/pkg/tokenizer"
 	"github.com/docker/docker-agent/pkg/tools"
 )
 
@@ -905,6 +906,114 @@ func TestProcessToolCalls_UnknownTool_ReturnsErrorResponse(t *testing.T) {
 	assert.Contains(t, toolContent, "not available")
 }
 
+func TestProcessToolCalls_ReadOnlyToolsRunInParallel(t *testing.T) {
+	const n = 3
+
+	// Each handler waits until all of them have started, which only
+	// happens if they are executed concurrently.
+	var started sync.WaitGroup
+	started.Add(n)
+	var agentTools []tools.Tool
+	var calls []tools.ToolCall
+	for i := range n {
+		name := "read_" + string(rune('a'+i))
+		agentTools = append(agentTools, tools.Tool{
+			Name:        name,
+			Annotations: tools.ToolAnnotations{ReadOnlyHint: true},
+			Handler: func(ctx context.Context, _ tools.ToolCall) (*tools.ToolCallResult, error) {
+				started.Done()
+				done := make(chan struct{})
+				go func() { started.Wait(); close(done) }()
+				select {
+				case <-done:
+				case <-time.After(5 * time.Second):
+					return nil, errors.New("tool calls were not executed in parallel")
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+				// Finish in reverse order to check that results are still
+				// recorded in call order.
+				time.Sleep(time.Duration(n-i) * 10 * time.Millisecond)
+				return tools.ResultSuccess(name), nil
+			},
+		})
+		calls = append(calls, tools.ToolCall{
+			ID:       "call_" + name,
+			Type:     "function",
+			Function: tools.FunctionCall{Name: name, Arguments: "{}"},
+		})
+	}
+
+	root := agent.New("root", "You are a test agent", agent.WithModel(&mockProvider{}), agent.WithMaxParallelToolCalls(n))
+	rt, err := NewLocalRuntime(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Start"))
+
+	events := make(chan Event, 20)
+	rt.processToolCalls(t.Context(), sess, calls, agentTools, events)
+	close(events)
+
+	var responseIDs []string
+	for ev := range events {
+		if tr, ok := ev.(*ToolCallResponseEvent); ok {
+			require.False(t, tr.Result.IsError, tr.Response)
+			responseIDs = append(responseIDs, tr.ToolCall.ID)
+		}
+	}
+	assert.Equal(t, []string{"call_read_a", "call_read_b", "call_read_c"}, responseIDs)
+
+	var messageIDs []string
+	for _, it := range sess.Messages {
+		if it.IsMessage() && it.Message.Message.Role == chat.MessageRoleTool {
+			messageIDs = append(messageIDs, it.Message.Message.ToolCallID)
+		}
+	}
+	assert.Equal(t, responseIDs, messageIDs)
+}
+
+func TestProcessToolCalls_SequentialUnlessConfigured(t *testing.T) {
+	for _, limit := range []int{0, 1} {
+		var running, maxRunning int
+		var mu sync.Mutex
+		handler := func(context.Context, tools.ToolCall) (*tools.ToolCallResult, error) {
+			mu.Lock()
+			running++
+			maxRunning = max(maxRunning, running)
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return tools.ResultSuccess("ok"), nil
+		}
+
+		agentTools := []tools.Tool{{
+			Name:        "read",
+			Annotations: tools.ToolAnnotations{ReadOnlyHint: true},
+			Handler:     handler,
+		}}
+		calls := []tools.ToolCall{
+			{ID: "call_1", Type: "function", Function: tools.FunctionCall{Name: "read", Arguments: "{}"}},
+			{ID: "call_2", Type: "function", Function: tools.FunctionCall{Name: "read", Arguments: "{}"}},
+		}
+
+		root := agent.New("root", "You are a test agent", agent.WithModel(&mockProvider{}), agent.WithMaxParallelToolCalls(limit))
+		rt, err := NewLocalRuntime(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+		require.NoError(t, err)
+
+		sess := session.New(session.WithUserMessage("Start"))
+
+		events := make(chan Event, 20)
+		rt.processToolCalls(t.Context(), sess, calls, agentTools, events)
+		close(events)
+		for range events {
+		}
+
+		assert.Equal(t, 1, maxRunning, "max_parallel_tool_calls: %d", limit)
+	}
+}
+
 func TestEmitStartupInfo(t *testing.T) {
 	// Create a simple agent with mock provider
 	prov := &mockProvider{id: "test/startup-model", stream: &mockStream{}}
@@ -1497,6 +1606,54 @@ func TestToolRejectionWithoutReason(t *testing.T) {
 	require.NotContains(t, toolResponse.Response, "Reason:")
 }
 
+func TestToolConfirmationExplainsAskRule(t *testing.T) {
+	agentTools := []tools.Tool{{
+		Name:        "read_file",
+		Parameters:  map[string]any{},
+		Annotations: tools.ToolAnnotations{ReadOnlyHint: true},
+		Handler: func(_ context.Context, _ tools.ToolCall) (*tools.ToolCallResult, error) {
+			t.Fatal("tool should not be executed when rejected")
+			return nil, nil
+		},
+	}}
+
+	prov := &mockProvider{id: "test/mock-model", stream: &mockStream{}}
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(prov),
+		agent.WithToolSets(newStubToolSet(nil, agentTools, nil)),
+	)
+	tm := team.New(team.WithAgents(root), team.WithPermissions(permissions.NewChecker(&latest.PermissionsConfig{
+		Ask: []string{"read_file:path^=/etc"},
+	})))
+
+	rt, err := NewLocalRuntime(tm, WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Test"))
+	calls := []tools.ToolCall{{
+		ID:       "call_1",
+		Type:     "function",
+		Function: tools.FunctionCall{Name: "read_file", Arguments: `{"path":"/etc/hosts"}`},
+	}}
+
+	events := make(chan Event, 10)
+	go func() {
+		rt.processToolCalls(t.Context(), sess, calls, agentTools, events)
+		close(events)
+	}()
+
+	var confirmation *ToolCallConfirmationEvent
+	for ev := range events {
+		if e, ok := ev.(*ToolCallConfirmationEvent); ok {
+			confirmation = e
+			rt.resumeChan <- ResumeReject("")
+		}
+	}
+
+	require.NotNil(t, confirmation, "expected a confirmation request")
+	assert.Equal(t, `The ask rule "read_file:path^=/etc" of the permissions configuration requires confirmation.`, confirmation.Reason)
+}
+
 func TestTransferTaskRejectsNonSubAgent(t *testing.T) {
 	// root has librarian as sub-agent but NOT planner.
 	// planner exists in the team. transfer_task to planner should be rejected.
@@ -1823,89 +1980,28 @@ func TestStripImageContent(t *testing.T) {
 	}
 }
 
-func TestEstimateMessageTokens(t *testing.T) {
+// maxTokensProvider is a mock provider configured with max_tokens.
+type maxTokensProvider struct {
+	mockProvider
+	maxTokens int64
+}
+
+func (p *maxTokensProvider) BaseConfig() base.Config {
+	return base.Config{ModelConfig: latest.ModelConfig{MaxTokens: &p.maxTokens}}
+}
+
+func TestContextBudget(t *testing.T) {
 	t.Parallel()
 
-	tests := []struct {
-		name     string
-		msg      chat.Message
-		expected int64
-	}{
-		{
-			name:     "empty message returns overhead only",
-			msg:      chat.Message{},
-			expected: 5, // perMessageOverhead
-		},
-		{
-			name:     "text-only message",
-			msg:      chat.Message{Content: "Hello, world!"}, // 13 chars → 13/4 = 3 + 5 overhead = 8
-			expected: 8,
-		},
-		{
-			name: "multi-content text parts",
-			msg: chat.Message{
-				MultiContent: []chat.MessagePart{
-					{Type: chat.MessagePartTypeText, Text: "first part"},  // 10 chars
-					{Type: chat.MessagePartTypeText, Text: "second part"}, // 11 chars
-				},
-			},
-			// 21 total chars → 21/4 = 5 + 5 overhead = 10
-			expected: 10,
-		},
-		{
-			name: "message with tool calls",
-			msg: chat.Message{
-				ToolCalls: []tools.ToolCall{
-					{
-						Function: tools.FunctionCall{
-							Name:      "read_file",                // 9 chars
-							Arguments: `{"path":"/tmp/test.txt"}`, // 24 chars
-						},
-					},
-				},
-			},
-			// 33 chars → 33/4 = 8 + 5 overhead = 13
-			expected: 13,
-		},
-		{
-			name: "message with reasoning content",
-			msg: chat.Message{
-				Content:          "answer",                                         // 6 chars
-				ReasoningContent: "Let me think about this carefully step by step", // 47 chars
-			},
-			// 53 chars → 53/4 = 13 + 5 overhead = 18
-			expected: 18,
-		},
-		{
-			name: "combined content types",
-			msg: chat.Message{
-				Content:          "result",                                   // 6 chars
-				ReasoningContent: "thinking",                                 // 8 chars
-				MultiContent:     []chat.MessagePart{{Text: "extra detail"}}, // 12 chars
-				ToolCalls: []tools.ToolCall{
-					{Function: tools.FunctionCall{Name: "cmd", Arguments: `{"x":"y"}`}}, // 3 + 9 = 12 chars
-				},
-			},
-			// 38 chars → 38/4 = 9 + 5 overhead = 14
-			expected: 14,
-		},
-		{
-			name: "large tool result",
-			msg: chat.Message{
-				Content: string(make([]byte, 40000)), // 40000 null bytes
-			},
-			// 40000/4 = 10000 + 5 overhead = 10005
-			expected: 10005,
-		},
-	}
+	model := &maxTokensProvider{maxTokens: 8000}
+	agentTools := []tools.Tool{{Name: "read_file", Description: "Read a file"}}
+	toolTokens := tokenizer.CountTools(tokenizer.Heuristic, agentTools)
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-			got := estimateMessageTokens(&tt.msg)
-			assert.Equal(t, tt.expected, got, "estimateMessageTokens mismatch")
-		})
-	}
+	assert.Equal(t, 100000-8000-toolTokens, contextBudget(model, 100000, tokenizer.Heuristic, agentTools))
+	assert.Equal(t, 0, contextBudget(model, 0, tokenizer.Heuristic, agentTools))
+
+	// max_tokens never takes more than half of the context window.
+	assert.Equal(t, 5000, contextBudget(model, 10000, tokenizer.Heuristic, nil))
 }
 
 // TestResolveSessionAgent_PinnedAgent verifies that resolveSessionAgent returns
@@ -2014,3 +2110,25 @@ func TestProcessToolCalls_UsesPinnedAgent(t *testing.T) {
 		}
 	}
 }
+
+type rootsToolSet struct {
+	stubToolSet
+	roots []string
+}
+
+func (s *rootsToolSet) SetRoots(dirs []string) { s.roots = dirs }
+
+func TestConfigureRoots(t *testing.T) {
+	t.Parallel()
+
+	toolset := &rootsToolSet{}
+	root := agent.New("root", "test", agent.WithToolSets(toolset), agent.WithModel(&mockProvider{}))
+	rt, err := NewLocalRuntime(team.New(team.WithAgents(root)), WithWorkingDir("/runtime"), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	rt.configureRoots(session.New(), root)
+	assert.Equal(t, []string{"/runtime"}, toolset.roots)
+
+	rt.configureRoots(session.New(session.WithWorkingDir("/project")), root)
+	assert.Equal(t, []string{"/project"}, toolset.roots)
+}
@@ -0,0 +1,309 @@
+package runtime
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/model/provider"
+	"github.com/docker/docker-agent/pkg/model/provider/options"
+	"github.com/docker/docker-agent/pkg/permissions"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// samplingToolName is the name under which the sampling requests of MCP
+// servers are matched against permissions and shown for confirmation. The
+// name of the server's toolset is the "server" argument, so that e.g.
+// "sampling:server=github" allows the requests of a single server.
+const samplingToolName = "sampling"
+
+var samplingTool = tools.Tool{
+	Name:        samplingToolName,
+	Category:    "mcp",
+	Description: "Completion requested by an MCP server from the agent's model",
+	Annotations: tools.ToolAnnotations{Title: "MCP Sampling"},
+}
+
+// samplingArgs are the arguments of the tool call a sampling request is shown
+// and checked as.
+type samplingArgs struct {
+	Server       string            `json:"server"`
+	SystemPrompt string            `json:"system_prompt,omitempty"`
+	MaxTokens    int64             `json:"max_tokens,omitempty"`
+	Messages     []samplingMessage `json:"messages"`
+}
+
+type samplingMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// samplingHandler creates the handler that answers the sampling requests of
+// MCP servers with the current model of the agent. Each request must be
+// approved, by --yolo, a permission rule or the user, and its cost is added
+// to the session. Secrets are redacted from the request before it is shown
+// and sent to the model, and from the completion before it is returned.
+//
+// MCP servers send requests at any time, so events go to the stream running
+// when the request arrives rather than to the one that configured the
+// handler. Requests are refused when no stream is running.
+func (r *LocalRuntime) samplingHandler(sess *session.Session, a *agent.Agent) tools.SamplingHandler {
+	return func(ctx context.Context, server string, req *mcp.CreateMessageParams) (*mcp.CreateMessageResult, error) {
+		slog.Debug("Sampling request received from MCP server", "server", server, "agent", a.Name(), "session_id", sess.ID)
+
+		toolCall, err := samplingToolCall(server, req)
+		if err != nil {
+			return nil, err
+		}
+		toolCall.Function.Arguments = redactText(a, "sampling request", toolCall.Function.Arguments)
+		if err := r.approveSampling(ctx, sess, a, toolCall); err != nil {
+			return nil, err
+		}
+
+		if err := r.sendToCurrentStream(ToolCall(toolCall, samplingTool, a.Name())); err != nil {
+			return nil, err
+		}
+
+		result, err := r.sample(ctx, sess, a, req)
+		if err != nil {
+			_ = r.sendToCurrentStream(ToolCallResponse(toolCall, samplingTool, tools.ResultError(err.Error()), err.Error(), a.Name()))
+			return nil, err
+		}
+
+		text := result.Content.(*mcp.TextContent).Text
+		_ = r.sendToCurrentStream(ToolCallResponse(toolCall, samplingTool, tools.ResultSuccess(text), text, a.Name()))
+		return result, nil
+	}
+}
+
+// errNoStream is returned for the requests of MCP servers that arrive while no
+// stream is running to report them to.
+var errNoStream = errors.New("no events channel available for sampling")
+
+// sendToCurrentStream sends an event to the events channel of the running
+// stream. The read lock is held while sending so that the channel can't be
+// swapped or closed meanwhile.
+func (r *LocalRuntime) sendToCurrentStream(event Event) error {
+	r.elicitationEventsChannelMux.RLock()
+	defer r.elicitationEventsChannelMux.RUnlock()
+
+	if r.elicitationEventsChannel == nil {
+		return errNoStream
+	}
+	r.elicitationEventsChannel <- event
+	return nil
+}
+
+// samplingToolCall describes a sampling request as a tool call.
+func samplingToolCall(server string, req *mcp.CreateMessageParams) (tools.ToolCall, error) {
+	args := samplingArgs{
+		Server:       server,
+		SystemPrompt: req.SystemPrompt,
+		MaxTokens:    req.MaxTokens,
+	}
+	for _, msg := range req.Messages {
+		content := "[" + contentType(msg.Content) + "]"
+		if text, ok := msg.Content.(*mcp.TextContent); ok {
+			content = text.Text
+		}
+		args.Messages = append(args.Messages, samplingMessage{Role: string(msg.Role), Content: content})
+	}
+
+	arguments, err := json.Marshal(args)
+	if err != nil {
+		return tools.ToolCall{}, err
+	}
+
+	return tools.ToolCall{
+		ID:   "sampling_" + uuid.NewString(),
+		Type: "function",
+		Function: tools.FunctionCall{
+			Name:      samplingToolName,
+			Arguments: string(arguments),
+		},
+	}, nil
+}
+
+// approveSampling follows the approval flow of tool calls for a sampling
+// request and returns an error if it was not approved.
+func (r *LocalRuntime) approveSampling(ctx context.Context, sess *session.Session, a *agent.Agent, toolCall tools.ToolCall) error {
+	if sess.ToolsApproved {
+		return nil
+	}
+
+	reason := "MCP servers need approval to use the agent's model."
+	switch match := r.checkPermissions(sess, toolCall); match.decision {
+	case permissions.Deny:
+		slog.Debug("Sampling denied by permissions", "source", match.source, "session_id", sess.ID)
+		return fmt.Errorf("sampling is denied by %s", match.source)
+	case permissions.Allow:
+		slog.Debug("Sampling auto-approved by permissions", "source", match.source, "session_id", sess.ID)
+		return nil
+	case permissions.ForceAsk:
+		reason = fmt.Sprintf("The ask rule %q of the %s requires confirmation.", match.pattern, match.source)
+	}
+
+	slog.Debug("Sampling not approved, waiting for resume", "session_id", sess.ID)
+	if err := r.sendToCurrentStream(ToolCallConfirmation(toolCall, samplingTool, reason, a.Name())); err != nil {
+		return err
+	}
+
+	r.executeOnUserInputHooks(ctx, sess.ID, "sampling confirmation")
+
+	select {
+	case req := <-r.resumeChan:
+		switch req.Type {
+		case ResumeTypeApprove:
+			return nil
+		case ResumeTypeApproveSession:
+			sess.ToolsApproved = true
+			return nil
+		case ResumeTypeApproveTool:
+			allowForSession(sess, samplingToolName)
+			return nil
+		default:
+			message := "the user rejected the sampling request"
+			if reason := strings.TrimSpace(req.Reason); reason != "" {
+				message += ": " + reason
+			}
+			return errors.New(message)
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// sample asks the current model of the agent for the completion of a
+// sampling request and adds its cost to the session.
+func (r *LocalRuntime) sample(ctx context.Context, sess *session.Session, a *agent.Agent, req *mcp.CreateMessageParams) (*mcp.CreateMessageResult, error) {
+	messages, err := samplingChatMessages(req)
+	if err != nil {
+		return nil, err
+	}
+	for i := range messages {
+		messages[i].Content = redactText(a, "sampling request", messages[i].Content)
+	}
+
+	opts := []options.Opt{options.WithStructuredOutput(nil), options.WithThinking(false)}
+	if req.MaxTokens > 0 {
+		opts = append(opts, options.WithMaxTokens(req.MaxTokens))
+	}
+	model := provider.CloneWithOptions(ctx, a.Model(), opts...)
+
+	stream, err := model.CreateChatCompletionStream(ctx, messages, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating completion: %w", err)
+	}
+	defer stream.Close()
+
+	var content strings.Builder
+	var usage *chat.Usage
+	var finishReason chat.FinishReason
+	actualModel := model.ID()
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("receiving completion: %w", err)
+		}
+		if resp.Usage != nil {
+			usage = resp.Usage
+		}
+		if resp.Model != "" {
+			actualModel = resp.Model
+		}
+		for _, choice := range resp.Choices {
+			content.WriteString(choice.Delta.Content)
+			if choice.FinishReason != "" {
+				finishReason = choice.FinishReason
+			}
+		}
+	}
+
+	m, err := r.modelsStore.GetModel(ctx, model.ID())
+	if err != nil {
+		slog.Debug("Failed to get model definition", "error", err)
+	}
+	if cost := usageCost(usage, m); cost > 0 {
+		sess.AddCost(cost)
+		var contextLimit int64
+		if m != nil {
+			contextLimit = int64(m.Limit.Context)
+		}
+		_ = r.sendToCurrentStream(NewTokenUsageEvent(sess.ID, a.Name(), SessionUsage(sess, contextLimit)))
+	}
+
+	stopReason := "endTurn"
+	if finishReason == chat.FinishReasonLength {
+		stopReason = "maxTokens"
+	}
+
+	return &mcp.CreateMessageResult{
+		Content:    &mcp.TextContent{Text: redactText(a, "sampling completion", content.String())},
+		Model:      actualModel,
+		Role:       "assistant",
+		StopReason: stopReason,
+	}, nil
+}
+
+// samplingChatMessages converts the messages of a sampling request to chat
+// messages. Only text and image content is supported.
+func samplingChatMessages(req *mcp.CreateMessageParams) ([]chat.Message, error) {
+	var messages []chat.Message
+	if req.SystemPrompt != "" {
+		messages = append(messages, chat.Message{Role: chat.MessageRoleSystem, Content: req.SystemPrompt})
+	}
+
+	for _, msg := range req.Messages {
+		role := chat.MessageRoleUser
+		if msg.Role == "assistant" {
+			role = chat.MessageRoleAssistant
+		}
+
+		switch content := msg.Content.(type) {
+		case *mcp.TextContent:
+			messages = append(messages, chat.Message{Role: role, Content: content.Text})
+		case *mcp.ImageContent:
+			messages = append(messages, chat.Message{
+				Role: role,
+				MultiContent: []chat.MessagePart{{
+					Type: chat.MessagePartTypeImageURL,
+					ImageURL: &chat.MessageImageURL{
+						URL: "data:" + content.MIMEType + ";base64," + base64.StdEncoding.EncodeToString(content.Data),
+					},
+				}},
+			})
+		default:
+			return nil, fmt.Errorf("unsupported %s content in sampling request", contentType(msg.Content))
+		}
+	}
+
+	return messages, nil
+}
+
+// contentType returns the type of MCP content, as named by the protocol.
+func contentType(content mcp.Content) string {
+	switch content.(type) {
+	case *mcp.TextContent:
+		return "text"
+	case *mcp.ImageContent:
+		return "image"
+	case *mcp.AudioContent:
+		return "audio"
+	default:
+		return fmt.Sprintf("%T", content)
+	}
+}
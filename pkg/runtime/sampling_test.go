@@ -0,0 +1,201 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/modelsdev"
+	"github.com/docker/docker-agent/pkg/permissions"
+	"github.com/docker/docker-agent/pkg/session"
+	"github.com/docker/docker-agent/pkg/team"
+)
+
+type mockModelStoreWithCost struct {
+	ModelStore
+}
+
+func (m mockModelStoreWithCost) GetModel(_ context.Context, _ string) (*modelsdev.Model, error) {
+	return &modelsdev.Model{Cost: &modelsdev.Cost{Input: 1, Output: 2}}, nil
+}
+
+func newSamplingRuntime(t *testing.T, stream *mockStream, perms *latest.PermissionsConfig) (*LocalRuntime, *agent.Agent) {
+	t.Helper()
+
+	prov := &mockProvider{id: "test/mock-model", stream: stream}
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov))
+	teamOpts := []team.Opt{team.WithAgents(root)}
+	if perms != nil {
+		teamOpts = append(teamOpts, team.WithPermissions(permissions.NewChecker(perms)))
+	}
+
+	rt, err := NewLocalRuntime(team.New(teamOpts...), WithSessionCompaction(false), WithModelStore(mockModelStoreWithCost{}))
+	require.NoError(t, err)
+	return rt, root
+}
+
+func samplingRequest(text string) *mcp.CreateMessageParams {
+	return &mcp.CreateMessageParams{
+		SystemPrompt: "You summarize.",
+		MaxTokens:    100,
+		Messages: []*mcp.SamplingMessage{
+			{Role: "user", Content: &mcp.TextContent{Text: text}},
+		},
+	}
+}
+
+func TestSamplingHandler_AllowedByPermission(t *testing.T) {
+	t.Parallel()
+
+	stream := newStreamBuilder().AddContent("A short ").AddContent("summary").AddStopWithUsage(1000, 500).Build()
+	rt, root := newSamplingRuntime(t, stream, &latest.PermissionsConfig{Allow: []string{"sampling:server=github"}})
+
+	sess := session.New(session.WithUserMessage("Test"))
+	events := make(chan Event, 10)
+	rt.swapElicitationEventsChannel(events)
+
+	result, err := rt.samplingHandler(sess, root)(t.Context(), "github", samplingRequest("Summarize this"))
+	require.NoError(t, err)
+	close(events)
+
+	assert.Equal(t, &mcp.TextContent{Text: "A short summary"}, result.Content)
+	assert.Equal(t, "test/mock-model", result.Model)
+	assert.Equal(t, mcp.Role("assistant"), result.Role)
+	assert.Equal(t, "endTurn", result.StopReason)
+
+	// 1000 input tokens at $1/M and 500 output tokens at $2/M
+	assert.InDelta(t, 0.002, sess.TotalCost(), 1e-9)
+
+	var received []Event
+	for ev := range events {
+		received = append(received, ev)
+	}
+	require.Len(t, received, 3)
+	assert.IsType(t, &ToolCallEvent{}, received[0])
+	assert.IsType(t, &TokenUsageEvent{}, received[1])
+	require.IsType(t, &ToolCallResponseEvent{}, received[2])
+	assert.Equal(t, "A short summary", received[2].(*ToolCallResponseEvent).Response)
+}
+
+func TestSamplingHandler_DeniedByPermission(t *testing.T) {
+	t.Parallel()
+
+	rt, root := newSamplingRuntime(t, &mockStream{}, &latest.PermissionsConfig{Deny: []string{"sampling"}})
+
+	sess := session.New(session.WithUserMessage("Test"))
+	events := make(chan Event, 10)
+	rt.swapElicitationEventsChannel(events)
+
+	_, err := rt.samplingHandler(sess, root)(t.Context(), "github", samplingRequest("Summarize this"))
+	require.ErrorContains(t, err, "sampling is denied by permissions configuration")
+	assert.Empty(t, events)
+}
+
+func TestSamplingHandler_UserConfirmation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		resume      ResumeRequest
+		wantErr     string
+		wantAllowed []string
+	}{
+		{
+			name:   "approve",
+			resume: ResumeApprove(),
+		},
+		{
+			name:        "always allow",
+			resume:      ResumeApproveTool(""),
+			wantAllowed: []string{"sampling"},
+		},
+		{
+			name:    "reject",
+			resume:  ResumeReject("not now"),
+			wantErr: "the user rejected the sampling request: not now",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stream := newStreamBuilder().AddContent("ok").AddStopWithUsage(1, 1).Build()
+			rt, root := newSamplingRuntime(t, stream, nil)
+
+			sess := session.New(session.WithUserMessage("Test"))
+			events := make(chan Event, 10)
+			rt.swapElicitationEventsChannel(events)
+
+			go func() {
+				for ev := range events {
+					if confirmation, ok := ev.(*ToolCallConfirmationEvent); ok {
+						var args samplingArgs
+						assert.NoError(t, json.Unmarshal([]byte(confirmation.ToolCall.Function.Arguments), &args))
+						assert.Equal(t, "github", args.Server)
+						assert.Equal(t, []samplingMessage{{Role: "user", Content: "Summarize this"}}, args.Messages)
+						rt.resumeChan <- tt.resume
+					}
+				}
+			}()
+
+			_, err := rt.samplingHandler(sess, root)(t.Context(), "github", samplingRequest("Summarize this"))
+			close(events)
+
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantAllowed != nil {
+				assert.Equal(t, tt.wantAllowed, sess.Permissions.Allow)
+			}
+		})
+	}
+}
+
+func TestSamplingHandler_NoStream(t *testing.T) {
+	t.Parallel()
+
+	rt, root := newSamplingRuntime(t, &mockStream{}, &latest.PermissionsConfig{Allow: []string{"sampling"}})
+
+	sess := session.New(session.WithUserMessage("Test"))
+	_, err := rt.samplingHandler(sess, root)(t.Context(), "github", samplingRequest("Summarize this"))
+	require.ErrorIs(t, err, errNoStream)
+}
+
+func TestSamplingChatMessages(t *testing.T) {
+	t.Parallel()
+
+	messages, err := samplingChatMessages(&mcp.CreateMessageParams{
+		SystemPrompt: "Be brief.",
+		Messages: []*mcp.SamplingMessage{
+			{Role: "user", Content: &mcp.TextContent{Text: "What is this?"}},
+			{Role: "user", Content: &mcp.ImageContent{Data: []byte("png"), MIMEType: "image/png"}},
+			{Role: "assistant", Content: &mcp.TextContent{Text: "A picture."}},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []chat.Message{
+		{Role: chat.MessageRoleSystem, Content: "Be brief."},
+		{Role: chat.MessageRoleUser, Content: "What is this?"},
+		{Role: chat.MessageRoleUser, MultiContent: []chat.MessagePart{{
+			Type:     chat.MessagePartTypeImageURL,
+			ImageURL: &chat.MessageImageURL{URL: "data:image/png;base64,cG5n"},
+		}}},
+		{Role: chat.MessageRoleAssistant, Content: "A picture."},
+	}, messages)
+
+	_, err = samplingChatMessages(&mcp.CreateMessageParams{
+		Messages: []*mcp.SamplingMessage{{Role: "user", Content: &mcp.AudioContent{Data: []byte("wav"), MIMEType: "audio/wav"}}},
+	})
+	require.EqualError(t, err, "unsupported audio content in sampling request")
+}
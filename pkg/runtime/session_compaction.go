@@ -32,7 +32,10 @@ func newSessionCompactor(model provider.Provider, sessionStore session.Store) *s
 	}
 }
 
-func (c *sessionCompactor) Compact(ctx context.Context, sess *session.Session, additionalPrompt string, events chan Event, agentName string) {
+// Compact summarizes the session. extendSummary, when not nil, is called with
+// the generated summary before it's added to the session, and returns
+// context to append to it.
+func (c *sessionCompactor) Compact(ctx context.Context, sess *session.Session, additionalPrompt string, extendSummary func(summary string) string, events chan Event, agentName string) {
 	slog.Debug("Generating summary for session", "session_id", sess.ID)
 
 	events <- SessionCompaction(sess.ID, "started", agentName)
@@ -93,6 +96,12 @@ func (c *sessionCompactor) Compact(ctx context.Context, sess *session.Session, a
 		return
 	}
 
+	if extendSummary != nil {
+		if extra := extendSummary(summary); extra != "" {
+			summary += "\n\n" + extra
+		}
+	}
+
 	compactionCost := summarySession.TotalCost()
 
 	// Store the compaction cost on the summary item so that TotalCost()
@@ -232,45 +232,3 @@ func stripImageContent(messages []chat.Message) []chat.Message {
 	}
 	return result
 }
-
-// charsPerToken is the average number of characters per token used for
-// estimation. A value of 4 is a widely-used heuristic for English text;
-// it slightly overestimates token counts for code/JSON (which is ~3.5),
-// making compaction trigger earlier — the safe direction.
-const charsPerToken = 4
-
-// estimateMessageTokens returns a rough token-count estimate for a single
-// chat message based on its text length. This is intentionally conservative
-// (overestimates) so that proactive compaction fires before we hit the limit.
-// The estimate includes the message content, multi-content text parts, and
-// a small overhead per message for role/metadata tokens.
-func estimateMessageTokens(msg *chat.Message) int64 {
-	var chars int
-
-	// Primary text content.
-	chars += len(msg.Content)
-
-	// Multi-content parts (e.g., tool results with image descriptions).
-	for _, part := range msg.MultiContent {
-		chars += len(part.Text)
-	}
-
-	// Reasoning / thinking content.
-	chars += len(msg.ReasoningContent)
-
-	// Tool call arguments (they count toward input tokens on the next turn).
-	for _, tc := range msg.ToolCalls {
-		chars += len(tc.Function.Arguments)
-		chars += len(tc.Function.Name)
-	}
-
-	// Per-message overhead: role, ToolCallID, delimiters, etc.
-	// Models typically use 3-7 tokens for message framing.
-	const perMessageOverhead = 5
-
-	if chars == 0 {
-		return perMessageOverhead
-	}
-
-	return int64(chars/charsPerToken) + perMessageOverhead
-}
@@ -1,6 +1,7 @@
 package runtime
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
@@ -8,6 +9,7 @@ import (
 	"log/slog"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
@@ -15,6 +17,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/audit"
 	"github.com/docker/docker-agent/pkg/chat"
 	"github.com/docker/docker-agent/pkg/config/latest"
 	"github.com/docker/docker-agent/pkg/hooks"
@@ -24,7 +27,13 @@ import (
 	"github.com/docker/docker-agent/pkg/tools"
 )
 
-// processToolCalls handles the execution of tool calls for an agent
+// processToolCalls handles the execution of tool calls for an agent.
+//
+// When the agent sets max_parallel_tool_calls above 1 and has no pre-tool-use
+// hooks, which may ask for confirmation, consecutive calls to read-only tools
+// that don't require user confirmation are executed concurrently, up to that
+// limit. Everything else runs sequentially. Results are always recorded in
+// the order the model emitted the calls.
 func (r *LocalRuntime) processToolCalls(ctx context.Context, sess *session.Session, calls []tools.ToolCall, agentTools []tools.Tool, events chan Event) {
 	a := r.resolveSessionAgent(sess)
 	slog.Debug("Processing tool calls", "agent", a.Name(), "call_count", len(calls))
@@ -35,50 +44,176 @@ func (r *LocalRuntime) processToolCalls(ctx context.Context, sess *session.Sessi
 		agentToolMap[t.Name] = t
 	}
 
-	for _, toolCall := range calls {
-		callCtx, callSpan := r.startSpan(ctx, "runtime.tool.call", trace.WithAttributes(
-			attribute.String("tool.name", toolCall.Function.Name),
-			attribute.String("tool.type", string(toolCall.Type)),
-			attribute.String("agent", a.Name()),
-			attribute.String("session.id", sess.ID),
-			attribute.String("tool.call_id", toolCall.ID),
-		))
-
-		slog.Debug("Processing tool call", "agent", a.Name(), "tool", toolCall.Function.Name, "session_id", sess.ID)
-
-		// Resolve the tool: it must be in the agent's tool set to be callable.
-		// After a handoff the model may hallucinate tools it saw in the
-		// conversation history from a previous agent; rejecting unknown
-		// tools with an error response lets it self-correct.
-		tool, available := agentToolMap[toolCall.Function.Name]
-		if !available {
-			slog.Warn("Tool call for unavailable tool", "agent", a.Name(), "tool", toolCall.Function.Name, "session_id", sess.ID)
-			errTool := tools.Tool{Name: toolCall.Function.Name}
-			r.addToolErrorResponse(ctx, sess, toolCall, errTool, events, a, fmt.Sprintf("Tool '%s' is not available. You can only use the tools provided to you.", toolCall.Function.Name))
-			callSpan.SetStatus(codes.Error, "tool not available")
-			callSpan.End()
-			continue
-		}
+	limit := a.MaxParallelToolCalls()
+	if hooksExec := r.getHooksExecutor(a); hooksExec != nil && hooksExec.HasPreToolUseHooks() {
+		limit = 1
+	}
 
-		// Pick the handler: runtime-managed tools (transfer_task, handoff)
-		// have dedicated handlers; everything else goes through the toolset.
-		var runTool func()
-		if handler, exists := r.toolMap[toolCall.Function.Name]; exists {
-			runTool = func() { r.runAgentTool(callCtx, handler, sess, toolCall, tool, events, a) }
-		} else {
-			runTool = func() { r.runTool(callCtx, tool, toolCall, events, sess, a) }
+	for i := 0; i < len(calls); {
+		if limit > 1 {
+			var approvals []toolApproval
+			for _, toolCall := range calls[i:] {
+				approval, ok := r.parallelApproval(sess, toolCall, agentToolMap)
+				if !ok {
+					break
+				}
+				approvals = append(approvals, approval)
+			}
+			if n := len(approvals); n > 1 {
+				r.processParallelToolCalls(ctx, sess, calls[i:i+n], approvals, agentToolMap, events, a, limit)
+				i += n
+				continue
+			}
 		}
 
-		// Execute tool with approval check
-		canceled := r.executeWithApproval(callCtx, sess, toolCall, tool, events, a, runTool)
-		if canceled {
-			callSpan.SetStatus(codes.Ok, "tool call canceled by user")
-			callSpan.End()
+		if canceled := r.processToolCall(ctx, sess, calls[i], agentToolMap, events, a); canceled {
 			return
 		}
+		i++
+	}
+}
+
+// processToolCall resolves, approves and executes a single tool call.
+// Returns true if the operation was canceled and processing should stop.
+func (r *LocalRuntime) processToolCall(ctx context.Context, sess *session.Session, toolCall tools.ToolCall, agentToolMap map[string]tools.Tool, events chan Event, a *agent.Agent) (canceled bool) {
+	callCtx, callSpan := r.startToolCallSpan(ctx, sess, toolCall, a)
+	defer callSpan.End()
+
+	slog.Debug("Processing tool call", "agent", a.Name(), "tool", toolCall.Function.Name, "session_id", sess.ID)
+
+	// Resolve the tool: it must be in the agent's tool set to be callable.
+	// After a handoff the model may hallucinate tools it saw in the
+	// conversation history from a previous agent; rejecting unknown
+	// tools with an error response lets it self-correct.
+	tool, available := agentToolMap[toolCall.Function.Name]
+	if !available {
+		slog.Warn("Tool call for unavailable tool", "agent", a.Name(), "tool", toolCall.Function.Name, "session_id", sess.ID)
+		errTool := tools.Tool{Name: toolCall.Function.Name}
+		r.addToolErrorResponse(ctx, sess, toolCall, errTool, events, a, fmt.Sprintf("Tool '%s' is not available. You can only use the tools provided to you.", toolCall.Function.Name))
+		callSpan.SetStatus(codes.Error, "tool not available")
+		return false
+	}
+
+	// Pick the handler: runtime-managed tools (transfer_task, handoff)
+	// have dedicated handlers; everything else goes through the toolset.
+	var runTool func(ctx context.Context)
+	if handler, exists := r.toolMap[toolCall.Function.Name]; exists {
+		runTool = func(ctx context.Context) { r.runAgentTool(ctx, handler, sess, toolCall, tool, events, a) }
+	} else {
+		runTool = func(ctx context.Context) { r.runTool(ctx, tool, toolCall, events, sess, a) }
+	}
+
+	// Execute tool with approval check
+	if r.executeWithApproval(callCtx, sess, toolCall, tool, events, a, runTool) {
+		callSpan.SetStatus(codes.Ok, "tool call canceled by user")
+		return true
+	}
+
+	callSpan.SetStatus(codes.Ok, "tool call processed")
+	return false
+}
 
-		callSpan.SetStatus(codes.Ok, "tool call processed")
-		callSpan.End()
+// startToolCallSpan starts the span that covers a whole tool call, from
+// approval to the recorded response.
+func (r *LocalRuntime) startToolCallSpan(ctx context.Context, sess *session.Session, toolCall tools.ToolCall, a *agent.Agent) (context.Context, trace.Span) {
+	return r.startSpan(ctx, "runtime.tool.call", trace.WithAttributes(
+		attribute.String("tool.name", toolCall.Function.Name),
+		attribute.String("tool.type", string(toolCall.Type)),
+		attribute.String("agent", a.Name()),
+		attribute.String("session.id", sess.ID),
+		attribute.String("tool.call_id", toolCall.ID),
+	))
+}
+
+// parallelApproval reports whether a tool call can be executed concurrently
+// with its neighbours: the tool must be a read-only toolset tool whose
+// approval resolves without asking the user. It also returns who or what
+// approves the call.
+func (r *LocalRuntime) parallelApproval(sess *session.Session, toolCall tools.ToolCall, agentToolMap map[string]tools.Tool) (toolApproval, bool) {
+	tool, available := agentToolMap[toolCall.Function.Name]
+	if !available || !tool.Annotations.ReadOnlyHint {
+		return toolApproval{}, false
+	}
+	if _, runtimeManaged := r.toolMap[toolCall.Function.Name]; runtimeManaged {
+		return toolApproval{}, false
+	}
+	if sess.ToolsApproved {
+		return toolApproval{by: audit.SourceYolo}, true
+	}
+	switch match := r.checkPermissions(sess, toolCall); match.decision {
+	case permissions.Allow:
+		return toolApproval{by: match.auditSource, pattern: match.pattern}, true
+	case permissions.Ask:
+		return toolApproval{by: audit.SourceReadOnlyHint}, true
+	default:
+		return toolApproval{}, false
+	}
+}
+
+// parallelToolCall tracks the state of one tool call in a parallel batch.
+type parallelToolCall struct {
+	toolCall tools.ToolCall
+	tool     tools.Tool
+	ctx      context.Context
+	callSpan trace.Span
+	span     trace.Span
+	approval toolApproval
+	res      *tools.ToolCallResult
+	err      error
+	duration time.Duration
+}
+
+// processParallelToolCalls executes a batch of read-only tool calls
+// concurrently. Events, post-tool hooks and session updates are all handled
+// on the calling goroutine, in call order, so that the session history and
+// the events stream stay deterministic; only the tool handlers themselves
+// run concurrently.
+func (r *LocalRuntime) processParallelToolCalls(ctx context.Context, sess *session.Session, calls []tools.ToolCall, approvals []toolApproval, agentToolMap map[string]tools.Tool, events chan Event, a *agent.Agent, limit int) {
+	slog.Debug("Processing tool calls in parallel", "agent", a.Name(), "call_count", len(calls), "limit", limit, "session_id", sess.ID)
+
+	hooksExec := r.getHooksExecutor(a)
+
+	batch := make([]*parallelToolCall, len(calls))
+	for i, toolCall := range calls {
+		callCtx, callSpan := r.startToolCallSpan(ctx, sess, toolCall, a)
+		pc := &parallelToolCall{
+			toolCall: toolCall,
+			tool:     agentToolMap[toolCall.Function.Name],
+			callSpan: callSpan,
+			approval: approvals[i],
+		}
+		batch[i] = pc
+
+		pc.ctx, pc.span = r.startToolHandlerSpan(callCtx, "runtime.tool.handler", sess, pc.toolCall, a)
+		events <- ToolCall(pc.toolCall, pc.tool, a.Name())
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, limit)
+	for _, pc := range batch {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			start := time.Now()
+			pc.res, pc.err = pc.tool.Handler(withToolProgress(pc.ctx, pc.toolCall, events, a), pc.toolCall)
+			pc.duration = time.Since(start)
+		})
+	}
+	wg.Wait()
+
+	for _, pc := range batch {
+		telemetry.RecordToolCall(pc.ctx, pc.toolCall.Function.Name, sess.ID, a.Name(), pc.duration, pc.err)
+		r.auditToolResult(pc.ctx, sess, a, pc.toolCall, pc.approval, pc.res, pc.err, pc.duration)
+		r.addToolResult(pc.ctx, pc.span, pc.toolCall, pc.tool, pc.res, pc.err, events, sess, a)
+		pc.span.End()
+
+		if hooksExec != nil && hooksExec.HasPostToolUseHooks() {
+			r.executePostToolHook(pc.ctx, hooksExec, sess, pc.toolCall, events, a)
+		}
+
+		pc.callSpan.SetStatus(codes.Ok, "tool call processed")
+		pc.callSpan.End()
 	}
 }
 
@@ -99,17 +234,59 @@ func (r *LocalRuntime) executeWithApproval(
 	tool tools.Tool,
 	events chan Event,
 	a *agent.Agent,
-	runTool func(),
+	runTool func(ctx context.Context),
 ) (canceled bool) {
 	toolName := toolCall.Function.Name
 
 	// --yolo flag takes absolute precedence: auto-approve everything.
 	if sess.ToolsApproved {
 		slog.Debug("Tool auto-approved by --yolo flag", "tool", toolName, "session_id", sess.ID)
-		runTool()
+		runTool(withToolApproval(ctx, toolApproval{by: audit.SourceYolo}))
+		return false
+	}
+
+	switch match := r.checkPermissions(sess, toolCall); match.decision {
+	case permissions.Deny:
+		slog.Debug("Tool denied by permissions", "tool", toolName, "source", match.source, "session_id", sess.ID)
+		message := fmt.Sprintf("Tool '%s' is denied by %s.", toolName, match.source)
+		r.auditToolDenied(sess, a, toolCall, audit.DecisionDeny, match.auditSource, match.pattern, message)
+		r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, message)
+		return false
+	case permissions.Allow:
+		slog.Debug("Tool auto-approved by permissions", "tool", toolName, "source", match.source, "session_id", sess.ID)
+		runTool(withToolApproval(ctx, toolApproval{by: match.auditSource, pattern: match.pattern}))
+		return false
+	case permissions.ForceAsk:
+		slog.Debug("Tool requires confirmation (ask pattern)", "tool", toolName, "source", match.source, "session_id", sess.ID)
+		reason := fmt.Sprintf("The ask rule %q of the %s requires confirmation.", match.pattern, match.source)
+		return r.askUserForConfirmation(ctx, sess, toolCall, tool, events, a, reason, runTool)
+	}
+
+	// No permission rule matched. Auto-approve if the tool is read-only.
+	if tool.Annotations.ReadOnlyHint {
+		runTool(withToolApproval(ctx, toolApproval{by: audit.SourceReadOnlyHint}))
 		return false
 	}
 
+	// Default: ask the user for confirmation
+	return r.askUserForConfirmation(ctx, sess, toolCall, tool, events, a, "No permission rule matches this tool call and the tool is not read-only.", runTool)
+}
+
+// permissionMatch is the outcome of the permission checkers for a tool call.
+type permissionMatch struct {
+	decision    permissions.Decision
+	source      string
+	auditSource audit.Source
+	pattern     string
+}
+
+// checkPermissions evaluates the session and team permission checkers, in
+// priority order, and returns the first explicit decision along with its
+// source and the pattern that matched. The decision is permissions.Ask when
+// no rule matched.
+func (r *LocalRuntime) checkPermissions(sess *session.Session, toolCall tools.ToolCall) permissionMatch {
+	toolName := toolCall.Function.Name
+
 	// Parse tool arguments once for permission matching
 	var toolArgs map[string]any
 	if toolCall.Function.Arguments != "" {
@@ -120,40 +297,20 @@ func (r *LocalRuntime) executeWithApproval(
 	}
 
 	// Collect permission checkers in priority order (session first, then team)
-	checkers := r.permissionCheckers(sess)
-
-	for _, pc := range checkers {
-		switch pc.checker.CheckWithArgs(toolName, toolArgs) {
-		case permissions.Deny:
-			slog.Debug("Tool denied by permissions", "tool", toolName, "source", pc.source, "session_id", sess.ID)
-			r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, fmt.Sprintf("Tool '%s' is denied by %s.", toolName, pc.source))
-			return false
-		case permissions.Allow:
-			slog.Debug("Tool auto-approved by permissions", "tool", toolName, "source", pc.source, "session_id", sess.ID)
-			runTool()
-			return false
-		case permissions.ForceAsk:
-			slog.Debug("Tool requires confirmation (ask pattern)", "tool", toolName, "source", pc.source, "session_id", sess.ID)
-			return r.askUserForConfirmation(ctx, sess, toolCall, tool, events, a, runTool)
-		case permissions.Ask:
-			// No explicit match at this level; fall through to next checker
+	for _, pc := range r.permissionCheckers(sess) {
+		// Ask means no explicit match at this level; fall through to next checker
+		if decision, pattern := pc.checker.Evaluate(toolName, toolArgs); decision != permissions.Ask {
+			return permissionMatch{decision: decision, source: pc.source, auditSource: pc.auditSource, pattern: pattern}
 		}
 	}
-
-	// No permission rule matched. Auto-approve if the tool is read-only.
-	if tool.Annotations.ReadOnlyHint {
-		runTool()
-		return false
-	}
-
-	// Default: ask the user for confirmation
-	return r.askUserForConfirmation(ctx, sess, toolCall, tool, events, a, runTool)
+	return permissionMatch{decision: permissions.Ask}
 }
 
 // permissionChecker pairs a checker with a human-readable source label.
 type permissionChecker struct {
-	checker *permissions.Checker
-	source  string
+	checker     *permissions.Checker
+	source      string
+	auditSource audit.Source
 }
 
 // permissionCheckers returns the ordered list of permission checkers to evaluate.
@@ -165,14 +322,18 @@ func (r *LocalRuntime) permissionCheckers(sess *session.Session) []permissionChe
 				Allow: sess.Permissions.Allow,
 				Ask:   sess.Permissions.Ask,
 				Deny:  sess.Permissions.Deny,
-			}),
-			source: "session permissions",
+			}, permissions.WithWorkingDir(cmp.Or(sess.WorkingDir, r.workingDir))),
+			source:      "session permissions",
+			auditSource: audit.SourceSessionPermissions,
 		})
 	}
 	if tc := r.team.Permissions(); tc != nil {
 		checkers = append(checkers, permissionChecker{
-			checker: tc,
-			source:  "permissions configuration",
+			// Relative paths of the rules are relative to the session's
+			// working directory, not to the one the team was loaded from.
+			checker:     tc.ForWorkingDir(sess.WorkingDir),
+			source:      "permissions configuration",
+			auditSource: audit.SourcePermissions,
 		})
 	}
 	return checkers
@@ -180,6 +341,7 @@ func (r *LocalRuntime) permissionCheckers(sess *session.Session) []permissionChe
 
 // askUserForConfirmation sends a confirmation event and waits for user response.
 // This is only called when --yolo is not active and no permission rule auto-approved the tool.
+// The reason explains to the user why the tool call needs confirmation.
 func (r *LocalRuntime) askUserForConfirmation(
 	ctx context.Context,
 	sess *session.Session,
@@ -187,11 +349,12 @@ func (r *LocalRuntime) askUserForConfirmation(
 	tool tools.Tool,
 	events chan Event,
 	a *agent.Agent,
-	runTool func(),
+	reason string,
+	runTool func(ctx context.Context),
 ) (canceled bool) {
 	toolName := toolCall.Function.Name
 	slog.Debug("Tools not approved, waiting for resume", "tool", toolName, "session_id", sess.ID)
-	events <- ToolCallConfirmation(toolCall, tool, a.Name())
+	events <- ToolCallConfirmation(toolCall, tool, reason, a.Name())
 
 	r.executeOnUserInputHooks(ctx, sess.ID, "tool confirmation")
 
@@ -200,41 +363,46 @@ func (r *LocalRuntime) askUserForConfirmation(
 		switch req.Type {
 		case ResumeTypeApprove:
 			slog.Debug("Resume signal received, approving tool", "tool", toolName, "session_id", sess.ID)
-			runTool()
+			runTool(withToolApproval(ctx, toolApproval{by: audit.SourceUser}))
 		case ResumeTypeApproveSession:
 			slog.Debug("Resume signal received, approving session", "tool", toolName, "session_id", sess.ID)
 			sess.ToolsApproved = true
-			runTool()
+			runTool(withToolApproval(ctx, toolApproval{by: audit.SourceUser}))
 		case ResumeTypeApproveTool:
 			// Add the tool to session's allow list for future auto-approval
-			approvedTool := req.ToolName
-			if approvedTool == "" {
-				approvedTool = toolName
-			}
-			if sess.Permissions == nil {
-				sess.Permissions = &session.PermissionsConfig{}
-			}
-			if !slices.Contains(sess.Permissions.Allow, approvedTool) {
-				sess.Permissions.Allow = append(sess.Permissions.Allow, approvedTool)
-			}
+			approvedTool := cmp.Or(req.ToolName, toolName)
+			allowForSession(sess, approvedTool)
 			slog.Debug("Resume signal received, approving tool permanently", "tool", approvedTool, "session_id", sess.ID)
-			runTool()
+			runTool(withToolApproval(ctx, toolApproval{by: audit.SourceUser}))
 		case ResumeTypeReject:
 			slog.Debug("Resume signal received, rejecting tool", "tool", toolName, "session_id", sess.ID, "reason", req.Reason)
 			rejectMsg := "The user rejected the tool call."
 			if strings.TrimSpace(req.Reason) != "" {
 				rejectMsg += " Reason: " + strings.TrimSpace(req.Reason)
 			}
+			r.auditToolDenied(sess, a, toolCall, audit.DecisionDeny, audit.SourceUser, "", strings.TrimSpace(req.Reason))
 			r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, rejectMsg)
 		}
 		return false
 	case <-ctx.Done():
 		slog.Debug("Context cancelled while waiting for resume", "tool", toolName, "session_id", sess.ID)
+		r.auditToolDenied(sess, a, toolCall, audit.DecisionCanceled, audit.SourceUser, "", "")
 		r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, "The tool call was canceled by the user.")
 		return true
 	}
 }
 
+// allowForSession adds a pattern to the session's allow list, so that matching
+// tool calls are auto-approved for the rest of the session.
+func allowForSession(sess *session.Session, pattern string) {
+	if sess.Permissions == nil {
+		sess.Permissions = &session.PermissionsConfig{}
+	}
+	if !slices.Contains(sess.Permissions.Allow, pattern) {
+		sess.Permissions.Allow = append(sess.Permissions.Allow, pattern)
+	}
+}
+
 // executeToolWithHandler is a common helper that handles tool execution, error handling,
 // event emission, and session updates. It reduces duplication between runTool and runAgentTool.
 func (r *LocalRuntime) executeToolWithHandler(
@@ -247,20 +415,43 @@ func (r *LocalRuntime) executeToolWithHandler(
 	spanName string,
 	execute func(ctx context.Context) (*tools.ToolCallResult, time.Duration, error),
 ) {
-	ctx, span := r.startSpan(ctx, spanName, trace.WithAttributes(
-		attribute.String("tool.name", toolCall.Function.Name),
-		attribute.String("agent", a.Name()),
-		attribute.String("session.id", sess.ID),
-		attribute.String("tool.call_id", toolCall.ID),
-	))
+	ctx, span := r.startToolHandlerSpan(ctx, spanName, sess, toolCall, a)
 	defer span.End()
 
 	events <- ToolCall(toolCall, tool, a.Name())
 
+	start := time.Now()
 	res, duration, err := execute(ctx)
 
 	telemetry.RecordToolCall(ctx, toolCall.Function.Name, sess.ID, a.Name(), duration, err)
+	r.auditToolResult(ctx, sess, a, toolCall, toolApprovalFrom(ctx), res, err, time.Since(start))
+
+	r.addToolResult(ctx, span, toolCall, tool, res, err, events, sess, a)
+}
 
+// startToolHandlerSpan starts the span that covers a tool handler execution.
+func (r *LocalRuntime) startToolHandlerSpan(ctx context.Context, spanName string, sess *session.Session, toolCall tools.ToolCall, a *agent.Agent) (context.Context, trace.Span) {
+	return r.startSpan(ctx, spanName, trace.WithAttributes(
+		attribute.String("tool.name", toolCall.Function.Name),
+		attribute.String("agent", a.Name()),
+		attribute.String("session.id", sess.ID),
+		attribute.String("tool.call_id", toolCall.ID),
+	))
+}
+
+// addToolResult turns a tool handler's result (or error) into a tool
+// response, emits it and records it in the session.
+func (r *LocalRuntime) addToolResult(
+	ctx context.Context,
+	span trace.Span,
+	toolCall tools.ToolCall,
+	tool tools.Tool,
+	res *tools.ToolCallResult,
+	err error,
+	events chan Event,
+	sess *session.Session,
+	a *agent.Agent,
+) {
 	if err != nil {
 		if errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled) {
 			slog.Debug("Tool handler canceled by context", "tool", toolCall.Function.Name, "agent", a.Name(), "session_id", sess.ID)
@@ -277,6 +468,8 @@ func (r *LocalRuntime) executeToolWithHandler(
 		slog.Debug("Tool call completed", "tool", toolCall.Function.Name, "output_length", len(res.Output))
 	}
 
+	res = redactToolResult(a, toolCall, res)
+
 	events <- ToolCallResponse(toolCall, tool, res, res.Output, a.Name())
 
 	// Ensure tool response content is not empty for API compatibility
@@ -320,25 +513,61 @@ func (r *LocalRuntime) executeToolWithHandler(
 func (r *LocalRuntime) runTool(ctx context.Context, tool tools.Tool, toolCall tools.ToolCall, events chan Event, sess *session.Session, a *agent.Agent) {
 	hooksExec := r.getHooksExecutor(a)
 
+	run := func(ctx context.Context) {
+		r.executeToolWithHandler(ctx, toolCall, tool, events, sess, a, "runtime.tool.handler",
+			func(ctx context.Context) (*tools.ToolCallResult, time.Duration, error) {
+				res, err := tool.Handler(withToolProgress(ctx, toolCall, events, a), toolCall)
+				return res, 0, err
+			})
+
+		// Execute post-tool hooks if configured.
+		if hooksExec != nil && hooksExec.HasPostToolUseHooks() {
+			r.executePostToolHook(ctx, hooksExec, sess, toolCall, events, a)
+		}
+	}
+
 	// Execute pre-tool hooks if configured.
 	if hooksExec != nil && hooksExec.HasPreToolUseHooks() {
-		blocked, modifiedTC := r.executePreToolHook(ctx, hooksExec, sess, toolCall, tool, events, a)
-		if blocked {
+		verdict := r.checkPreToolHook(ctx, hooksExec, sess, toolCall, events, a)
+		toolCall = verdict.toolCall
+		if verdict.blocked {
+			r.addHookBlockedResponse(ctx, sess, toolCall, tool, events, a, verdict.message)
+			return
+		}
+		if verdict.ask {
+			r.confirmHookAsk(ctx, sess, toolCall, tool, events, a, verdict.message, run)
 			return
 		}
-		toolCall = modifiedTC
 	}
 
-	r.executeToolWithHandler(ctx, toolCall, tool, events, sess, a, "runtime.tool.handler",
-		func(ctx context.Context) (*tools.ToolCallResult, time.Duration, error) {
-			res, err := tool.Handler(ctx, toolCall)
-			return res, 0, err
-		})
+	run(ctx)
+}
 
-	// Execute post-tool hooks if configured.
-	if hooksExec != nil && hooksExec.HasPostToolUseHooks() {
-		r.executePostToolHook(ctx, hooksExec, sess, toolCall, events, a)
+// confirmHookAsk asks the user to confirm a tool call a pre-tool-use hook
+// asked about, unless the user already confirmed it. When tool calls are
+// auto-approved, nobody can confirm and the call is denied.
+func (r *LocalRuntime) confirmHookAsk(ctx context.Context, sess *session.Session, toolCall tools.ToolCall, tool tools.Tool, events chan Event, a *agent.Agent, reason string, run func(ctx context.Context)) {
+	if toolApprovalFrom(ctx).by == audit.SourceUser {
+		run(ctx)
+		return
 	}
+
+	reason = cmp.Or(reason, "A hook asks to confirm this tool call.")
+	if sess.ToolsApproved {
+		slog.Debug("Pre-tool hook asked for confirmation while tools are auto-approved", "tool", toolCall.Function.Name, "session_id", sess.ID)
+		r.addHookBlockedResponse(ctx, sess, toolCall, tool, events, a, reason+" Tool calls are auto-approved, so nobody can confirm it.")
+		return
+	}
+
+	r.askUserForConfirmation(ctx, sess, toolCall, tool, events, a, reason, run)
+}
+
+// withToolProgress returns the context of a tool call in which the tool, e.g.
+// an MCP tool, reports its progress as ToolCallProgress events.
+func withToolProgress(ctx context.Context, toolCall tools.ToolCall, events chan Event, a *agent.Agent) context.Context {
+	return tools.WithProgressHandler(ctx, func(progress tools.ToolProgress) {
+		events <- ToolCallProgress(toolCall.ID, progress, a.Name())
+	})
 }
 
 // newHooksInput builds a hooks.Input from the common tool-call fields.
@@ -352,40 +581,60 @@ func (r *LocalRuntime) newHooksInput(sess *session.Session, toolCall tools.ToolC
 	}
 }
 
-// executePreToolHook runs the pre-tool-use hook and returns whether the tool
-// call was blocked and the (possibly modified) tool call.
-func (r *LocalRuntime) executePreToolHook(
+// preToolVerdict is the outcome of the pre-tool-use hooks of a tool call.
+type preToolVerdict struct {
+	blocked bool
+	// ask is set when a hook asks the user to confirm the tool call.
+	ask bool
+	// message is the reason to block or to ask.
+	message string
+	// toolCall is the tool call, with the input the hooks modified.
+	toolCall tools.ToolCall
+}
+
+// checkPreToolHook runs the pre-tool-use hook without recording anything in
+// the session. The tool call is blocked when the hooks can't be run.
+func (r *LocalRuntime) checkPreToolHook(
 	ctx context.Context,
 	hooksExec *hooks.Executor,
 	sess *session.Session,
 	toolCall tools.ToolCall,
-	tool tools.Tool,
 	events chan Event,
 	a *agent.Agent,
-) (blocked bool, modifiedTC tools.ToolCall) {
+) preToolVerdict {
 	result, err := hooksExec.ExecutePreToolUse(ctx, r.newHooksInput(sess, toolCall))
 	switch {
 	case err != nil:
 		slog.Warn("Pre-tool hook execution failed", "tool", toolCall.Function.Name, "error", err)
+		return preToolVerdict{blocked: true, message: fmt.Sprintf("pre-tool hook failed: %v", err), toolCall: toolCall}
 	case !result.Allowed:
 		slog.Debug("Pre-tool hook blocked tool call", "tool", toolCall.Function.Name, "message", result.Message)
-		events <- HookBlocked(toolCall, tool, result.Message, a.Name())
-		r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, "Tool call blocked by hook: "+result.Message)
-		return true, toolCall
-	default:
-		if result.SystemMessage != "" {
-			events <- Warning(result.SystemMessage, a.Name())
-		}
-		if result.ModifiedInput != nil {
-			if updated, merr := json.Marshal(result.ModifiedInput); merr != nil {
-				slog.Warn("Failed to marshal modified tool input from hook", "tool", toolCall.Function.Name, "error", merr)
-			} else {
-				slog.Debug("Pre-tool hook modified tool input", "tool", toolCall.Function.Name)
-				toolCall.Function.Arguments = string(updated)
-			}
+		return preToolVerdict{blocked: true, message: result.Message, toolCall: toolCall}
+	}
+
+	if result.SystemMessage != "" {
+		events <- Warning(result.SystemMessage, a.Name())
+	}
+	if result.ModifiedInput != nil {
+		if updated, merr := json.Marshal(result.ModifiedInput); merr != nil {
+			slog.Warn("Failed to marshal modified tool input from hook", "tool", toolCall.Function.Name, "error", merr)
+		} else {
+			slog.Debug("Pre-tool hook modified tool input", "tool", toolCall.Function.Name)
+			toolCall.Function.Arguments = string(updated)
 		}
 	}
-	return false, toolCall
+	if result.Ask {
+		slog.Debug("Pre-tool hook asked for confirmation", "tool", toolCall.Function.Name, "message", result.Message)
+	}
+	return preToolVerdict{ask: result.Ask, message: result.Message, toolCall: toolCall}
+}
+
+// addHookBlockedResponse emits the hook blocked event and records the
+// corresponding tool error response.
+func (r *LocalRuntime) addHookBlockedResponse(ctx context.Context, sess *session.Session, toolCall tools.ToolCall, tool tools.Tool, events chan Event, a *agent.Agent, message string) {
+	events <- HookBlocked(toolCall, tool, message, a.Name())
+	r.auditToolDenied(sess, a, toolCall, audit.DecisionDeny, audit.SourceHook, "", message)
+	r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, "Tool call blocked by hook: "+message)
 }
 
 // executePostToolHook runs the post-tool-use hook and emits any system messages.
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Scope is a permission granted to an API caller.
+type Scope string
+
+const (
+	// ScopeSessionsRead allows listing and reading agents and sessions.
+	ScopeSessionsRead Scope = "sessions:read"
+	// ScopeAgentsRun allows creating, running, updating and deleting sessions.
+	ScopeAgentsRun Scope = "agents:run"
+	// ScopePermissionsWrite allows changing what tools a session may run
+	// without confirmation: session permissions, YOLO mode and
+	// "always allow" tool approvals.
+	ScopePermissionsWrite Scope = "permissions:write"
+	// ScopeAll grants every scope.
+	ScopeAll Scope = "*"
+)
+
+// ErrUnauthenticated is returned by an Authenticator when the request
+// carries no credentials or credentials it doesn't recognize.
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// Principal is the authenticated caller of an API request.
+type Principal struct {
+	// Subject identifies the caller: the token name for static tokens or
+	// the "sub" claim for JWTs.
+	Subject string
+	Scopes  []Scope
+}
+
+// HasScope reports whether the principal was granted the given scope.
+func (p *Principal) HasScope(scope Scope) bool {
+	return slices.Contains(p.Scopes, scope) || slices.Contains(p.Scopes, ScopeAll)
+}
+
+// Authenticator verifies the bearer token of an API request.
+type Authenticator interface {
+	// Authenticate returns the principal the token belongs to, or an error
+	// wrapping ErrUnauthenticated if the token isn't valid.
+	Authenticate(ctx context.Context, token string) (*Principal, error)
+}
+
+// Authenticators tries each authenticator in order and returns the first
+// principal that matches.
+type Authenticators []Authenticator
+
+func (a Authenticators) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	var errs []error
+	for _, auth := range a {
+		principal, err := auth.Authenticate(ctx, token)
+		if err == nil {
+			return principal, nil
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return nil, ErrUnauthenticated
+	}
+	return nil, errors.Join(errs...)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying the authenticated principal.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated principal, if any.
+// It returns nil when the server runs without authentication.
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
+
+// authMiddleware authenticates every request with a bearer token and stores
+// the principal in the request context.
+func authMiddleware(auth Authenticator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, ok := bearerToken(c.Request())
+			if !ok {
+				c.Response().Header().Set("WWW-Authenticate", "Bearer")
+				return echo.NewHTTPError(http.StatusUnauthorized, "missing bearer token")
+			}
+
+			principal, err := auth.Authenticate(c.Request().Context(), token)
+			if err != nil {
+				c.Response().Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				return echo.NewHTTPError(http.StatusUnauthorized, "invalid bearer token")
+			}
+
+			c.SetRequest(c.Request().WithContext(WithPrincipal(c.Request().Context(), principal)))
+			return next(c)
+		}
+	}
+}
+
+// requireScope rejects requests whose principal lacks the given scope.
+// Requests are let through when the server runs without authentication.
+func requireScope(scope Scope) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if err := checkScope(c, scope); err != nil {
+				return err
+			}
+			return next(c)
+		}
+	}
+}
+
+// checkScope returns a 403 error if the request's principal lacks the given scope.
+func checkScope(c echo.Context, scope Scope) error {
+	principal := PrincipalFromContext(c.Request().Context())
+	if principal == nil || principal.HasScope(scope) {
+		return nil
+	}
+	return echo.NewHTTPError(http.StatusForbidden, "missing scope: "+string(scope))
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
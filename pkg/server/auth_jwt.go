@@ -0,0 +1,224 @@
+package server
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTConfig configures a JWTVerifier.
+type JWTConfig struct {
+	// JWKSFile is the path to a local JSON Web Key Set used to verify
+	// token signatures, typically downloaded from the OIDC provider's
+	// jwks_uri.
+	JWKSFile string
+	// Issuer, when set, must match the "iss" claim.
+	Issuer string
+	// Audience, when set, must be contained in the "aud" claim.
+	Audience string
+}
+
+// JWTVerifier authenticates requests carrying a signed JWT, such as an OIDC
+// ID or access token. The principal's subject is the "sub" claim and its
+// scopes come from the space-separated "scope" claim or the "scp" claim.
+type JWTVerifier struct {
+	keys   map[string]crypto.PublicKey
+	parser *jwt.Parser
+}
+
+// NewJWTVerifier loads the key set and returns a verifier.
+func NewJWTVerifier(cfg JWTConfig) (*JWTVerifier, error) {
+	keys, err := loadJWKS(cfg.JWKSFile)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	return &JWTVerifier{
+		keys:   keys,
+		parser: jwt.NewParser(opts...),
+	}, nil
+}
+
+func (v *JWTVerifier) Authenticate(_ context.Context, token string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.keyFunc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnauthenticated, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: missing sub claim", ErrUnauthenticated)
+	}
+
+	return &Principal{
+		Subject: subject,
+		Scopes:  scopesFromClaims(claims),
+	}, nil
+}
+
+func (v *JWTVerifier) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	// Tokens without a kid are accepted when the key set has a single key.
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("no key found for kid %q", kid)
+}
+
+// scopesFromClaims reads scopes from the OAuth2 "scope" claim (a
+// space-separated string) or the "scp" claim (a string or a list).
+func scopesFromClaims(claims jwt.MapClaims) []Scope {
+	var scopes []Scope
+	add := func(v any) {
+		switch v := v.(type) {
+		case string:
+			for s := range strings.FieldsSeq(v) {
+				scopes = append(scopes, Scope(s))
+			}
+		case []any:
+			for _, s := range v {
+				if s, ok := s.(string); ok {
+					scopes = append(scopes, Scope(s))
+				}
+			}
+		}
+	}
+	add(claims["scope"])
+	add(claims["scp"])
+	return scopes
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// loadJWKS reads a JSON Web Key Set and returns its signature keys indexed by kid.
+func loadJWKS(path string) (map[string]crypto.PublicKey, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading JWKS file: %w", err)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(buf, &set); err != nil {
+		return nil, fmt.Errorf("parsing JWKS file %s: %w", path, err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("parsing key %q from %s: %w", k.Kid, path, err)
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no signature keys found in %s", path)
+	}
+
+	return keys, nil
+}
+
+func (k *jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent: %w", err)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate: %w", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate: %w", err)
+		}
+		// Uncompressed point encoding: 0x04 || X || Y.
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) > size || len(y) > size {
+			return nil, errors.New("invalid EC point")
+		}
+		point := make([]byte, 1+2*size)
+		point[0] = 4
+		copy(point[1+size-len(x):1+size], x)
+		copy(point[1+2*size-len(y):], y)
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(buf), nil
+}
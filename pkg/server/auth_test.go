@@ -0,0 +1,301 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"maps"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/session"
+)
+
+func TestStaticTokens(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "tokens.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`tokens:
+  - name: reader
+    token: read-token
+    scopes: [sessions:read]
+  - name: admin
+    token: admin-token
+    scopes: ["*"]
+`), 0o600))
+
+	tokens, err := LoadStaticTokens(path)
+	require.NoError(t, err)
+
+	principal, err := tokens.Authenticate(t.Context(), "read-token")
+	require.NoError(t, err)
+	assert.Equal(t, "reader", principal.Subject)
+	assert.True(t, principal.HasScope(ScopeSessionsRead))
+	assert.False(t, principal.HasScope(ScopeAgentsRun))
+
+	principal, err = tokens.Authenticate(t.Context(), "admin-token")
+	require.NoError(t, err)
+	assert.True(t, principal.HasScope(ScopePermissionsWrite))
+
+	_, err = tokens.Authenticate(t.Context(), "unknown")
+	require.ErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestStaticTokens_InvalidScope(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewStaticTokens(StaticToken{Name: "bad", Token: "t", Scopes: []Scope{"sessions:delete"}})
+	require.ErrorContains(t, err, "unknown scope")
+}
+
+func TestStaticTokens_Invalid(t *testing.T) {
+	t.Parallel()
+
+	all := []Scope{ScopeAll}
+
+	_, err := NewStaticTokens(StaticToken{Token: "t", Scopes: all})
+	require.ErrorContains(t, err, "has no name")
+
+	_, err = NewStaticTokens(
+		StaticToken{Name: "ci", Token: "t1", Scopes: all},
+		StaticToken{Name: "ci", Token: "t2", Scopes: all},
+	)
+	require.ErrorContains(t, err, `duplicate name "ci"`)
+
+	_, err = NewStaticTokens(StaticToken{Name: "ci", Token: "t"})
+	require.ErrorContains(t, err, "has no scopes")
+}
+
+func TestJWTVerifier(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	jwksPath := writeJWKS(t, map[string]any{
+		"keys": []map[string]any{
+			{
+				"kty": "RSA",
+				"kid": "rsa",
+				"use": "sig",
+				"n":   b64(rsaKey.N.Bytes()),
+				"e":   b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+			},
+			{
+				"kty": "EC",
+				"kid": "ec",
+				"crv": "P-256",
+				"x":   b64(ecKey.X.Bytes()),
+				"y":   b64(ecKey.Y.Bytes()),
+			},
+		},
+	})
+
+	verifier, err := NewJWTVerifier(JWTConfig{JWKSFile: jwksPath, Issuer: "https://issuer", Audience: "docker-agent"})
+	require.NoError(t, err)
+
+	claims := jwt.MapClaims{
+		"sub":   "alice",
+		"iss":   "https://issuer",
+		"aud":   "docker-agent",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "sessions:read agents:run",
+	}
+
+	t.Run("rsa", func(t *testing.T) {
+		t.Parallel()
+
+		principal, err := verifier.Authenticate(t.Context(), signJWT(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims))
+		require.NoError(t, err)
+		assert.Equal(t, "alice", principal.Subject)
+		assert.Equal(t, []Scope{ScopeSessionsRead, ScopeAgentsRun}, principal.Scopes)
+	})
+
+	t.Run("ecdsa", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := verifier.Authenticate(t.Context(), signJWT(t, jwt.SigningMethodES256, "ec", ecKey, claims))
+		require.NoError(t, err)
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		t.Parallel()
+
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		_, err = verifier.Authenticate(t.Context(), signJWT(t, jwt.SigningMethodRS256, "rsa", otherKey, claims))
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	t.Run("wrong audience", func(t *testing.T) {
+		t.Parallel()
+
+		other := maps.Clone(claims)
+		other["aud"] = "someone-else"
+		_, err := verifier.Authenticate(t.Context(), signJWT(t, jwt.SigningMethodRS256, "rsa", rsaKey, other))
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		t.Parallel()
+
+		other := maps.Clone(claims)
+		other["exp"] = time.Now().Add(-time.Hour).Unix()
+		_, err := verifier.Authenticate(t.Context(), signJWT(t, jwt.SigningMethodRS256, "rsa", rsaKey, other))
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+}
+
+func TestServer_AuthScopes(t *testing.T) {
+	t.Parallel()
+
+	tokens, err := NewStaticTokens(
+		StaticToken{Name: "reader", Token: "read-token", Scopes: []Scope{ScopeSessionsRead}},
+		StaticToken{Name: "runner", Token: "run-token", Scopes: []Scope{ScopeSessionsRead, ScopeAgentsRun}},
+	)
+	require.NoError(t, err)
+
+	sources, err := config.ResolveSources(prepareAgentsDir(t), nil)
+	require.NoError(t, err)
+	srv, err := New(t.Context(), session.NewInMemorySessionStore(), &config.RuntimeConfig{}, 0, sources, WithAuthenticator(tokens))
+	require.NoError(t, err)
+
+	do := func(method, path, token string) int {
+		req := httptest.NewRequestWithContext(t.Context(), method, path, http.NoBody)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		srv.e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/api/ping", ""))
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/api/sessions", ""))
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/api/sessions", "nope"))
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/api/sessions", "read-token"))
+	assert.Equal(t, http.StatusForbidden, do(http.MethodDelete, "/api/sessions/some-id", "read-token"))
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPost, "/api/sessions/some-id/tools/toggle", "run-token"))
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPatch, "/api/sessions/some-id/permissions", "run-token"))
+}
+
+func TestServer_CreateSessionScopes(t *testing.T) {
+	t.Parallel()
+
+	tokens, err := NewStaticTokens(
+		StaticToken{Name: "runner", Token: "run-token", Scopes: []Scope{ScopeSessionsRead, ScopeAgentsRun}},
+		StaticToken{Name: "admin", Token: "admin-token", Scopes: []Scope{ScopeAgentsRun, ScopePermissionsWrite}},
+	)
+	require.NoError(t, err)
+
+	sources, err := config.ResolveSources(prepareAgentsDir(t), nil)
+	require.NoError(t, err)
+	store := session.NewInMemorySessionStore()
+	srv, err := New(t.Context(), store, &config.RuntimeConfig{}, 0, sources, WithAuthenticator(tokens))
+	require.NoError(t, err)
+
+	do := func(token, body string) int {
+		req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/api/sessions", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		srv.e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, do("run-token", `{}`))
+	assert.Equal(t, http.StatusForbidden, do("run-token", `{"tools_approved":true}`))
+	assert.Equal(t, http.StatusForbidden, do("run-token", `{"permissions":{"allow":["*"]}}`))
+	assert.Equal(t, http.StatusOK, do("admin-token", `{"tools_approved":true}`))
+	assert.Equal(t, http.StatusOK, do("admin-token", `{"permissions":{"allow":["*"]}}`))
+
+	sessions, err := store.GetSessions(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, sessions, 3)
+}
+
+func TestServer_SessionOwnership(t *testing.T) {
+	t.Parallel()
+
+	tokens, err := NewStaticTokens(
+		StaticToken{Name: "alice", Token: "alice-token", Scopes: []Scope{ScopeAll}},
+		StaticToken{Name: "bob", Token: "bob-token", Scopes: []Scope{ScopeAll}},
+	)
+	require.NoError(t, err)
+
+	sources, err := config.ResolveSources(prepareAgentsDir(t), nil)
+	require.NoError(t, err)
+	store := session.NewInMemorySessionStore()
+	srv, err := New(t.Context(), store, &config.RuntimeConfig{}, 0, sources, WithAuthenticator(tokens))
+	require.NoError(t, err)
+
+	do := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequestWithContext(t.Context(), method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		srv.e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The owner in the request body is ignored.
+	rec := do(http.MethodPost, "/api/sessions", "alice-token", `{"owner":"bob"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var created session.Session
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "alice", created.Owner)
+
+	var listed []map[string]any
+	require.NoError(t, json.Unmarshal(do(http.MethodGet, "/api/sessions", "alice-token", "").Body.Bytes(), &listed))
+	assert.Len(t, listed, 1)
+	require.NoError(t, json.Unmarshal(do(http.MethodGet, "/api/sessions", "bob-token", "").Body.Bytes(), &listed))
+	assert.Empty(t, listed)
+
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/api/sessions/"+created.ID, "bob-token", "").Code)
+	assert.Equal(t, http.StatusInternalServerError, do(http.MethodDelete, "/api/sessions/"+created.ID, "bob-token", "").Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/api/sessions/"+created.ID, "alice-token", "").Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodDelete, "/api/sessions/"+created.ID, "alice-token", "").Code)
+
+	_, err = store.GetSession(t.Context(), created.ID)
+	require.ErrorIs(t, err, session.ErrNotFound)
+}
+
+func b64(buf []byte) string {
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func writeJWKS(t *testing.T, jwks any) string {
+	t.Helper()
+
+	buf, err := json.Marshal(jwks)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, buf, 0o600))
+	return path
+}
+
+func signJWT(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// StaticToken is a pre-shared bearer token and the scopes it grants.
+type StaticToken struct {
+	Name   string  `json:"name"`
+	Token  string  `json:"token"`
+	Scopes []Scope `json:"scopes,omitempty"`
+}
+
+// tokensFile is the format of the file loaded by LoadStaticTokens:
+//
+//	tokens:
+//	  - name: ci
+//	    token: s3cr3t
+//	    scopes: [sessions:read, agents:run]
+type tokensFile struct {
+	Tokens []StaticToken `json:"tokens"`
+}
+
+// StaticTokens authenticates requests against a fixed list of bearer tokens.
+type StaticTokens struct {
+	tokens []StaticToken
+}
+
+// NewStaticTokens returns an authenticator for the given tokens.
+// Every token needs a unique name, which identifies the owner of the
+// sessions it creates, and at least one scope.
+func NewStaticTokens(tokens ...StaticToken) (*StaticTokens, error) {
+	names := make(map[string]bool, len(tokens))
+	for i, t := range tokens {
+		name := strings.TrimSpace(t.Name)
+		if name == "" {
+			return nil, fmt.Errorf("token #%d has no name", i+1)
+		}
+		if names[name] {
+			return nil, fmt.Errorf("token #%d: duplicate name %q", i+1, name)
+		}
+		names[name] = true
+		if strings.TrimSpace(t.Token) == "" {
+			return nil, fmt.Errorf("token #%d (%s) is empty", i+1, t.Name)
+		}
+		if len(t.Scopes) == 0 {
+			return nil, fmt.Errorf("token #%d (%s) has no scopes; use [%q] to grant every scope", i+1, t.Name, ScopeAll)
+		}
+		for _, scope := range t.Scopes {
+			if !isValidScope(scope) {
+				return nil, fmt.Errorf("token #%d (%s) has unknown scope %q", i+1, t.Name, scope)
+			}
+		}
+	}
+	return &StaticTokens{tokens: tokens}, nil
+}
+
+// LoadStaticTokens reads bearer tokens from a YAML or JSON file.
+func LoadStaticTokens(path string) (*StaticTokens, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading tokens file: %w", err)
+	}
+
+	var file tokensFile
+	if err := yaml.Unmarshal(buf, &file); err != nil {
+		return nil, fmt.Errorf("parsing tokens file %s: %w", path, err)
+	}
+	if len(file.Tokens) == 0 {
+		return nil, fmt.Errorf("no tokens found in %s", path)
+	}
+
+	return NewStaticTokens(file.Tokens...)
+}
+
+func (s *StaticTokens) Authenticate(_ context.Context, token string) (*Principal, error) {
+	for _, t := range s.tokens {
+		if subtle.ConstantTimeCompare([]byte(t.Token), []byte(token)) == 1 {
+			return &Principal{Subject: t.Name, Scopes: t.Scopes}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unknown static token", ErrUnauthenticated)
+}
+
+func isValidScope(scope Scope) bool {
+	switch scope {
+	case ScopeSessionsRead, ScopeAgentsRun, ScopePermissionsWrite, ScopeAll:
+		return true
+	default:
+		return false
+	}
+}
@@ -4,18 +4,26 @@ import (
 	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
 	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
 	"github.com/docker/docker-agent/pkg/api"
+	"github.com/docker/docker-agent/pkg/audit"
+	"github.com/docker/docker-agent/pkg/checkpoint"
 	"github.com/docker/docker-agent/pkg/config"
+	"github.com/docker/docker-agent/pkg/config/latest"
+	"github.com/docker/docker-agent/pkg/permissions"
+	"github.com/docker/docker-agent/pkg/runtime"
 	"github.com/docker/docker-agent/pkg/session"
 	"github.com/docker/docker-agent/pkg/upstream"
 )
@@ -25,7 +33,45 @@ type Server struct {
 	sm *SessionManager
 }
 
-func New(ctx context.Context, sessionStore session.Store, runConfig *config.RuntimeConfig, refreshInterval time.Duration, agentSources config.Sources) (*Server, error) {
+type Opt func(*options)
+
+type options struct {
+	auth        Authenticator
+	checkpoints *checkpoint.Store
+	auditLog    *audit.Log
+}
+
+// WithAuthenticator requires every API request, except the health check, to
+// carry a bearer token accepted by auth. Handlers then enforce the scopes
+// granted to the token.
+func WithAuthenticator(auth Authenticator) Opt {
+	return func(o *options) {
+		o.auth = auth
+	}
+}
+
+// WithCheckpoints snapshots the files changed by tools into store, so that
+// they can be restored with the checkpoint endpoints.
+func WithCheckpoints(store *checkpoint.Store) Opt {
+	return func(o *options) {
+		o.checkpoints = store
+	}
+}
+
+// WithAuditLog records the decisions taken on the tool calls of all the
+// sessions in log.
+func WithAuditLog(log *audit.Log) Opt {
+	return func(o *options) {
+		o.auditLog = log
+	}
+}
+
+func New(ctx context.Context, sessionStore session.Store, runConfig *config.RuntimeConfig, refreshInterval time.Duration, agentSources config.Sources, opts ...Opt) (*Server, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	e := echo.New()
 	e.Use(middleware.RequestLogger())
 	e.Use(echo.WrapMiddleware(upstream.Handler))
@@ -34,44 +80,58 @@ func New(ctx context.Context, sessionStore session.Store, runConfig *config.Runt
 		e:  e,
 		sm: NewSessionManager(ctx, agentSources, sessionStore, refreshInterval, runConfig),
 	}
+	s.sm.checkpoints = o.checkpoints
+	s.sm.auditLog = o.auditLog
+
+	// Health check endpoint, always reachable without authentication
+	e.GET("/api/ping", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+	})
 
 	group := e.Group("/api")
+	if o.auth != nil {
+		group.Use(authMiddleware(o.auth))
+	}
+
+	read := requireScope(ScopeSessionsRead)
+	run := requireScope(ScopeAgentsRun)
+	changePermissions := requireScope(ScopePermissionsWrite)
 
 	// List all available agents
-	group.GET("/agents", s.getAgents)
+	group.GET("/agents", s.getAgents, read)
 	// Get an agent by id
-	group.GET("/agents/:id", s.getAgentConfig)
+	group.GET("/agents/:id", s.getAgentConfig, read)
 
 	// List all sessions
-	group.GET("/sessions", s.getSessions)
+	group.GET("/sessions", s.getSessions, read)
+	// Search sessions by title and message content
+	group.GET("/sessions/search", s.searchSessions, read)
 	// Get a session by id
-	group.GET("/sessions/:id", s.getSession)
+	group.GET("/sessions/:id", s.getSession, read)
 	// Resume a session by id
-	group.POST("/sessions/:id/resume", s.resumeSession)
+	group.POST("/sessions/:id/resume", s.resumeSession, run)
 	// Toggle YOLO mode for a session
-	group.POST("/sessions/:id/tools/toggle", s.toggleSessionYolo)
+	group.POST("/sessions/:id/tools/toggle", s.toggleSessionYolo, changePermissions)
 	// Toggle thinking mode for a session
-	group.POST("/sessions/:id/thinking/toggle", s.toggleSessionThinking)
+	group.POST("/sessions/:id/thinking/toggle", s.toggleSessionThinking, run)
 	// Update session permissions
-	group.PATCH("/sessions/:id/permissions", s.updateSessionPermissions)
+	group.PATCH("/sessions/:id/permissions", s.updateSessionPermissions, changePermissions)
 	// Update session title
-	group.PATCH("/sessions/:id/title", s.updateSessionTitle)
+	group.PATCH("/sessions/:id/title", s.updateSessionTitle, run)
+	// List the turns that changed files, and restore the files to their state before a turn
+	group.GET("/sessions/:id/checkpoints", s.getCheckpoints, read)
+	group.POST("/sessions/:id/checkpoints/:turn/restore", s.restoreCheckpoint, run)
 	// Create a new session
-	group.POST("/sessions", s.createSession)
+	group.POST("/sessions", s.createSession, run)
 	// Delete a session
-	group.DELETE("/sessions/:id", s.deleteSession)
+	group.DELETE("/sessions/:id", s.deleteSession, run)
 	// Run an agent loop
-	group.POST("/sessions/:id/agent/:agent", s.runAgent)
-	group.POST("/sessions/:id/agent/:agent/:agent_name", s.runAgent)
-	group.POST("/sessions/:id/elicitation", s.elicitation)
+	group.POST("/sessions/:id/agent/:agent", s.runAgent, run)
+	group.POST("/sessions/:id/agent/:agent/:agent_name", s.runAgent, run)
+	group.POST("/sessions/:id/elicitation", s.elicitation, run)
 
 	// Agent tool count
-	group.GET("/agents/:id/:agent_name/tools/count", s.getAgentToolCount)
-
-	// Health check endpoint
-	group.GET("/ping", func(c echo.Context) error {
-		return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
-	})
+	group.GET("/agents/:id/:agent_name/tools/count", s.getAgentToolCount, read)
 
 	return s, nil
 }
@@ -170,11 +230,45 @@ func (s *Server) getSessions(c echo.Context) error {
 	return c.JSON(http.StatusOK, responses)
 }
 
+func (s *Server) searchSessions(c echo.Context) error {
+	query := strings.TrimSpace(c.QueryParam("q"))
+	if query == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing query parameter: q")
+	}
+
+	results, err := s.sm.SearchSessions(c.Request().Context(), query)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to search sessions: %v", err))
+	}
+
+	responses := make([]api.SessionSearchResult, len(results))
+	for i, result := range results {
+		responses[i] = api.SessionSearchResult{
+			ID:          result.ID,
+			Title:       result.Title,
+			CreatedAt:   result.CreatedAt.Format(time.RFC3339),
+			NumMessages: result.NumMessages,
+			Snippet:     result.Snippet,
+		}
+	}
+	return c.JSON(http.StatusOK, responses)
+}
+
 func (s *Server) createSession(c echo.Context) error {
 	var sessionTemplate session.Session
 	if err := c.Bind(&sessionTemplate); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
 	}
+	if err := validatePermissions(sessionTemplate.Permissions); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	// Starting a session in YOLO mode or with its own permissions changes
+	// what it may run without confirmation.
+	if sessionTemplate.ToolsApproved || sessionTemplate.Permissions != nil {
+		if err := checkScope(c, ScopePermissionsWrite); err != nil {
+			return err
+		}
+	}
 
 	sess, err := s.sm.CreateSession(c.Request().Context(), &sessionTemplate)
 	if err != nil {
@@ -210,6 +304,15 @@ func (s *Server) resumeSession(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
 	}
 
+	// Approving a whole session or always allowing a tool changes what
+	// the session may run without confirmation.
+	switch runtime.ResumeType(req.Confirmation) {
+	case runtime.ResumeTypeApproveSession, runtime.ResumeTypeApproveTool:
+		if err := checkScope(c, ScopePermissionsWrite); err != nil {
+			return err
+		}
+	}
+
 	if err := s.sm.ResumeSession(c.Request().Context(), c.Param("id"), req.Confirmation, req.Reason, req.ToolName); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to resume session: %v", err))
 	}
@@ -246,6 +349,9 @@ func (s *Server) updateSessionPermissions(c echo.Context) error {
 	if err := c.Bind(&req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
 	}
+	if err := validatePermissions(req.Permissions); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
 
 	if err := s.sm.UpdateSessionPermissions(c.Request().Context(), sessionID, req.Permissions); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to update session permissions: %v", err))
@@ -254,6 +360,19 @@ func (s *Server) updateSessionPermissions(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]string{"message": "session permissions updated"})
 }
 
+// validatePermissions returns an error if a rule of session permissions has an
+// invalid regular expression.
+func validatePermissions(perms *session.PermissionsConfig) error {
+	if perms == nil {
+		return nil
+	}
+	return permissions.NewChecker(&latest.PermissionsConfig{
+		Allow: perms.Allow,
+		Ask:   perms.Ask,
+		Deny:  perms.Deny,
+	}).Validate()
+}
+
 func (s *Server) updateSessionTitle(c echo.Context) error {
 	sessionID := c.Param("id")
 	var req api.UpdateSessionTitleRequest
@@ -271,6 +390,54 @@ func (s *Server) updateSessionTitle(c echo.Context) error {
 	})
 }
 
+func (s *Server) getCheckpoints(c echo.Context) error {
+	turns, err := s.sm.Checkpoints(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return checkpointError(err)
+	}
+
+	checkpoints := []api.Checkpoint{}
+	for _, turn := range turns {
+		cp := api.Checkpoint{Turn: turn.Number, Paths: []string{}}
+		for _, entry := range turn.Entries {
+			cp.Paths = append(cp.Paths, entry.Path)
+		}
+		checkpoints = append(checkpoints, cp)
+	}
+	return c.JSON(http.StatusOK, checkpoints)
+}
+
+func (s *Server) restoreCheckpoint(c echo.Context) error {
+	turn, err := strconv.Atoi(c.Param("turn"))
+	if err != nil || turn < 1 {
+		return echo.NewHTTPError(http.StatusBadRequest, "turn must be a positive integer")
+	}
+
+	restored, err := s.sm.RestoreCheckpoint(c.Request().Context(), c.Param("id"), turn)
+	if err != nil {
+		return checkpointError(err)
+	}
+
+	return c.JSON(http.StatusOK, api.RestoreCheckpointResponse{
+		Turn:     turn,
+		Restored: restored,
+	})
+}
+
+// checkpointError maps the errors of the checkpoint endpoints to HTTP errors.
+func checkpointError(err error) error {
+	switch {
+	case errors.Is(err, session.ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("session not found: %v", err))
+	case errors.Is(err, checkpoint.ErrNothingToRestore):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, runtime.ErrCheckpointsDisabled):
+		return echo.NewHTTPError(http.StatusNotImplemented, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("checkpoint operation failed: %v", err))
+	}
+}
+
 func (s *Server) deleteSession(c echo.Context) error {
 	sessionID := c.Param("id")
 
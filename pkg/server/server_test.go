@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/docker/docker-agent/pkg/api"
+	"github.com/docker/docker-agent/pkg/checkpoint"
 	"github.com/docker/docker-agent/pkg/config"
 	"github.com/docker/docker-agent/pkg/session"
 )
@@ -199,14 +200,66 @@ func TestServer_UpdateSessionTitle(t *testing.T) {
 	assert.Equal(t, newTitle, sessionResp.Title)
 }
 
-func startServerWithStore(t *testing.T, ctx context.Context, agentsDir string, store session.Store) string {
+func TestServer_SearchSessions(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store := session.NewInMemorySessionStore()
+	require.NoError(t, store.AddSession(ctx, session.New(
+		session.WithTitle("Fix the migration bug"),
+		session.WithUserMessage("the migration fails"),
+	)))
+	lnPath := startServerWithStore(t, ctx, prepareAgentsDir(t), store)
+
+	buf := httpGET(t, ctx, lnPath, "/api/sessions/search?q=migration")
+	var results []api.SessionSearchResult
+	unmarshal(t, buf, &results)
+
+	require.Len(t, results, 1)
+	assert.Equal(t, "Fix the migration bug", results[0].Title)
+	assert.Equal(t, "Fix the <mark>migration</mark> bug", results[0].Snippet)
+}
+
+func TestServer_RestoreCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store := session.NewInMemorySessionStore()
+	sess := session.New()
+	require.NoError(t, store.AddSession(ctx, sess))
+
+	checkpoints := checkpoint.NewStore(t.TempDir())
+	file := filepath.Join(t.TempDir(), "file.txt")
+	require.NoError(t, os.WriteFile(file, []byte("before"), 0o644))
+	require.NoError(t, checkpoints.Snapshot(sess.ID, 1, file))
+	require.NoError(t, os.WriteFile(file, []byte("after"), 0o644))
+
+	lnPath := startServerWithStore(t, ctx, prepareAgentsDir(t), store, WithCheckpoints(checkpoints))
+
+	buf := httpGET(t, ctx, lnPath, "/api/sessions/"+sess.ID+"/checkpoints")
+	var list []api.Checkpoint
+	unmarshal(t, buf, &list)
+	require.Len(t, list, 1)
+	assert.Equal(t, api.Checkpoint{Turn: 1, Paths: []string{file}}, list[0])
+
+	buf = httpDo(t, ctx, http.MethodPost, lnPath, "/api/sessions/"+sess.ID+"/checkpoints/1/restore", nil)
+	var resp api.RestoreCheckpointResponse
+	unmarshal(t, buf, &resp)
+	assert.Equal(t, []string{file}, resp.Restored)
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "before", string(content))
+}
+
+func startServerWithStore(t *testing.T, ctx context.Context, agentsDir string, store session.Store, opts ...Opt) string {
 	t.Helper()
 
 	runConfig := config.RuntimeConfig{}
 
 	sources, err := config.ResolveSources(agentsDir, nil)
 	require.NoError(t, err)
-	srv, err := New(ctx, store, &runConfig, 0, sources)
+	srv, err := New(ctx, store, &runConfig, 0, sources, opts...)
 	require.NoError(t, err)
 
 	socketPath := "unix://" + filepath.Join(t.TempDir(), "sock")
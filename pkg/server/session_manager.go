@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/docker/docker-agent/pkg/api"
+	"github.com/docker/docker-agent/pkg/audit"
+	"github.com/docker/docker-agent/pkg/checkpoint"
 	"github.com/docker/docker-agent/pkg/concurrent"
 	"github.com/docker/docker-agent/pkg/config"
 	"github.com/docker/docker-agent/pkg/runtime"
@@ -33,6 +35,8 @@ type activeRuntimes struct {
 type SessionManager struct {
 	runtimeSessions *concurrent.Map[string, *activeRuntimes]
 	sessionStore    session.Store
+	checkpoints     *checkpoint.Store
+	auditLog        *audit.Log
 	Sources         config.Sources
 
 	// TODO: We have to do something about this, it's weird, session creation should send everything that is needed.
@@ -63,11 +67,16 @@ func NewSessionManager(ctx context.Context, sources config.Sources, sessionStore
 }
 
 // GetSession retrieves a session by ID.
+// When the server runs with authentication, sessions owned by another
+// caller are reported as not found.
 func (sm *SessionManager) GetSession(ctx context.Context, id string) (*session.Session, error) {
 	sess, err := sm.sessionStore.GetSession(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if !ownsSession(ctx, sess) {
+		return nil, session.ErrNotFound
+	}
 	return sess, nil
 }
 
@@ -98,24 +107,47 @@ func (sm *SessionManager) CreateSession(ctx context.Context, sessionTemplate *se
 		opts = append(opts, session.WithPermissions(sessionTemplate.Permissions))
 	}
 
+	// The owner always comes from the authenticated caller, never from the template.
+	if principal := PrincipalFromContext(ctx); principal != nil {
+		opts = append(opts, session.WithOwner(principal.Subject))
+	}
+
 	sess := session.New(opts...)
 	return sess, sm.sessionStore.AddSession(ctx, sess)
 }
 
-// GetSessions retrieves all sessions.
+// GetSessions retrieves all sessions, or only the caller's sessions
+// when the server runs with authentication.
 func (sm *SessionManager) GetSessions(ctx context.Context) ([]*session.Session, error) {
-	sessions, err := sm.sessionStore.GetSessions(ctx)
+	var (
+		sessions []*session.Session
+		err      error
+	)
+	if principal := PrincipalFromContext(ctx); principal != nil {
+		sessions, err = sm.sessionStore.GetSessionsByOwner(ctx, principal.Subject)
+	} else {
+		sessions, err = sm.sessionStore.GetSessions(ctx)
+	}
 	if err != nil {
 		return nil, err
 	}
 	return sessions, nil
 }
 
+// SearchSessions searches all sessions, or only the caller's sessions
+// when the server runs with authentication.
+func (sm *SessionManager) SearchSessions(ctx context.Context, query string) ([]session.SearchResult, error) {
+	if principal := PrincipalFromContext(ctx); principal != nil {
+		return sm.sessionStore.SearchSessionsByOwner(ctx, principal.Subject, query)
+	}
+	return sm.sessionStore.SearchSessions(ctx, query)
+}
+
 // DeleteSession deletes a session by ID.
 func (sm *SessionManager) DeleteSession(ctx context.Context, sessionID string) error {
 	sm.mux.Lock()
 	defer sm.mux.Unlock()
-	sess, err := sm.sessionStore.GetSession(ctx, sessionID)
+	sess, err := sm.GetSession(ctx, sessionID)
 	if err != nil {
 		return err
 	}
@@ -123,20 +155,49 @@ func (sm *SessionManager) DeleteSession(ctx context.Context, sessionID string) e
 	if err := sm.sessionStore.DeleteSession(ctx, sessionID); err != nil {
 		return err
 	}
+	if sm.checkpoints != nil {
+		if err := sm.checkpoints.Delete(sessionID); err != nil {
+			slog.Warn("Failed to delete session checkpoints", "session_id", sessionID, "error", err)
+		}
+	}
 
 	if sessionRuntime, ok := sm.runtimeSessions.Load(sess.ID); ok {
 		sessionRuntime.cancel()
+		sessionRuntime.runtime.DetachMCPResources(sess.ID)
 		sm.runtimeSessions.Delete(sess.ID)
 	}
 
 	return nil
 }
 
+// Checkpoints returns the turns of a session during which tools changed files.
+func (sm *SessionManager) Checkpoints(ctx context.Context, sessionID string) ([]checkpoint.Turn, error) {
+	if sm.checkpoints == nil {
+		return nil, runtime.ErrCheckpointsDisabled
+	}
+	if _, err := sm.GetSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+	return sm.checkpoints.Turns(sessionID)
+}
+
+// RestoreCheckpoint restores the files changed since the start of the given
+// turn of a session to their previous state.
+func (sm *SessionManager) RestoreCheckpoint(ctx context.Context, sessionID string, turn int) ([]string, error) {
+	if sm.checkpoints == nil {
+		return nil, runtime.ErrCheckpointsDisabled
+	}
+	if _, err := sm.GetSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+	return sm.checkpoints.Restore(sessionID, turn)
+}
+
 // RunSession runs a session with the given messages.
 func (sm *SessionManager) RunSession(ctx context.Context, sessionID, agentFilename, currentAgent string, messages []api.Message) (<-chan runtime.Event, error) {
 	sm.mux.Lock()
 	defer sm.mux.Unlock()
-	sess, err := sm.sessionStore.GetSession(ctx, sessionID)
+	sess, err := sm.GetSession(ctx, sessionID)
 	if err != nil {
 		return nil, err
 	}
@@ -216,7 +277,7 @@ func (sm *SessionManager) ResumeSession(ctx context.Context, sessionID, confirma
 
 	// Ensure the session runtime exists
 	rt, exists := sm.runtimeSessions.Load(sessionID)
-	if !exists {
+	if !exists || !ownsSession(ctx, rt.session) {
 		return errors.New("session not found")
 	}
 
@@ -233,7 +294,7 @@ func (sm *SessionManager) ResumeElicitation(ctx context.Context, sessionID, acti
 	sm.mux.Lock()
 	defer sm.mux.Unlock()
 	rt, exists := sm.runtimeSessions.Load(sessionID)
-	if !exists {
+	if !exists || !ownsSession(ctx, rt.session) {
 		return errors.New("session not found")
 	}
 
@@ -244,7 +305,7 @@ func (sm *SessionManager) ResumeElicitation(ctx context.Context, sessionID, acti
 func (sm *SessionManager) ToggleToolApproval(ctx context.Context, sessionID string) error {
 	sm.mux.Lock()
 	defer sm.mux.Unlock()
-	sess, err := sm.sessionStore.GetSession(ctx, sessionID)
+	sess, err := sm.GetSession(ctx, sessionID)
 	if err != nil {
 		return err
 	}
@@ -258,7 +319,7 @@ func (sm *SessionManager) ToggleToolApproval(ctx context.Context, sessionID stri
 func (sm *SessionManager) ToggleThinking(ctx context.Context, sessionID string) error {
 	sm.mux.Lock()
 	defer sm.mux.Unlock()
-	sess, err := sm.sessionStore.GetSession(ctx, sessionID)
+	sess, err := sm.GetSession(ctx, sessionID)
 	if err != nil {
 		return err
 	}
@@ -272,7 +333,7 @@ func (sm *SessionManager) ToggleThinking(ctx context.Context, sessionID string)
 func (sm *SessionManager) UpdateSessionPermissions(ctx context.Context, sessionID string, perms *session.PermissionsConfig) error {
 	sm.mux.Lock()
 	defer sm.mux.Unlock()
-	sess, err := sm.sessionStore.GetSession(ctx, sessionID)
+	sess, err := sm.GetSession(ctx, sessionID)
 	if err != nil {
 		return err
 	}
@@ -292,13 +353,16 @@ func (sm *SessionManager) UpdateSessionTitle(ctx context.Context, sessionID, tit
 	// If session is actively running, update the in-memory session object directly.
 	// This ensures the runtime's saveSession won't overwrite our manual edit.
 	if rt, ok := sm.runtimeSessions.Load(sessionID); ok && rt.session != nil {
+		if !ownsSession(ctx, rt.session) {
+			return session.ErrNotFound
+		}
 		rt.session.Title = title
 		slog.Debug("Updated title for active session", "session_id", sessionID, "title", title)
 		return sm.sessionStore.UpdateSession(ctx, rt.session)
 	}
 
 	// Session is not actively running, load from store and update
-	sess, err := sm.sessionStore.GetSession(ctx, sessionID)
+	sess, err := sm.GetSession(ctx, sessionID)
 	if err != nil {
 		return err
 	}
@@ -343,6 +407,13 @@ func (sm *SessionManager) generateTitle(ctx context.Context, sess *session.Sessi
 	}
 }
 
+// ownsSession reports whether the authenticated caller may access sess.
+// Every session is accessible when the server runs without authentication.
+func ownsSession(ctx context.Context, sess *session.Session) bool {
+	principal := PrincipalFromContext(ctx)
+	return principal == nil || (sess != nil && principal.Subject != "" && sess.Owner == principal.Subject)
+}
+
 func (sm *SessionManager) runtimeForSession(ctx context.Context, sess *session.Session, agentFilename, currentAgent string, rc *config.RuntimeConfig) (runtime.Runtime, *sessiontitle.Generator, error) {
 	rt, exists := sm.runtimeSessions.Load(sess.ID)
 	if exists && rt.runtime != nil {
@@ -368,6 +439,12 @@ func (sm *SessionManager) runtimeForSession(ctx context.Context, sess *session.S
 		runtime.WithManagedOAuth(false),
 		runtime.WithSessionStore(sm.sessionStore),
 	}
+	if sm.checkpoints != nil {
+		opts = append(opts, runtime.WithCheckpoints(sm.checkpoints))
+	}
+	if sm.auditLog != nil {
+		opts = append(opts, runtime.WithAuditLog(sm.auditLog))
+	}
 	run, err := runtime.New(t, opts...)
 	if err != nil {
 		return nil, nil, err
@@ -94,6 +94,7 @@ func copySessionMetadata(dst, src *Session, title string) {
 	dst.SendUserMessage = src.SendUserMessage
 	dst.MaxIterations = src.MaxIterations
 	dst.Starred = src.Starred
+	dst.Owner = src.Owner
 	dst.Permissions = clonePermissionsConfig(src.Permissions)
 	dst.AgentModelOverrides = cloneStringMap(src.AgentModelOverrides)
 	dst.CustomModelsUsed = cloneStringSlice(src.CustomModelsUsed)
@@ -0,0 +1,106 @@
+package session
+
+import (
+	"log/slog"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/tokenizer"
+)
+
+// MessagesOpt configures how GetMessages builds the messages of a request.
+type MessagesOpt func(*messagesOptions)
+
+type messagesOptions struct {
+	tokenizer tokenizer.Tokenizer
+	budget    int
+}
+
+// WithTokenBudget makes GetMessages fit the messages into budget tokens, as
+// counted by tok. Older tool results are truncated first, then the oldest
+// turns are dropped. The system messages and the last turn are always kept.
+func WithTokenBudget(tok tokenizer.Tokenizer, budget int) MessagesOpt {
+	return func(o *messagesOptions) {
+		if tok != nil {
+			o.tokenizer = tok
+		}
+		o.budget = budget
+	}
+}
+
+// fitToBudget reduces messages until they take at most budget tokens.
+// It works in three steps, stopping as soon as the messages fit:
+//  1. replace the results of tools called before the last turn with a placeholder, oldest first;
+//  2. drop the oldest turns, a turn being a user message and the messages that follow it;
+//  3. replace the results of tools called during the last turn, oldest first.
+//
+// The messages may still exceed the budget when the system messages and the
+// last turn alone don't fit.
+func fitToBudget(messages []chat.Message, tok tokenizer.Tokenizer, budget int) []chat.Message {
+	counts := make([]int, len(messages))
+	total := 0
+	for i := range messages {
+		counts[i] = tokenizer.CountMessage(tok, &messages[i])
+		total += counts[i]
+	}
+	if total <= budget {
+		return messages
+	}
+
+	result := make([]chat.Message, len(messages))
+	copy(result, messages)
+
+	// Turns start at user messages; the last one is never dropped.
+	var turnStarts []int
+	for i := range result {
+		if result[i].Role == chat.MessageRoleUser {
+			turnStarts = append(turnStarts, i)
+		}
+	}
+	lastTurn := len(result)
+	if len(turnStarts) > 0 {
+		lastTurn = turnStarts[len(turnStarts)-1]
+	}
+
+	truncateTools := func(from, to int) {
+		for i := from; i < to && total > budget; i++ {
+			if result[i].Role != chat.MessageRoleTool || result[i].Content == toolContentPlaceholder {
+				continue
+			}
+			result[i].Content = toolContentPlaceholder
+			result[i].MultiContent = nil
+			newCount := tokenizer.CountMessage(tok, &result[i])
+			total -= counts[i] - newCount
+			counts[i] = newCount
+		}
+	}
+
+	truncateTools(0, lastTurn)
+
+	dropped := make([]bool, len(result))
+	for t := 0; t < len(turnStarts)-1 && total > budget; t++ {
+		for i := turnStarts[t]; i < turnStarts[t+1]; i++ {
+			if result[i].Role == chat.MessageRoleSystem {
+				continue
+			}
+			dropped[i] = true
+			total -= counts[i]
+		}
+	}
+
+	truncateTools(lastTurn, len(result))
+
+	kept := result[:0]
+	for i := range result {
+		if !dropped[i] {
+			kept = append(kept, result[i])
+		}
+	}
+
+	if total > budget {
+		slog.Warn("Messages exceed the token budget after truncation", "tokens", total, "budget", budget, "tokenizer", tok.Name())
+	} else {
+		slog.Debug("Fitted messages to the token budget", "tokens", total, "budget", budget, "tokenizer", tok.Name(), "messages", len(kept))
+	}
+
+	return kept
+}
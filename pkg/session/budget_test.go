@@ -0,0 +1,94 @@
+package session
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/agent"
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/tokenizer"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+func budgetTestMessages() []chat.Message {
+	return []chat.Message{
+		{Role: chat.MessageRoleSystem, Content: "system"},
+		{Role: chat.MessageRoleUser, Content: "first question"},
+		{Role: chat.MessageRoleAssistant, ToolCalls: []tools.ToolCall{{ID: "1", Function: tools.FunctionCall{Name: "read_file"}}}},
+		{Role: chat.MessageRoleTool, ToolCallID: "1", Content: strings.Repeat("a", 4000)},
+		{Role: chat.MessageRoleAssistant, Content: "first answer"},
+		{Role: chat.MessageRoleUser, Content: "second question"},
+		{Role: chat.MessageRoleAssistant, ToolCalls: []tools.ToolCall{{ID: "2", Function: tools.FunctionCall{Name: "read_file"}}}},
+		{Role: chat.MessageRoleTool, ToolCallID: "2", Content: strings.Repeat("b", 4000)},
+	}
+}
+
+func TestFitToBudget(t *testing.T) {
+	t.Parallel()
+
+	t.Run("keeps messages that fit", func(t *testing.T) {
+		t.Parallel()
+		messages := budgetTestMessages()
+
+		result := fitToBudget(messages, tokenizer.Heuristic, 10000)
+
+		assert.Equal(t, messages, result)
+	})
+
+	t.Run("truncates tool results of earlier turns first", func(t *testing.T) {
+		t.Parallel()
+		messages := budgetTestMessages()
+
+		result := fitToBudget(messages, tokenizer.Heuristic, 1200)
+
+		require.Len(t, result, len(messages))
+		assert.Equal(t, toolContentPlaceholder, result[3].Content)
+		assert.Equal(t, messages[7].Content, result[7].Content)
+		assert.LessOrEqual(t, tokenizer.CountMessages(tokenizer.Heuristic, result), 1200)
+
+		// The original messages are left untouched.
+		assert.Equal(t, strings.Repeat("a", 4000), messages[3].Content)
+	})
+
+	t.Run("drops the oldest turns", func(t *testing.T) {
+		t.Parallel()
+		messages := budgetTestMessages()
+
+		result := fitToBudget(messages, tokenizer.Heuristic, 1030)
+
+		assert.Equal(t, []chat.Message{messages[0], messages[5], messages[6], messages[7]}, result)
+	})
+
+	t.Run("truncates tool results of the last turn last", func(t *testing.T) {
+		t.Parallel()
+		messages := budgetTestMessages()
+
+		result := fitToBudget(messages, tokenizer.Heuristic, 100)
+
+		require.Len(t, result, 4)
+		assert.Equal(t, chat.MessageRoleSystem, result[0].Role)
+		assert.Equal(t, "second question", result[1].Content)
+		assert.Equal(t, toolContentPlaceholder, result[3].Content)
+	})
+}
+
+func TestGetMessagesWithTokenBudget(t *testing.T) {
+	t.Parallel()
+
+	s := New()
+	for _, msg := range budgetTestMessages()[1:] {
+		s.AddMessage(NewAgentMessage("root", &msg))
+	}
+	a := agent.New("root", "You are a helpful assistant")
+
+	messages := s.GetMessages(a)
+	budget := tokenizer.CountMessages(tokenizer.Heuristic, messages) - 1
+
+	fitted := s.GetMessages(a, WithTokenBudget(tokenizer.Heuristic, budget))
+
+	assert.LessOrEqual(t, tokenizer.CountMessages(tokenizer.Heuristic, fitted), budget)
+	assert.Len(t, fitted, len(messages))
+}
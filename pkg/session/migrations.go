@@ -332,6 +332,84 @@ func getAllMigrations() []Migration {
 			Description: "Add index on session_items(session_id, item_type) to speed up session summary message counts",
 			UpSQL:       `CREATE INDEX IF NOT EXISTS idx_session_items_session_type ON session_items(session_id, item_type)`,
 		},
+		{
+			ID:          19,
+			Name:        "019_add_owner_column",
+			Description: "Add owner column to sessions table for per-caller session scoping",
+			UpSQL: `
+				ALTER TABLE sessions ADD COLUMN owner TEXT DEFAULT '';
+				CREATE INDEX IF NOT EXISTS idx_sessions_owner ON sessions(owner, created_at);
+			`,
+			DownSQL: `
+				DROP INDEX IF EXISTS idx_sessions_owner;
+				ALTER TABLE sessions DROP COLUMN owner;
+			`,
+		},
+		{
+			ID:          20,
+			Name:        "020_add_sessions_fts",
+			Description: "Add a full-text index over session titles and message contents",
+			// Titles are indexed under the negated rowid of their session and
+			// items under their own id, so that triggers can update them by rowid.
+			// Only user and assistant messages and summaries are indexed.
+			UpSQL: `
+				CREATE VIRTUAL TABLE IF NOT EXISTS sessions_fts USING fts5(
+					session_id UNINDEXED,
+					body,
+					tokenize = 'unicode61 remove_diacritics 2'
+				);
+
+				CREATE VIEW IF NOT EXISTS session_items_fts_body AS
+					SELECT id, session_id,
+						CASE
+							WHEN item_type = 'summary' THEN summary_text
+							WHEN item_type = 'message' AND json_extract(message_json, '$.role') IN ('user', 'assistant')
+								THEN json_extract(message_json, '$.content')
+						END AS body
+					FROM session_items;
+
+				INSERT INTO sessions_fts(rowid, session_id, body)
+					SELECT -rowid, id, title FROM sessions WHERE COALESCE(title, '') != '';
+				INSERT INTO sessions_fts(rowid, session_id, body)
+					SELECT id, session_id, body FROM session_items_fts_body WHERE COALESCE(body, '') != '';
+
+				CREATE TRIGGER IF NOT EXISTS sessions_fts_insert AFTER INSERT ON sessions
+				WHEN COALESCE(new.title, '') != '' BEGIN
+					INSERT INTO sessions_fts(rowid, session_id, body) VALUES (-new.rowid, new.id, new.title);
+				END;
+				CREATE TRIGGER IF NOT EXISTS sessions_fts_update AFTER UPDATE OF title ON sessions BEGIN
+					DELETE FROM sessions_fts WHERE rowid = -old.rowid;
+					INSERT INTO sessions_fts(rowid, session_id, body)
+						SELECT -new.rowid, new.id, new.title WHERE COALESCE(new.title, '') != '';
+				END;
+				CREATE TRIGGER IF NOT EXISTS sessions_fts_delete AFTER DELETE ON sessions BEGIN
+					DELETE FROM sessions_fts WHERE session_id = old.id;
+				END;
+
+				CREATE TRIGGER IF NOT EXISTS session_items_fts_insert AFTER INSERT ON session_items BEGIN
+					INSERT INTO sessions_fts(rowid, session_id, body)
+						SELECT id, session_id, body FROM session_items_fts_body WHERE id = new.id AND COALESCE(body, '') != '';
+				END;
+				CREATE TRIGGER IF NOT EXISTS session_items_fts_update AFTER UPDATE OF message_json, summary_text ON session_items BEGIN
+					DELETE FROM sessions_fts WHERE rowid = old.id;
+					INSERT INTO sessions_fts(rowid, session_id, body)
+						SELECT id, session_id, body FROM session_items_fts_body WHERE id = new.id AND COALESCE(body, '') != '';
+				END;
+				CREATE TRIGGER IF NOT EXISTS session_items_fts_delete AFTER DELETE ON session_items BEGIN
+					DELETE FROM sessions_fts WHERE rowid = old.id;
+				END;
+			`,
+			DownSQL: `
+				DROP TRIGGER IF EXISTS session_items_fts_delete;
+				DROP TRIGGER IF EXISTS session_items_fts_update;
+				DROP TRIGGER IF EXISTS session_items_fts_insert;
+				DROP TRIGGER IF EXISTS sessions_fts_delete;
+				DROP TRIGGER IF EXISTS sessions_fts_update;
+				DROP TRIGGER IF EXISTS sessions_fts_insert;
+				DROP VIEW IF EXISTS session_items_fts_body;
+				DROP TABLE IF EXISTS sessions_fts;
+			`,
+		},
 	}
 }
 
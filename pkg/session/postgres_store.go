@@ -0,0 +1,702 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // registers the "pgx" database/sql driver
+)
+
+// postgresMigrationLockKey is the advisory lock taken while migrating so that
+// replicas starting at the same time don't race each other.
+const postgresMigrationLockKey = 0x646f636b6572 // "docker"
+
+// postgresSessionColumns lists the columns read by scanSession.
+// Postgres has no split_diff_view column, NULL stands in for it.
+const postgresSessionColumns = "id, tools_approved, input_tokens, output_tokens, title, cost, send_user_message, max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides, custom_models_used, thinking, parent_id, branch_parent_session_id, branch_parent_position, branch_created_at, NULL, owner"
+
+// IsPostgresDSN reports whether ref is a Postgres connection URL rather than
+// the path to a SQLite database.
+func IsPostgresDSN(ref string) bool {
+	return strings.HasPrefix(ref, "postgres://") || strings.HasPrefix(ref, "postgresql://")
+}
+
+// PostgresSessionStore implements Store using Postgres.
+// Several processes can share the same database.
+type PostgresSessionStore struct {
+	db *sql.DB
+}
+
+// NewPostgresSessionStore connects to the Postgres database at dsn and
+// migrates its schema.
+func NewPostgresSessionStore(ctx context.Context, dsn string) (Store, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connecting to postgres: %w", err)
+	}
+
+	if err := migratePostgres(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &PostgresSessionStore{db: db}, nil
+}
+
+// migratePostgres applies the pending Postgres migrations in a single transaction.
+func migratePostgres(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", postgresMigrationLockKey); err != nil {
+		return fmt.Errorf("locking migrations: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS migrations (
+			id INTEGER PRIMARY KEY,
+			name TEXT UNIQUE NOT NULL,
+			description TEXT,
+			applied_at TIMESTAMPTZ NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	for _, migration := range getPostgresMigrations() {
+		var count int
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM migrations WHERE name = $1", migration.Name).Scan(&count); err != nil {
+			return fmt.Errorf("failed to check if migration %s is applied: %w", migration.Name, err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, migration.UpSQL); err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", migration.Name, err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO migrations (id, name, description, applied_at) VALUES ($1, $2, $3, $4)",
+			migration.ID, migration.Name, migration.Description, time.Now()); err != nil {
+			return fmt.Errorf("failed to record migration %s: %w", migration.Name, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// getPostgresMigrations returns all available Postgres migrations in order.
+// They are separate from the SQLite migrations: the Postgres schema starts
+// out normalized and has none of the legacy columns.
+func getPostgresMigrations() []Migration {
+	return []Migration{
+		{
+			ID:          1,
+			Name:        "001_create_sessions",
+			Description: "Create sessions and session_items tables",
+			UpSQL: `
+				CREATE TABLE sessions (
+					id TEXT PRIMARY KEY,
+					title TEXT NOT NULL DEFAULT '',
+					created_at TIMESTAMPTZ NOT NULL,
+					tools_approved BOOLEAN NOT NULL DEFAULT false,
+					input_tokens BIGINT NOT NULL DEFAULT 0,
+					output_tokens BIGINT NOT NULL DEFAULT 0,
+					cost DOUBLE PRECISION NOT NULL DEFAULT 0,
+					send_user_message BOOLEAN NOT NULL DEFAULT true,
+					max_iterations INTEGER NOT NULL DEFAULT 0,
+					working_dir TEXT NOT NULL DEFAULT '',
+					starred BOOLEAN NOT NULL DEFAULT false,
+					permissions TEXT NOT NULL DEFAULT '',
+					agent_model_overrides TEXT NOT NULL DEFAULT '{}',
+					custom_models_used TEXT NOT NULL DEFAULT '[]',
+					thinking BOOLEAN NOT NULL DEFAULT true,
+					parent_id TEXT REFERENCES sessions(id) ON DELETE CASCADE,
+					branch_parent_session_id TEXT REFERENCES sessions(id) ON DELETE SET NULL,
+					branch_parent_position INTEGER,
+					branch_created_at TIMESTAMPTZ,
+					owner TEXT NOT NULL DEFAULT ''
+				);
+				CREATE INDEX idx_sessions_parent_id ON sessions(parent_id);
+				CREATE INDEX idx_sessions_branch_parent ON sessions(branch_parent_session_id);
+				CREATE INDEX idx_sessions_owner ON sessions(owner, created_at);
+
+				CREATE TABLE session_items (
+					id BIGSERIAL PRIMARY KEY,
+					session_id TEXT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+					position INTEGER NOT NULL,
+					item_type TEXT NOT NULL,
+					agent_name TEXT,
+					message_json TEXT,
+					implicit BOOLEAN NOT NULL DEFAULT false,
+					subsession_id TEXT REFERENCES sessions(id) ON DELETE SET NULL,
+					summary_text TEXT,
+					UNIQUE (session_id, position)
+				);
+				CREATE INDEX idx_session_items_session_type ON session_items(session_id, item_type);
+			`,
+		},
+	}
+}
+
+// sessionRow holds the marshaled column values of a session.
+type sessionRow struct {
+	permissions          string
+	agentModelOverrides  string
+	customModelsUsed     string
+	parentID             any
+	branchParentID       any
+	branchParentPosition any
+	branchCreatedAt      any
+}
+
+func newSessionRow(session *Session) (*sessionRow, error) {
+	row := &sessionRow{
+		agentModelOverrides: "{}",
+		customModelsUsed:    "[]",
+	}
+
+	if session.Permissions != nil {
+		buf, err := json.Marshal(session.Permissions)
+		if err != nil {
+			return nil, err
+		}
+		row.permissions = string(buf)
+	}
+	if len(session.AgentModelOverrides) > 0 {
+		buf, err := json.Marshal(session.AgentModelOverrides)
+		if err != nil {
+			return nil, err
+		}
+		row.agentModelOverrides = string(buf)
+	}
+	if len(session.CustomModelsUsed) > 0 {
+		buf, err := json.Marshal(session.CustomModelsUsed)
+		if err != nil {
+			return nil, err
+		}
+		row.customModelsUsed = string(buf)
+	}
+
+	// Use NULL for empty references to avoid foreign key constraint issues
+	if session.ParentID != "" {
+		row.parentID = session.ParentID
+	}
+	if session.BranchParentSessionID != "" {
+		row.branchParentID = session.BranchParentSessionID
+	}
+	if session.BranchParentPosition != nil {
+		row.branchParentPosition = *session.BranchParentPosition
+	}
+	if session.BranchCreatedAt != nil {
+		row.branchCreatedAt = *session.BranchCreatedAt
+	}
+
+	return row, nil
+}
+
+// AddSession adds a new session to the store, including any messages
+func (s *PostgresSessionStore) AddSession(ctx context.Context, session *Session) error {
+	if session.ID == "" {
+		return ErrEmptyID
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := s.addSessionTx(ctx, tx, session); err != nil {
+		return err
+	}
+
+	for position, item := range session.Messages {
+		if err := s.addItemTx(ctx, tx, session.ID, position, item); err != nil {
+			return fmt.Errorf("adding item at position %d: %w", position, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// addSessionTx inserts a session within a transaction.
+func (s *PostgresSessionStore) addSessionTx(ctx context.Context, tx *sql.Tx, session *Session) error {
+	row, err := newSessionRow(session)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO sessions (
+			id, tools_approved, input_tokens, output_tokens, title, cost, send_user_message,
+			max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides,
+			custom_models_used, thinking, parent_id, branch_parent_session_id,
+			branch_parent_position, branch_created_at, owner
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)`,
+		session.ID, session.ToolsApproved, session.InputTokens, session.OutputTokens,
+		session.Title, session.Cost, session.SendUserMessage, session.MaxIterations,
+		session.WorkingDir, session.CreatedAt, session.Starred,
+		row.permissions, row.agentModelOverrides, row.customModelsUsed, session.Thinking,
+		row.parentID, row.branchParentID, row.branchParentPosition, row.branchCreatedAt, session.Owner)
+	return err
+}
+
+// addItemTx inserts a session item within a transaction.
+func (s *PostgresSessionStore) addItemTx(ctx context.Context, tx *sql.Tx, sessionID string, position int, item Item) error {
+	switch {
+	case item.Message != nil:
+		msgJSON, err := json.Marshal(item.Message.Message)
+		if err != nil {
+			return fmt.Errorf("marshaling message: %w", err)
+		}
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO session_items (session_id, position, item_type, agent_name, message_json, implicit)
+			 VALUES ($1, $2, 'message', $3, $4, $5)`,
+			sessionID, position, item.Message.AgentName, string(msgJSON), item.Message.Implicit)
+		return err
+
+	case item.SubSession != nil:
+		// Recursively add the sub-session
+		subSession := item.SubSession
+		subSession.ParentID = sessionID
+
+		if err := s.addSessionTx(ctx, tx, subSession); err != nil {
+			return fmt.Errorf("inserting nested sub-session: %w", err)
+		}
+
+		for i, subItem := range subSession.Messages {
+			if err := s.addItemTx(ctx, tx, subSession.ID, i, subItem); err != nil {
+				return fmt.Errorf("inserting nested sub-session item %d: %w", i, err)
+			}
+		}
+
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO session_items (session_id, position, item_type, subsession_id)
+			 VALUES ($1, $2, 'subsession', $3)`,
+			sessionID, position, subSession.ID)
+		return err
+
+	case item.Summary != "":
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO session_items (session_id, position, item_type, summary_text)
+			 VALUES ($1, $2, 'summary', $3)`,
+			sessionID, position, item.Summary)
+		return err
+
+	default:
+		return nil // Empty item, skip
+	}
+}
+
+// nextPositionTx locks the session row and returns the position of its next item.
+// The lock serializes appends from concurrent writers, possibly in other processes.
+func (s *PostgresSessionStore) nextPositionTx(ctx context.Context, tx *sql.Tx, sessionID string) (int, error) {
+	var id string
+	err := tx.QueryRowContext(ctx, "SELECT id FROM sessions WHERE id = $1 FOR UPDATE", sessionID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrNotFound
+		}
+		return 0, err
+	}
+
+	var position int
+	err = tx.QueryRowContext(ctx,
+		"SELECT COALESCE(MAX(position), -1) + 1 FROM session_items WHERE session_id = $1", sessionID).Scan(&position)
+	return position, err
+}
+
+// GetSession retrieves a session by ID
+func (s *PostgresSessionStore) GetSession(ctx context.Context, id string) (*Session, error) {
+	if id == "" {
+		return nil, ErrEmptyID
+	}
+	return s.loadSession(ctx, id)
+}
+
+func (s *PostgresSessionStore) loadSession(ctx context.Context, id string) (*Session, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+postgresSessionColumns+" FROM sessions WHERE id = $1", id)
+
+	sess, err := scanSession(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	items, err := s.loadSessionItems(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("loading session items: %w", err)
+	}
+	sess.Messages = items
+
+	return sess, nil
+}
+
+// loadSessionItems loads all items for a session, recursively loading sub-sessions.
+func (s *PostgresSessionStore) loadSessionItems(ctx context.Context, sessionID string) ([]Item, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT position, item_type, agent_name, message_json, implicit, subsession_id, summary_text
+		 FROM session_items WHERE session_id = $1 ORDER BY position`, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect the rows first so the connection is released before loading sub-sessions.
+	var rawRows []sessionItemRow
+	for rows.Next() {
+		var row sessionItemRow
+		if err := rows.Scan(&row.position, &row.itemType, &row.agentName, &row.messageJSON, &row.implicit, &row.subsessionID, &row.summaryText); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rawRows = append(rawRows, row)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+
+	var items []Item
+	for _, row := range rawRows {
+		switch row.itemType {
+		case "message":
+			var msg Message
+			if err := json.Unmarshal([]byte(row.messageJSON.String), &msg.Message); err != nil {
+				return nil, fmt.Errorf("unmarshaling message at position %d: %w", row.position, err)
+			}
+			msg.AgentName = row.agentName.String
+			msg.Implicit = row.implicit
+			items = append(items, Item{Message: &msg})
+
+		case "subsession":
+			if !row.subsessionID.Valid || row.subsessionID.String == "" {
+				slog.Warn("Skipping subsession item with NULL reference", "session_id", sessionID, "position", row.position)
+				continue
+			}
+			subSession, err := s.loadSession(ctx, row.subsessionID.String)
+			if err != nil {
+				if errors.Is(err, ErrNotFound) {
+					slog.Warn("Skipping orphaned subsession reference", "session_id", sessionID, "subsession_id", row.subsessionID.String)
+					continue
+				}
+				return nil, fmt.Errorf("getting sub-session %s: %w", row.subsessionID.String, err)
+			}
+			items = append(items, Item{SubSession: subSession})
+
+		case "summary":
+			items = append(items, Item{Summary: row.summaryText.String})
+		}
+	}
+
+	return items, nil
+}
+
+// GetSessions retrieves all root sessions (excludes sub-sessions)
+func (s *PostgresSessionStore) GetSessions(ctx context.Context) ([]*Session, error) {
+	return s.getSessions(ctx, "")
+}
+
+// GetSessionsByOwner retrieves the root sessions owned by owner.
+func (s *PostgresSessionStore) GetSessionsByOwner(ctx context.Context, owner string) ([]*Session, error) {
+	return s.getSessions(ctx, "AND owner = $1", owner)
+}
+
+func (s *PostgresSessionStore) getSessions(ctx context.Context, filter string, args ...any) ([]*Session, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT "+postgresSessionColumns+" FROM sessions WHERE parent_id IS NULL "+filter+" ORDER BY created_at DESC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*Session
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for _, session := range sessions {
+		items, err := s.loadSessionItems(ctx, session.ID)
+		if err != nil {
+			return nil, fmt.Errorf("loading items for session %s: %w", session.ID, err)
+		}
+		session.Messages = items
+	}
+
+	return sessions, nil
+}
+
+// GetSessionSummaries retrieves lightweight session metadata for listing (excludes sub-sessions).
+func (s *PostgresSessionStore) GetSessionSummaries(ctx context.Context) ([]Summary, error) {
+	return s.getSessionSummaries(ctx, "")
+}
+
+// GetSessionSummariesByOwner retrieves lightweight metadata for the root sessions owned by owner.
+func (s *PostgresSessionStore) GetSessionSummariesByOwner(ctx context.Context, owner string) ([]Summary, error) {
+	return s.getSessionSummaries(ctx, "AND s.owner = $1", owner)
+}
+
+func (s *PostgresSessionStore) getSessionSummaries(ctx context.Context, filter string, args ...any) ([]Summary, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT s.id, s.title, s.created_at, s.starred, s.branch_parent_session_id,
+		        (SELECT COUNT(*) FROM session_items si WHERE si.session_id = s.id AND si.item_type = 'message')
+		 FROM sessions s
+		 WHERE s.parent_id IS NULL `+filter+`
+		 ORDER BY s.created_at DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []Summary
+	for rows.Next() {
+		var summary Summary
+		var branchParentID sql.NullString
+		if err := rows.Scan(&summary.ID, &summary.Title, &summary.CreatedAt, &summary.Starred, &branchParentID, &summary.NumMessages); err != nil {
+			return nil, err
+		}
+		summary.BranchParentSessionID = branchParentID.String
+		summaries = append(summaries, summary)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
+// DeleteSession deletes a session by ID
+func (s *PostgresSessionStore) DeleteSession(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrEmptyID
+	}
+	return s.execOne(ctx, "DELETE FROM sessions WHERE id = $1", id)
+}
+
+// UpdateSession updates an existing session's metadata, or creates it if it doesn't exist (upsert).
+// Only metadata is modified - use AddMessage, AddSubSession, AddSummary for items.
+// The owner is only set when the session is created and never changes.
+func (s *PostgresSessionStore) UpdateSession(ctx context.Context, session *Session) error {
+	if session.ID == "" {
+		return ErrEmptyID
+	}
+
+	row, err := newSessionRow(session)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO sessions (
+			id, tools_approved, input_tokens, output_tokens, title, cost, send_user_message,
+			max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides,
+			custom_models_used, thinking, parent_id, branch_parent_session_id,
+			branch_parent_position, branch_created_at, owner
+		)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+		 ON CONFLICT(id) DO UPDATE SET
+		   title = excluded.title,
+		   tools_approved = excluded.tools_approved,
+		   input_tokens = excluded.input_tokens,
+		   output_tokens = excluded.output_tokens,
+		   cost = excluded.cost,
+		   send_user_message = excluded.send_user_message,
+		   max_iterations = excluded.max_iterations,
+		   working_dir = excluded.working_dir,
+		   starred = excluded.starred,
+		   permissions = excluded.permissions,
+		   agent_model_overrides = excluded.agent_model_overrides,
+		   custom_models_used = excluded.custom_models_used,
+		   thinking = excluded.thinking,
+		   parent_id = excluded.parent_id,
+		   branch_parent_session_id = excluded.branch_parent_session_id,
+		   branch_parent_position = excluded.branch_parent_position,
+		   branch_created_at = excluded.branch_created_at`,
+		session.ID, session.ToolsApproved, session.InputTokens, session.OutputTokens,
+		session.Title, session.Cost, session.SendUserMessage, session.MaxIterations, session.WorkingDir,
+		session.CreatedAt, session.Starred, row.permissions, row.agentModelOverrides,
+		row.customModelsUsed, session.Thinking, row.parentID, row.branchParentID, row.branchParentPosition, row.branchCreatedAt,
+		session.Owner)
+	return err
+}
+
+// SetSessionStarred sets the starred status of a session.
+func (s *PostgresSessionStore) SetSessionStarred(ctx context.Context, id string, starred bool) error {
+	if id == "" {
+		return ErrEmptyID
+	}
+	return s.execOne(ctx, "UPDATE sessions SET starred = $1 WHERE id = $2", starred, id)
+}
+
+// AddMessage adds a message to a session at the next position.
+// Returns the ID of the created message item.
+func (s *PostgresSessionStore) AddMessage(ctx context.Context, sessionID string, msg *Message) (int64, error) {
+	if sessionID == "" {
+		return 0, ErrEmptyID
+	}
+
+	msgJSON, err := json.Marshal(msg.Message)
+	if err != nil {
+		return 0, fmt.Errorf("marshaling message: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	position, err := s.nextPositionTx(ctx, tx, sessionID)
+	if err != nil {
+		return 0, err
+	}
+
+	var id int64
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO session_items (session_id, position, item_type, agent_name, message_json, implicit)
+		 VALUES ($1, $2, 'message', $3, $4, $5) RETURNING id`,
+		sessionID, position, msg.AgentName, string(msgJSON), msg.Implicit).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("inserting message: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	slog.Debug("[STORE] AddMessage", "session_id", sessionID, "message_id", id, "role", msg.Message.Role, "agent", msg.AgentName)
+	return id, nil
+}
+
+// UpdateMessage updates an existing message by its ID.
+func (s *PostgresSessionStore) UpdateMessage(ctx context.Context, messageID int64, msg *Message) error {
+	msgJSON, err := json.Marshal(msg.Message)
+	if err != nil {
+		return fmt.Errorf("marshaling message: %w", err)
+	}
+
+	return s.execOne(ctx,
+		"UPDATE session_items SET message_json = $1, implicit = $2 WHERE id = $3",
+		string(msgJSON), msg.Implicit, messageID)
+}
+
+// AddSubSession creates a sub-session and links it to the parent.
+func (s *PostgresSessionStore) AddSubSession(ctx context.Context, parentSessionID string, subSession *Session) error {
+	if parentSessionID == "" || subSession.ID == "" {
+		return ErrEmptyID
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	position, err := s.nextPositionTx(ctx, tx, parentSessionID)
+	if err != nil {
+		return err
+	}
+
+	// addItemTx inserts the sub-session, its items and the reference in the parent.
+	if err := s.addItemTx(ctx, tx, parentSessionID, position, Item{SubSession: subSession}); err != nil {
+		return fmt.Errorf("inserting sub-session: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// AddSummary adds a summary item to a session at the next position.
+func (s *PostgresSessionStore) AddSummary(ctx context.Context, sessionID, summary string) error {
+	if sessionID == "" {
+		return ErrEmptyID
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	position, err := s.nextPositionTx(ctx, tx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO session_items (session_id, position, item_type, summary_text)
+		 VALUES ($1, $2, 'summary', $3)`,
+		sessionID, position, summary); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateSessionTokens updates only token/cost fields.
+func (s *PostgresSessionStore) UpdateSessionTokens(ctx context.Context, sessionID string, inputTokens, outputTokens int64, cost float64) error {
+	if sessionID == "" {
+		return ErrEmptyID
+	}
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE sessions SET input_tokens = $1, output_tokens = $2, cost = $3 WHERE id = $4",
+		inputTokens, outputTokens, cost, sessionID)
+	return err
+}
+
+// UpdateSessionTitle updates only the title.
+func (s *PostgresSessionStore) UpdateSessionTitle(ctx context.Context, sessionID, title string) error {
+	if sessionID == "" {
+		return ErrEmptyID
+	}
+	_, err := s.db.ExecContext(ctx, "UPDATE sessions SET title = $1 WHERE id = $2", title, sessionID)
+	return err
+}
+
+// Close closes the database connection pool.
+func (s *PostgresSessionStore) Close() error {
+	return s.db.Close()
+}
+
+// execOne runs a statement that must affect exactly one row.
+func (s *PostgresSessionStore) execOne(ctx context.Context, query string, args ...any) error {
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,286 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/docker/docker-agent/pkg/chat"
+)
+
+// Markers surrounding the matched terms in SearchResult.Snippet.
+const (
+	SnippetMatchStart = "<mark>"
+	SnippetMatchEnd   = "</mark>"
+)
+
+// maxSearchResults caps the number of sessions returned by a search.
+const maxSearchResults = 50
+
+// snippetContext is the number of characters kept around the first match
+// when building snippets in Go.
+const snippetContext = 40
+
+// SearchResult is a session matching a full-text search.
+type SearchResult struct {
+	Summary
+	// Snippet is an excerpt of the session title or of its best matching
+	// message, with the matched terms wrapped in SnippetMatchStart and
+	// SnippetMatchEnd.
+	Snippet string
+}
+
+// searchTerms splits a search query into lowercase terms.
+func searchTerms(query string) []string {
+	return strings.Fields(strings.ToLower(query))
+}
+
+// ftsQuery turns free text into an FTS5 query matching every term, the last
+// one as a prefix so that results update while the user is typing.
+// Terms are quoted so that FTS5 operators in the input are matched literally.
+func ftsQuery(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+	return strings.Join(quoted, " ") + "*"
+}
+
+// matchesAllTerms reports whether every term is found in text, ignoring case.
+func matchesAllTerms(text string, terms []string) bool {
+	text = strings.ToLower(text)
+	return !slices.ContainsFunc(terms, func(term string) bool {
+		return !strings.Contains(text, term)
+	})
+}
+
+// makeSnippet returns an excerpt of text around the first term found, with
+// every occurrence of the terms highlighted.
+func makeSnippet(text string, terms []string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		// Lowercasing changed byte offsets: only match the terms as typed.
+		lower = text
+	}
+
+	first := len(text)
+	for _, term := range terms {
+		if i := strings.Index(lower, term); i >= 0 {
+			first = min(first, i)
+		}
+	}
+	start := max(0, min(first, len(text))-snippetContext)
+	end := min(len(text), start+4*snippetContext)
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	for i := start; i < end; {
+		matched := 0
+		for _, term := range terms {
+			if strings.HasPrefix(lower[i:end], term) {
+				matched = max(matched, len(term))
+			}
+		}
+		if matched > 0 {
+			sb.WriteString(SnippetMatchStart + text[i:i+matched] + SnippetMatchEnd)
+			i += matched
+			continue
+		}
+		sb.WriteByte(text[i])
+		i++
+	}
+	if end < len(text) {
+		sb.WriteString("…")
+	}
+	return sb.String()
+}
+
+// SearchSessions searches the titles and message contents of root sessions.
+func (s *InMemorySessionStore) SearchSessions(_ context.Context, query string) ([]SearchResult, error) {
+	return s.search(query, func(*Session) bool { return true }), nil
+}
+
+// SearchSessionsByOwner is like SearchSessions but only searches the sessions owned by owner.
+func (s *InMemorySessionStore) SearchSessionsByOwner(_ context.Context, owner, query string) ([]SearchResult, error) {
+	return s.search(query, func(sess *Session) bool { return sess.Owner == owner }), nil
+}
+
+func (s *InMemorySessionStore) search(query string, keep func(*Session) bool) []SearchResult {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	var results []SearchResult
+	for _, summary := range s.sessionSummaries(keep) {
+		sess, ok := s.sessions.Load(summary.ID)
+		if !ok {
+			continue
+		}
+
+		texts := []string{sess.Title}
+		for _, item := range sess.GetAllMessages() {
+			if item.Message.Role == chat.MessageRoleUser || item.Message.Role == chat.MessageRoleAssistant {
+				texts = append(texts, item.Message.Content)
+			}
+		}
+		for _, text := range texts {
+			if matchesAllTerms(text, terms) {
+				results = append(results, SearchResult{Summary: summary, Snippet: makeSnippet(text, terms)})
+				break
+			}
+		}
+		if len(results) == maxSearchResults {
+			break
+		}
+	}
+	return results
+}
+
+// SearchSessions searches the titles and message contents of root sessions
+// using the sessions_fts full-text index. Results are ordered by relevance.
+func (s *SQLiteSessionStore) SearchSessions(ctx context.Context, query string) ([]SearchResult, error) {
+	return s.search(ctx, query, "")
+}
+
+// SearchSessionsByOwner is like SearchSessions but only searches the sessions owned by owner.
+func (s *SQLiteSessionStore) SearchSessionsByOwner(ctx context.Context, owner, query string) ([]SearchResult, error) {
+	return s.search(ctx, query, "AND COALESCE(s.owner, '') = ?", owner)
+}
+
+func (s *SQLiteSessionStore) search(ctx context.Context, query, filter string, args ...any) ([]SearchResult, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	// The matches are materialized so that snippet() runs in a plain
+	// full-text query. MIN(rank) then picks, for each session, the snippet of
+	// its best matching row.
+	rows, err := s.db.QueryContext(ctx,
+		`WITH matches AS MATERIALIZED (
+		   SELECT session_id, snippet(sessions_fts, 1, ?, ?, '…', 16) AS snippet, rank
+		   FROM sessions_fts WHERE sessions_fts MATCH ?
+		 )
+		 SELECT s.id, s.title, s.created_at, s.starred, s.branch_parent_session_id,
+		        (SELECT COUNT(*) FROM session_items si WHERE si.session_id = s.id AND si.item_type = 'message'),
+		        m.snippet
+		 FROM (SELECT session_id, snippet, MIN(rank) AS rank FROM matches GROUP BY session_id) m
+		 JOIN sessions s ON s.id = m.session_id
+		 WHERE (s.parent_id IS NULL OR s.parent_id = '') `+filter+`
+		 ORDER BY m.rank
+		 LIMIT `+strconv.Itoa(maxSearchResults),
+		append([]any{SnippetMatchStart, SnippetMatchEnd, ftsQuery(terms)}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var id, title, createdAtStr, starredStr, snippet string
+		var branchParentID sql.NullString
+		var numMessages int
+		if err := rows.Scan(&id, &title, &createdAtStr, &starredStr, &branchParentID, &numMessages, &snippet); err != nil {
+			return nil, err
+		}
+		createdAt, err := time.Parse(time.RFC3339, createdAtStr)
+		if err != nil {
+			return nil, err
+		}
+		starred, err := strconv.ParseBool(starredStr)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, SearchResult{
+			Summary: Summary{
+				ID:                    id,
+				Title:                 title,
+				CreatedAt:             createdAt,
+				Starred:               starred,
+				BranchParentSessionID: branchParentID.String,
+				NumMessages:           numMessages,
+			},
+			Snippet: snippet,
+		})
+	}
+
+	return results, rows.Err()
+}
+
+// SearchSessions searches the titles and message contents of root sessions.
+// Terms are matched case-insensitively as substrings.
+func (s *PostgresSessionStore) SearchSessions(ctx context.Context, query string) ([]SearchResult, error) {
+	return s.search(ctx, query, "")
+}
+
+// SearchSessionsByOwner is like SearchSessions but only searches the sessions owned by owner.
+func (s *PostgresSessionStore) SearchSessionsByOwner(ctx context.Context, owner, query string) ([]SearchResult, error) {
+	return s.search(ctx, query, "AND s.owner = $1", owner)
+}
+
+func (s *PostgresSessionStore) search(ctx context.Context, query, filter string, args ...any) ([]SearchResult, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	// Each term must appear in the same text: the title or a single message.
+	var conditions []string
+	for _, term := range terms {
+		args = append(args, "%"+escapeLike(term)+"%")
+		conditions = append(conditions, "t.body ILIKE $"+strconv.Itoa(len(args)))
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT DISTINCT ON (s.created_at, s.id) s.id, s.title, s.created_at, s.starred, s.branch_parent_session_id,
+		        (SELECT COUNT(*) FROM session_items si WHERE si.session_id = s.id AND si.item_type = 'message'),
+		        t.body
+		 FROM sessions s
+		 JOIN (
+		   SELECT id AS session_id, title AS body, 0 AS position FROM sessions
+		   UNION ALL
+		   SELECT session_id, COALESCE(summary_text, message_json::json->>'content'), position + 1 FROM session_items
+		   WHERE item_type = 'summary' OR (item_type = 'message' AND message_json::json->>'role' IN ('user', 'assistant'))
+		 ) t ON t.session_id = s.id
+		 WHERE s.parent_id IS NULL `+filter+` AND `+strings.Join(conditions, " AND ")+`
+		 ORDER BY s.created_at DESC, s.id, t.position
+		 LIMIT `+strconv.Itoa(maxSearchResults), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var result SearchResult
+		var branchParentID sql.NullString
+		var body string
+		if err := rows.Scan(&result.ID, &result.Title, &result.CreatedAt, &result.Starred, &branchParentID, &result.NumMessages, &body); err != nil {
+			return nil, err
+		}
+		result.BranchParentSessionID = branchParentID.String
+		result.Snippet = makeSnippet(body, terms)
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// escapeLike escapes the LIKE wildcards in s.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,125 @@
+package session
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/chat"
+)
+
+func TestSearchSessions(t *testing.T) {
+	t.Parallel()
+
+	sqliteStore, err := NewSQLiteSessionStore(filepath.Join(t.TempDir(), "test_search.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { sqliteStore.(*SQLiteSessionStore).Close() })
+
+	for name, store := range map[string]Store{
+		"sqlite":    sqliteStore,
+		"in-memory": NewInMemorySessionStore(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			now := time.Now().UTC().Truncate(time.Second)
+			require.NoError(t, store.AddSession(t.Context(), &Session{
+				ID:        "migration",
+				Title:     "Database work",
+				CreatedAt: now.Add(-time.Hour),
+				Messages: []Item{
+					NewMessageItem(UserMessage("The migration fails on startup")),
+					NewMessageItem(&Message{Message: chat.Message{Role: chat.MessageRoleTool, Content: "unrelated tool output about owls"}}),
+				},
+			}))
+			require.NoError(t, store.AddSession(t.Context(), &Session{
+				ID:        "owls",
+				Title:     "Owls",
+				Owner:     "alice",
+				CreatedAt: now,
+			}))
+
+			_, err := store.AddMessage(t.Context(), "owls", &Message{
+				AgentName: "root",
+				Message:   chat.Message{Role: chat.MessageRoleAssistant, Content: "Fixed the migration bug in the owl tracker"},
+			})
+			require.NoError(t, err)
+
+			results, err := store.SearchSessions(t.Context(), "migration bug")
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			assert.Equal(t, "owls", results[0].ID)
+			assert.Equal(t, "Owls", results[0].Title)
+			assert.Contains(t, results[0].Snippet, SnippetMatchStart+"migration"+SnippetMatchEnd)
+
+			// The last term is matched as a prefix while typing.
+			results, err = store.SearchSessions(t.Context(), "migrat")
+			require.NoError(t, err)
+			assert.Len(t, results, 2)
+
+			// Titles are searched too, tool results are not.
+			results, err = store.SearchSessions(t.Context(), "database")
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			assert.Equal(t, "migration", results[0].ID)
+			results, err = store.SearchSessions(t.Context(), "unrelated")
+			require.NoError(t, err)
+			assert.Empty(t, results)
+
+			results, err = store.SearchSessionsByOwner(t.Context(), "alice", "migration")
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			assert.Equal(t, "owls", results[0].ID)
+
+			results, err = store.SearchSessions(t.Context(), `  "unbalanced  `)
+			require.NoError(t, err)
+			assert.Empty(t, results)
+		})
+	}
+}
+
+func TestSearchSessions_FollowsTitleChanges(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewSQLiteSessionStore(filepath.Join(t.TempDir(), "test_search_title.db"))
+	require.NoError(t, err)
+	defer store.(*SQLiteSessionStore).Close()
+
+	sess := &Session{ID: "s", Title: "Before", CreatedAt: time.Now()}
+	require.NoError(t, store.AddSession(t.Context(), sess))
+	require.NoError(t, store.UpdateSessionTitle(t.Context(), "s", "After"))
+
+	results, err := store.SearchSessions(t.Context(), "before")
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	results, err = store.SearchSessions(t.Context(), "after")
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	require.NoError(t, store.DeleteSession(t.Context(), "s"))
+	results, err = store.SearchSessions(t.Context(), "after")
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestMakeSnippet(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Fixed the <mark>Migration</mark> bug", makeSnippet("Fixed  the\nMigration bug", []string{"migration"}))
+
+	long := "This is a very long message that keeps going and going before it finally mentions the migration and then goes on"
+	snippet := makeSnippet(long, []string{"migration"})
+	assert.True(t, len(snippet) < len(long)+len(SnippetMatchStart+SnippetMatchEnd))
+	assert.Contains(t, snippet, "…")
+	assert.Contains(t, snippet, "<mark>migration</mark>")
+}
+
+func TestFTSQuery(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `"fix" "migra"*`, ftsQuery([]string{"fix", "migra"}))
+	assert.Equal(t, `"a""b"*`, ftsQuery([]string{`a"b`}))
+}
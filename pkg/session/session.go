@@ -12,13 +12,15 @@ import (
 
 	"github.com/docker/docker-agent/pkg/agent"
 	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/tokenizer"
 	"github.com/docker/docker-agent/pkg/tools"
 )
 
 const (
 	// MaxToolCallTokens is the maximum number of tokens to keep from tool call
-	// arguments and results. Older tool calls beyond this budget will have their
-	// content replaced with a placeholder. Tokens are approximated as len/4.
+	// results. Older tool results beyond this budget will have their content
+	// replaced with a placeholder. Tokens are counted with the tokenizer passed
+	// to GetMessages, or approximated as len/4 by default.
 	MaxToolCallTokens = 40000
 
 	// toolContentPlaceholder is the text used to replace truncated tool content
@@ -96,6 +98,10 @@ type Session struct {
 	// Starred indicates if this session has been starred by the user
 	Starred bool `json:"starred"`
 
+	// Owner identifies the caller that created the session when the API
+	// server runs with authentication. Empty for sessions without an owner.
+	Owner string `json:"owner,omitempty"`
+
 	InputTokens  int64   `json:"input_tokens"`
 	OutputTokens int64   `json:"output_tokens"`
 	Cost         float64 `json:"cost"`
@@ -225,6 +231,34 @@ type EvalCriteria struct {
 	WorkingDir string   `json:"working_dir,omitempty"` // Subdirectory under evals/working_dirs/
 	Size       string   `json:"size,omitempty"`        // Expected response size: S, M, L, XL
 	Setup      string   `json:"setup,omitempty"`       // Optional sh script to run in the container before docker agent run --exec
+
+	// Deterministic assertions, checked without a judge model.
+	Contains        []string       `json:"contains,omitempty"`          // Strings the final response must contain
+	NotContains     []string       `json:"not_contains,omitempty"`      // Strings the final response must not contain
+	Matches         []string       `json:"matches,omitempty"`           // Regular expressions the final response must match
+	ResponseSchema  map[string]any `json:"response_schema,omitempty"`   // JSON schema the final response, parsed as JSON, must validate against
+	Files           []EvalFile     `json:"files,omitempty"`             // Expected files in the working directory after the run
+	ToolCalls       []EvalToolCall `json:"tool_calls,omitempty"`        // Tool calls that must be made, in this order
+	MaxCost         float64        `json:"max_cost,omitempty"`          // Maximum cost of the run, in dollars
+	MaxInputTokens  int64          `json:"max_input_tokens,omitempty"`  // Maximum number of input tokens of the run, summed over the model calls
+	MaxOutputTokens int64          `json:"max_output_tokens,omitempty"` // Maximum number of output tokens of the run
+	MaxTotalTokens  int64          `json:"max_total_tokens,omitempty"`  // Maximum number of input and output tokens of the run
+}
+
+// EvalFile is the expected state of a file in the working directory after an
+// evaluation run.
+type EvalFile struct {
+	Path     string   `json:"path"`               // Path relative to the working directory
+	Absent   bool     `json:"absent,omitempty"`   // The file must not exist
+	Content  *string  `json:"content,omitempty"`  // Exact expected content
+	Contains []string `json:"contains,omitempty"` // Strings the content must contain
+	Matches  []string `json:"matches,omitempty"`  // Regular expressions the content must match
+}
+
+// EvalToolCall is a tool call expected during an evaluation run.
+type EvalToolCall struct {
+	Name      string            `json:"name"`                // Tool name
+	Arguments map[string]string `json:"arguments,omitempty"` // Regular expressions the arguments, by name, must match
 }
 
 // deepCopyMessage returns a deep copy of a session Message.
@@ -290,6 +324,18 @@ func (s *Session) AddSubSession(subSession *Session) {
 	s.mu.Unlock()
 }
 
+// AddCost adds the cost of an operation that doesn't produce a message, such as
+// answering a sampling request of an MCP server, to the last item.
+func (s *Session) AddCost(cost float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.Messages) == 0 {
+		s.Messages = append(s.Messages, Item{Cost: cost})
+		return
+	}
+	s.Messages[len(s.Messages)-1].Cost += cost
+}
+
 // Duration calculates the duration of the session from message timestamps.
 func (s *Session) Duration() time.Duration {
 	messages := s.GetAllMessages()
@@ -466,6 +512,13 @@ func WithPermissions(perms *PermissionsConfig) Opt {
 	}
 }
 
+// WithOwner sets the owner of the session.
+func WithOwner(owner string) Opt {
+	return func(s *Session) {
+		s.Owner = owner
+	}
+}
+
 // WithAgentName pins this session to a specific agent. When set, RunStream
 // resolves the agent from the session rather than the shared runtime state,
 // which is required for concurrent background agent tasks.
@@ -723,9 +776,14 @@ func buildSessionSummaryMessages(items []Item) ([]chat.Message, int) {
 	return messages, lastSummaryIndex
 }
 
-func (s *Session) GetMessages(a *agent.Agent) []chat.Message {
+func (s *Session) GetMessages(a *agent.Agent, opts ...MessagesOpt) []chat.Message {
 	slog.Debug("Getting messages for agent", "agent", a.Name(), "session_id", s.ID)
 
+	options := messagesOptions{tokenizer: tokenizer.Heuristic}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	// Build invariant system messages (cacheable across sessions/users/projects)
 	invariantMessages := buildInvariantSystemMessages(a)
 	markLastMessageAsCacheControl(invariantMessages)
@@ -770,7 +828,11 @@ func (s *Session) GetMessages(a *agent.Agent) []chat.Message {
 		messages = trimMessages(messages, maxItems)
 	}
 
-	messages = truncateOldToolContent(messages, MaxToolCallTokens)
+	messages = truncateOldToolContent(messages, MaxToolCallTokens, options.tokenizer)
+
+	if options.budget > 0 {
+		messages = fitToBudget(messages, options.tokenizer, options.budget)
+	}
 
 	systemCount := 0
 	conversationCount := 0
@@ -871,7 +933,7 @@ func trimMessages(messages []chat.Message, maxItems int) []chat.Message {
 // messages that exceed the token budget. It processes messages from newest to
 // oldest, keeping recent tool content intact while truncating older content
 // once the budget is exhausted.
-func truncateOldToolContent(messages []chat.Message, maxTokens int) []chat.Message {
+func truncateOldToolContent(messages []chat.Message, maxTokens int, tok tokenizer.Tokenizer) []chat.Message {
 	if len(messages) == 0 || maxTokens <= 0 {
 		return messages
 	}
@@ -885,7 +947,7 @@ func truncateOldToolContent(messages []chat.Message, maxTokens int) []chat.Messa
 		msg := &result[i]
 
 		if msg.Role == chat.MessageRoleTool {
-			tokens := len(msg.Content) / 4
+			tokens := tok.Count(msg.Content)
 			if tokenBudget >= tokens {
 				tokenBudget -= tokens
 			} else {
@@ -85,6 +85,26 @@ type Store interface {
 	UpdateSession(ctx context.Context, session *Session) error // Updates metadata only (not messages/items)
 	SetSessionStarred(ctx context.Context, id string, starred bool) error
 
+	// === Owner-scoped operations ===
+
+	// GetSessionsByOwner is like GetSessions but only returns the root
+	// sessions owned by owner.
+	GetSessionsByOwner(ctx context.Context, owner string) ([]*Session, error)
+
+	// GetSessionSummariesByOwner is like GetSessionSummaries but only
+	// returns the root sessions owned by owner.
+	GetSessionSummariesByOwner(ctx context.Context, owner string) ([]Summary, error)
+
+	// === Search ===
+
+	// SearchSessions returns the root sessions whose title or messages
+	// contain every term of query, the most relevant first.
+	SearchSessions(ctx context.Context, query string) ([]SearchResult, error)
+
+	// SearchSessionsByOwner is like SearchSessions but only searches the
+	// sessions owned by owner.
+	SearchSessionsByOwner(ctx context.Context, owner, query string) ([]SearchResult, error)
+
 	// === Granular item operations ===
 
 	// AddMessage adds a message to a session at the next position.
@@ -153,10 +173,34 @@ func (s *InMemorySessionStore) GetSessions(_ context.Context) ([]*Session, error
 	return sessions, nil
 }
 
+func (s *InMemorySessionStore) GetSessionsByOwner(_ context.Context, owner string) ([]*Session, error) {
+	var sessions []*Session
+	s.sessions.Range(func(_ string, value *Session) bool {
+		if value.ParentID == "" && value.Owner == owner {
+			sessions = append(sessions, value)
+		}
+		return true
+	})
+	slices.SortFunc(sessions, func(a, b *Session) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return sessions, nil
+}
+
 func (s *InMemorySessionStore) GetSessionSummaries(_ context.Context) ([]Summary, error) {
+	return s.sessionSummaries(func(*Session) bool { return true }), nil
+}
+
+func (s *InMemorySessionStore) GetSessionSummariesByOwner(_ context.Context, owner string) ([]Summary, error) {
+	return s.sessionSummaries(func(sess *Session) bool { return sess.Owner == owner }), nil
+}
+
+// sessionSummaries returns the summaries of the root sessions matching keep,
+// newest first.
+func (s *InMemorySessionStore) sessionSummaries(keep func(*Session) bool) []Summary {
 	summaries := make([]Summary, 0, s.sessions.Length())
 	s.sessions.Range(func(_ string, value *Session) bool {
-		if value.ParentID != "" {
+		if value.ParentID != "" || !keep(value) {
 			return true
 		}
 		summaries = append(summaries, Summary{
@@ -172,7 +216,7 @@ func (s *InMemorySessionStore) GetSessionSummaries(_ context.Context) ([]Summary
 	slices.SortFunc(summaries, func(a, b Summary) int {
 		return b.CreatedAt.Compare(a.CreatedAt)
 	})
-	return summaries, nil
+	return summaries
 }
 
 func (s *InMemorySessionStore) DeleteSession(_ context.Context, id string) error {
@@ -210,6 +254,7 @@ func (s *InMemorySessionStore) UpdateSession(_ context.Context, session *Session
 		SendUserMessage:       session.SendUserMessage,
 		MaxIterations:         session.MaxIterations,
 		Starred:               session.Starred,
+		Owner:                 session.Owner,
 		InputTokens:           session.InputTokens,
 		OutputTokens:          session.OutputTokens,
 		Cost:                  session.Cost,
@@ -228,6 +273,8 @@ func (s *InMemorySessionStore) UpdateSession(_ context.Context, session *Session
 		newSession.Messages = make([]Item, len(existing.Messages))
 		copy(newSession.Messages, existing.Messages)
 		existing.mu.RUnlock()
+		// Like SQLite, the owner is only set when the session is created.
+		newSession.Owner = existing.Owner
 	}
 
 	s.sessions.Store(session.ID, newSession)
@@ -552,12 +599,13 @@ func (s *SQLiteSessionStore) AddSession(ctx context.Context, session *Session) e
 			id, tools_approved, input_tokens, output_tokens, title, cost, send_user_message,
 			max_iterations, working_dir, created_at, permissions, agent_model_overrides,
 			custom_models_used, thinking, parent_id, branch_parent_session_id,
-			branch_parent_position, branch_created_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			branch_parent_position, branch_created_at, owner
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		session.ID, session.ToolsApproved, session.InputTokens, session.OutputTokens, session.Title,
 		session.Cost, session.SendUserMessage, session.MaxIterations, session.WorkingDir,
 		session.CreatedAt.Format(time.RFC3339), permissionsJSON, agentModelOverridesJSON,
-		customModelsUsedJSON, session.Thinking, parentID, branchParentID, branchParentPosition, branchCreatedAt)
+		customModelsUsedJSON, session.Thinking, parentID, branchParentID, branchParentPosition, branchCreatedAt,
+		session.Owner)
 	if err != nil {
 		return err
 	}
@@ -587,8 +635,9 @@ func scanSession(scanner interface {
 	var branchParentPosition sql.NullInt64
 	var branchCreatedAt sql.NullString
 	var splitDiffView sql.NullBool // column kept for backward compat, value ignored
+	var owner sql.NullString
 
-	err := scanner.Scan(&sessionID, &toolsApprovedStr, &inputTokensStr, &outputTokensStr, &titleStr, &costStr, &sendUserMessageStr, &maxIterationsStr, &workingDir, &createdAtStr, &starredStr, &permissionsJSON, &agentModelOverridesJSON, &customModelsUsedJSON, &thinkingStr, &parentID, &branchParentID, &branchParentPosition, &branchCreatedAt, &splitDiffView)
+	err := scanner.Scan(&sessionID, &toolsApprovedStr, &inputTokensStr, &outputTokensStr, &titleStr, &costStr, &sendUserMessageStr, &maxIterationsStr, &workingDir, &createdAtStr, &starredStr, &permissionsJSON, &agentModelOverridesJSON, &customModelsUsedJSON, &thinkingStr, &parentID, &branchParentID, &branchParentPosition, &branchCreatedAt, &splitDiffView, &owner)
 	if err != nil {
 		return nil, err
 	}
@@ -692,6 +741,7 @@ func scanSession(scanner interface {
 		CreatedAt:             createdAt,
 		WorkingDir:            workingDir.String,
 		Starred:               starred,
+		Owner:                 owner.String,
 		Permissions:           permissions,
 		AgentModelOverrides:   agentModelOverrides,
 		CustomModelsUsed:      customModelsUsed,
@@ -709,7 +759,7 @@ func (s *SQLiteSessionStore) GetSession(ctx context.Context, id string) (*Sessio
 	}
 
 	row := s.db.QueryRowContext(ctx,
-		"SELECT id, tools_approved, input_tokens, output_tokens, title, cost, send_user_message, max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides, custom_models_used, thinking, parent_id, branch_parent_session_id, branch_parent_position, branch_created_at, split_diff_view FROM sessions WHERE id = ?", id)
+		"SELECT id, tools_approved, input_tokens, output_tokens, title, cost, send_user_message, max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides, custom_models_used, thinking, parent_id, branch_parent_session_id, branch_parent_position, branch_created_at, split_diff_view, owner FROM sessions WHERE id = ?", id)
 
 	sess, err := scanSession(row)
 	if err != nil {
@@ -825,7 +875,7 @@ func (s *SQLiteSessionStore) loadSessionItemsWith(ctx context.Context, q querier
 // loadSessionWith loads a session using the provided querier.
 func (s *SQLiteSessionStore) loadSessionWith(ctx context.Context, q querier, id string) (*Session, error) {
 	row := q.QueryRowContext(ctx,
-		"SELECT id, tools_approved, input_tokens, output_tokens, title, cost, send_user_message, max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides, custom_models_used, thinking, parent_id, branch_parent_session_id, branch_parent_position, branch_created_at, split_diff_view FROM sessions WHERE id = ?", id)
+		"SELECT id, tools_approved, input_tokens, output_tokens, title, cost, send_user_message, max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides, custom_models_used, thinking, parent_id, branch_parent_session_id, branch_parent_position, branch_created_at, split_diff_view, owner FROM sessions WHERE id = ?", id)
 
 	sess, err := scanSession(row)
 	if err != nil {
@@ -879,8 +929,18 @@ func (s *SQLiteSessionStore) loadMessagesFromLegacyColumn(ctx context.Context, s
 
 // GetSessions retrieves all root sessions (excludes sub-sessions)
 func (s *SQLiteSessionStore) GetSessions(ctx context.Context) ([]*Session, error) {
+	return s.getSessions(ctx, "")
+}
+
+// GetSessionsByOwner retrieves the root sessions owned by owner.
+func (s *SQLiteSessionStore) GetSessionsByOwner(ctx context.Context, owner string) ([]*Session, error) {
+	return s.getSessions(ctx, "AND COALESCE(owner, '') = ?", owner)
+}
+
+// getSessions retrieves root sessions matching an optional extra filter.
+func (s *SQLiteSessionStore) getSessions(ctx context.Context, filter string, args ...any) ([]*Session, error) {
 	rows, err := s.db.QueryContext(ctx,
-		"SELECT id, tools_approved, input_tokens, output_tokens, title, cost, send_user_message, max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides, custom_models_used, thinking, parent_id, branch_parent_session_id, branch_parent_position, branch_created_at, split_diff_view FROM sessions WHERE parent_id IS NULL OR parent_id = '' ORDER BY created_at DESC")
+		"SELECT id, tools_approved, input_tokens, output_tokens, title, cost, send_user_message, max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides, custom_models_used, thinking, parent_id, branch_parent_session_id, branch_parent_position, branch_created_at, split_diff_view, owner FROM sessions WHERE (parent_id IS NULL OR parent_id = '') "+filter+" ORDER BY created_at DESC", args...)
 	if err != nil {
 		return nil, err
 	}
@@ -914,12 +974,22 @@ func (s *SQLiteSessionStore) GetSessions(ctx context.Context) ([]*Session, error
 // GetSessionSummaries retrieves lightweight session metadata for listing (excludes sub-sessions).
 // This is much faster than GetSessions as it doesn't load message content.
 func (s *SQLiteSessionStore) GetSessionSummaries(ctx context.Context) ([]Summary, error) {
+	return s.getSessionSummaries(ctx, "")
+}
+
+// GetSessionSummariesByOwner retrieves lightweight metadata for the root sessions owned by owner.
+func (s *SQLiteSessionStore) GetSessionSummariesByOwner(ctx context.Context, owner string) ([]Summary, error) {
+	return s.getSessionSummaries(ctx, "AND COALESCE(s.owner, '') = ?", owner)
+}
+
+// getSessionSummaries retrieves root session summaries matching an optional extra filter.
+func (s *SQLiteSessionStore) getSessionSummaries(ctx context.Context, filter string, args ...any) ([]Summary, error) {
 	rows, err := s.db.QueryContext(ctx,
 		`SELECT s.id, s.title, s.created_at, s.starred, s.branch_parent_session_id,
 		        (SELECT COUNT(*) FROM session_items si WHERE si.session_id = s.id AND si.item_type = 'message')
 		 FROM sessions s
-		 WHERE s.parent_id IS NULL OR s.parent_id = ''
-		 ORDER BY s.created_at DESC`)
+		 WHERE (s.parent_id IS NULL OR s.parent_id = '') `+filter+`
+		 ORDER BY s.created_at DESC`, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -1043,15 +1113,16 @@ func (s *SQLiteSessionStore) UpdateSession(ctx context.Context, session *Session
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	// Use INSERT OR REPLACE for upsert behavior - creates if not exists, updates if exists
+	// Use INSERT OR REPLACE for upsert behavior - creates if not exists, updates if exists.
+	// The owner is only set when the session is created and never changes.
 	_, err = tx.ExecContext(ctx,
 		`INSERT INTO sessions (
 			id, tools_approved, input_tokens, output_tokens, title, cost, send_user_message,
 			max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides,
 			custom_models_used, thinking, parent_id, branch_parent_session_id,
-			branch_parent_position, branch_created_at
+			branch_parent_position, branch_created_at, owner
 		)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		 ON CONFLICT(id) DO UPDATE SET
 		   title = excluded.title,
 		   tools_approved = excluded.tools_approved,
@@ -1073,7 +1144,8 @@ func (s *SQLiteSessionStore) UpdateSession(ctx context.Context, session *Session
 		session.ID, session.ToolsApproved, session.InputTokens, session.OutputTokens,
 		session.Title, session.Cost, session.SendUserMessage, session.MaxIterations, session.WorkingDir,
 		session.CreatedAt.Format(time.RFC3339), session.Starred, permissionsJSON, agentModelOverridesJSON,
-		customModelsUsedJSON, session.Thinking, parentID, branchParentID, branchParentPosition, branchCreatedAt)
+		customModelsUsedJSON, session.Thinking, parentID, branchParentID, branchParentPosition, branchCreatedAt,
+		session.Owner)
 	if err != nil {
 		return err
 	}
@@ -1283,14 +1355,14 @@ func (s *SQLiteSessionStore) addSessionTx(ctx context.Context, tx *sql.Tx, sessi
 			id, tools_approved, input_tokens, output_tokens, title, cost, send_user_message,
 			max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides,
 			custom_models_used, thinking, parent_id, branch_parent_session_id,
-			branch_parent_position, branch_created_at
+			branch_parent_position, branch_created_at, owner
 		)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		session.ID, session.ToolsApproved, session.InputTokens, session.OutputTokens,
 		session.Title, session.Cost, session.SendUserMessage, session.MaxIterations,
 		session.WorkingDir, session.CreatedAt.Format(time.RFC3339), session.Starred,
 		permissionsJSON, agentModelOverridesJSON, customModelsUsedJSON, session.Thinking,
-		parentID, branchParentID, branchParentPosition, branchCreatedAt)
+		parentID, branchParentID, branchParentPosition, branchCreatedAt, session.Owner)
 	return err
 }
 
@@ -223,6 +223,56 @@ func TestGetSessionSummaries(t *testing.T) {
 	assert.Equal(t, 1, summaries[1].NumMessages)
 }
 
+func TestGetSessionsByOwner(t *testing.T) {
+	t.Parallel()
+
+	sqliteStore, err := NewSQLiteSessionStore(filepath.Join(t.TempDir(), "test_owner.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { sqliteStore.(*SQLiteSessionStore).Close() })
+
+	for name, store := range map[string]Store{
+		"sqlite":    sqliteStore,
+		"in-memory": NewInMemorySessionStore(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			now := time.Now().UTC().Truncate(time.Second)
+			require.NoError(t, store.AddSession(t.Context(), &Session{ID: "alice-1", Owner: "alice", CreatedAt: now.Add(-time.Hour)}))
+			require.NoError(t, store.AddSession(t.Context(), &Session{ID: "alice-2", Owner: "alice", CreatedAt: now}))
+			require.NoError(t, store.AddSession(t.Context(), &Session{ID: "bob-1", Owner: "bob", CreatedAt: now}))
+			require.NoError(t, store.AddSession(t.Context(), &Session{ID: "nobody", CreatedAt: now}))
+
+			sessions, err := store.GetSessionsByOwner(t.Context(), "alice")
+			require.NoError(t, err)
+			require.Len(t, sessions, 2)
+			assert.Equal(t, "alice-2", sessions[0].ID)
+			assert.Equal(t, "alice", sessions[0].Owner)
+			assert.Equal(t, "alice-1", sessions[1].ID)
+
+			summaries, err := store.GetSessionSummariesByOwner(t.Context(), "bob")
+			require.NoError(t, err)
+			require.Len(t, summaries, 1)
+			assert.Equal(t, "bob-1", summaries[0].ID)
+
+			summaries, err = store.GetSessionSummariesByOwner(t.Context(), "")
+			require.NoError(t, err)
+			require.Len(t, summaries, 1)
+			assert.Equal(t, "nobody", summaries[0].ID)
+
+			// The owner is never changed by updates.
+			sess, err := store.GetSession(t.Context(), "bob-1")
+			require.NoError(t, err)
+			assert.Equal(t, "bob", sess.Owner)
+			sess.Title = "renamed"
+			require.NoError(t, store.UpdateSession(t.Context(), sess))
+			sess, err = store.GetSession(t.Context(), "bob-1")
+			require.NoError(t, err)
+			assert.Equal(t, "bob", sess.Owner)
+		})
+	}
+}
+
 func TestBranchSessionCopiesPrefix(t *testing.T) {
 	tempDB := filepath.Join(t.TempDir(), "test_branch_prefix.db")
 
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/tokenizer"
 	"github.com/docker/docker-agent/pkg/tools"
 )
 
@@ -24,7 +25,7 @@ func TestTruncateOldToolContent(t *testing.T) {
 			{Role: chat.MessageRoleTool, ToolCallID: "1", Content: "result"},
 		}
 
-		result := truncateOldToolContent(messages, 1000)
+		result := truncateOldToolContent(messages, 1000, tokenizer.Heuristic)
 
 		assert.JSONEq(t, `{"key":"value"}`, result[1].ToolCalls[0].Function.Arguments)
 		assert.Equal(t, "result", result[2].Content)
@@ -56,7 +57,7 @@ func TestTruncateOldToolContent(t *testing.T) {
 		}
 
 		// Budget of 60 tokens: new result (50 tokens) fits, old result gets truncated
-		result := truncateOldToolContent(messages, 60)
+		result := truncateOldToolContent(messages, 60, tokenizer.Heuristic)
 
 		// New result should be preserved, old result should be truncated
 		assert.Equal(t, newResult, result[5].Content)
@@ -70,7 +71,7 @@ func TestTruncateOldToolContent(t *testing.T) {
 			{Role: chat.MessageRoleSystem, Content: strings.Repeat("z", 1000)},
 		}
 
-		result := truncateOldToolContent(messages, 10)
+		result := truncateOldToolContent(messages, 10, tokenizer.Heuristic)
 
 		assert.Equal(t, messages[0].Content, result[0].Content)
 		assert.Equal(t, messages[1].Content, result[1].Content)
@@ -88,7 +89,7 @@ func TestTruncateOldToolContent(t *testing.T) {
 			{Role: chat.MessageRoleTool, ToolCallID: "1", Content: "result"},
 		}
 
-		result := truncateOldToolContent(messages, 0)
+		result := truncateOldToolContent(messages, 0, tokenizer.Heuristic)
 
 		assert.Equal(t, messages, result)
 	})
@@ -104,7 +105,7 @@ func TestTruncateOldToolContent(t *testing.T) {
 			{Role: chat.MessageRoleTool, ToolCallID: "1", Content: "result"},
 		}
 
-		result := truncateOldToolContent(messages, -10)
+		result := truncateOldToolContent(messages, -10, tokenizer.Heuristic)
 
 		assert.Equal(t, messages, result)
 	})
@@ -121,7 +122,7 @@ func TestTruncateOldToolContent(t *testing.T) {
 			{Role: chat.MessageRoleTool, ToolCallID: "1", Content: originalContent},
 		}
 
-		result := truncateOldToolContent(messages, 10)
+		result := truncateOldToolContent(messages, 10, tokenizer.Heuristic)
 
 		// Result should have truncated tool content
 		assert.Equal(t, toolContentPlaceholder, result[1].Content)
@@ -131,10 +132,10 @@ func TestTruncateOldToolContent(t *testing.T) {
 	})
 
 	t.Run("handles empty messages slice", func(t *testing.T) {
-		result := truncateOldToolContent(nil, 1000)
+		result := truncateOldToolContent(nil, 1000, tokenizer.Heuristic)
 		assert.Nil(t, result)
 
-		result = truncateOldToolContent([]chat.Message{}, 1000)
+		result = truncateOldToolContent([]chat.Message{}, 1000, tokenizer.Heuristic)
 		require.NotNil(t, result)
 		assert.Empty(t, result)
 	})
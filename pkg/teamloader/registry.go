@@ -198,7 +198,7 @@ func createFilesystemTool(_ context.Context, toolset latest.Toolset, _ string, r
 	if toolset.IgnoreVCS != nil {
 		ignoreVCS = *toolset.IgnoreVCS
 	}
-	opts = append(opts, builtin.WithIgnoreVCS(ignoreVCS))
+	opts = append(opts, builtin.WithIgnoreVCS(ignoreVCS), builtin.WithMaxReadSize(toolset.MaxReadSize))
 
 	// Handle post-edit commands
 	if len(toolset.PostEdit) > 0 {
@@ -237,6 +237,23 @@ func createFetchTool(_ context.Context, toolset latest.Toolset, _ string, _ *con
 }
 
 func createMCPTool(ctx context.Context, toolset latest.Toolset, _ string, runConfig *config.RuntimeConfig, _ string) (tools.ToolSet, error) {
+	ts, err := newMCPToolset(ctx, toolset, runConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if toolset.ResourceTools {
+		if mcpToolset, ok := tools.As[*mcp.Toolset](ts); ok {
+			mcpToolset.SetResourceTools(true)
+		}
+	}
+
+	return ts, nil
+}
+
+// newMCPToolset creates the toolset of an MCP server from the catalog, a
+// command or a remote URL.
+func newMCPToolset(ctx context.Context, toolset latest.Toolset, runConfig *config.RuntimeConfig) (tools.ToolSet, error) {
 	envProvider := runConfig.EnvProvider()
 
 	switch {
@@ -23,6 +23,7 @@ import (
 	"github.com/docker/docker-agent/pkg/modelsdev"
 	"github.com/docker/docker-agent/pkg/permissions"
 	"github.com/docker/docker-agent/pkg/rag"
+	"github.com/docker/docker-agent/pkg/redact"
 	"github.com/docker/docker-agent/pkg/skills"
 	"github.com/docker/docker-agent/pkg/team"
 	"github.com/docker/docker-agent/pkg/tools"
@@ -169,10 +170,19 @@ func LoadWithConfig(ctx context.Context, agentSource config.Source, runConfig *c
 			agent.WithAddPromptFiles(promptFiles),
 			agent.WithMaxIterations(agentConfig.MaxIterations),
 			agent.WithNumHistoryItems(agentConfig.NumHistoryItems),
+			agent.WithMaxParallelToolCalls(agentConfig.MaxParallelToolCalls),
 			agent.WithCommands(expander.ExpandCommands(ctx, agentConfig.Commands)),
 			agent.WithHooks(agentConfig.Hooks),
 		}
 
+		if agentConfig.Redact.IsEnabled() {
+			redactor, err := newRedactor(agentConfig.Redact, runConfig)
+			if err != nil {
+				return nil, fmt.Errorf("agent '%s': %w", agentConfig.Name, err)
+			}
+			opts = append(opts, agent.WithRedactor(redactor))
+		}
+
 		models, thinkingConfigured, err := getModelsForAgent(ctx, cfg, &agentConfig, autoModel, runConfig)
 		if err != nil {
 			// Return auto model fallback errors and DMR not installed errors directly
@@ -259,7 +269,10 @@ func LoadWithConfig(ctx context.Context, agentSource config.Source, runConfig *c
 	}
 
 	// Create permissions checker from config
-	permChecker := permissions.NewChecker(cfg.Permissions)
+	permChecker := permissions.NewChecker(cfg.Permissions, permissions.WithWorkingDir(runConfig.WorkingDir))
+	if err := permChecker.Validate(); err != nil {
+		return nil, err
+	}
 
 	// Build agent default models map
 	agentDefaultModels := make(map[string]string)
@@ -595,6 +608,20 @@ func contextWithExternalDepth(ctx context.Context, depth int) context.Context {
 	return context.WithValue(ctx, externalDepthKey, depth)
 }
 
+// newRedactor creates the redactor of an agent's tool results. It redacts the
+// values of the environment variables resolved while loading and running the
+// agent.
+func newRedactor(cfg *latest.RedactConfig, runConfig *config.RuntimeConfig) (*redact.Redactor, error) {
+	opts := redact.Options{
+		Env: runConfig.ResolvedEnv,
+	}
+	if cfg != nil {
+		opts.Entropy = cfg.Entropy
+		opts.Allow = cfg.Allow
+	}
+	return redact.New(opts)
+}
+
 // createRAGToolsForAgent creates RAG tools for an agent, one for each referenced RAG source
 func createRAGToolsForAgent(agentConfig *latest.AgentConfig, allManagers map[string]*rag.Manager) []tools.ToolSet {
 	if len(agentConfig.RAG) == 0 {
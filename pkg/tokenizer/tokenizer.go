@@ -0,0 +1,280 @@
+// Package tokenizer counts the tokens of chat messages locally, before they
+// are sent to a model, so that the conversation can be fitted to the model's
+// context window.
+//
+// The encodings don't ship the vocabularies of the real tokenizers. Instead,
+// text is split with the same pre-tokenization rules as the encoding of each
+// model family, and every piece is then weighted by how that family's
+// vocabulary usually merges it. This is much closer to the real count than a
+// flat characters-per-token ratio, and errs on the side of overestimating.
+package tokenizer
+
+import (
+	"encoding/json"
+	"hash/maphash"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// Tokenizer counts the tokens of text for a model family.
+type Tokenizer interface {
+	// Name identifies the encoding, e.g. "o200k" or "heuristic".
+	Name() string
+	// Count returns the number of tokens of text.
+	Count(text string) int
+}
+
+const (
+	// messageOverhead is the number of tokens used to frame a message:
+	// role, separators and tool call IDs.
+	messageOverhead = 5
+
+	// imageTokens is the cost of an image part. Providers charge between a
+	// few hundred and ~1600 tokens depending on the size; the upper end is
+	// used since images are resized to at most 2000×2000 before sending.
+	imageTokens = 1600
+)
+
+var (
+	// O200k approximates the encoding of GPT-4o, GPT-4.1, GPT-5 and the o-series.
+	O200k Tokenizer = &pieceTokenizer{
+		name:          "o200k",
+		pattern:       bpePattern,
+		wordLen:       8,
+		bytesPerToken: 4.5,
+		runeTokens:    0.8,
+	}
+
+	// Cl100k approximates the encoding of GPT-4 and GPT-3.5.
+	Cl100k Tokenizer = &pieceTokenizer{
+		name:          "cl100k",
+		pattern:       bpePattern,
+		wordLen:       7,
+		bytesPerToken: 4,
+		runeTokens:    1,
+	}
+
+	// Claude approximates the encoding of Anthropic's Claude models, which
+	// use a smaller vocabulary and split words more often.
+	Claude Tokenizer = &pieceTokenizer{
+		name:          "claude",
+		pattern:       bpePattern,
+		wordLen:       6,
+		bytesPerToken: 3.5,
+		runeTokens:    1.2,
+	}
+
+	// Gemini approximates the SentencePiece encoding of Gemini and Gemma,
+	// which splits numbers into single digits.
+	Gemini Tokenizer = &pieceTokenizer{
+		name:          "gemini",
+		pattern:       sentencePiecePattern,
+		wordLen:       8,
+		bytesPerToken: 4,
+		runeTokens:    0.8,
+	}
+
+	// Heuristic counts one token every four bytes, for models whose encoding is unknown.
+	Heuristic Tokenizer = heuristic{}
+)
+
+// ForModel returns the tokenizer of a model, given its "provider/model" ID.
+// Unknown models use the Heuristic tokenizer.
+func ForModel(modelID string) Tokenizer {
+	providerName, model, found := strings.Cut(strings.ToLower(modelID), "/")
+	if !found {
+		model, providerName = providerName, ""
+	}
+
+	switch {
+	case strings.Contains(model, "claude"):
+		return Claude
+	case strings.Contains(model, "gemini"), strings.Contains(model, "gemma"):
+		return Gemini
+	case strings.Contains(model, "gpt-4o"), strings.Contains(model, "gpt-4.1"), strings.Contains(model, "gpt-5"),
+		strings.Contains(model, "gpt-oss"), isOSeries(model):
+		return O200k
+	case strings.Contains(model, "gpt-4"), strings.Contains(model, "gpt-3.5"):
+		return Cl100k
+	}
+
+	switch providerName {
+	case "openai", "azure":
+		return O200k
+	case "anthropic":
+		return Claude
+	case "google":
+		return Gemini
+	default:
+		return Heuristic
+	}
+}
+
+// isOSeries reports whether model is one of OpenAI's o1, o3, o4... reasoning models.
+func isOSeries(model string) bool {
+	return len(model) >= 2 && model[0] == 'o' && model[1] >= '1' && model[1] <= '9'
+}
+
+// CountMessage returns the number of tokens a message takes in a request.
+func CountMessage(tok Tokenizer, msg *chat.Message) int {
+	n := messageOverhead + tok.Count(msg.Content) + tok.Count(msg.ReasoningContent)
+	for _, part := range msg.MultiContent {
+		switch {
+		case part.ImageURL != nil:
+			n += imageTokens
+		default:
+			n += tok.Count(part.Text)
+		}
+	}
+	for _, toolCall := range msg.ToolCalls {
+		n += tok.Count(toolCall.Function.Name) + tok.Count(toolCall.Function.Arguments)
+	}
+	return n
+}
+
+// CountMessages returns the number of tokens a list of messages takes in a request.
+func CountMessages(tok Tokenizer, messages []chat.Message) int {
+	var n int
+	for i := range messages {
+		n += CountMessage(tok, &messages[i])
+	}
+	return n
+}
+
+// CountTools returns the number of tokens the definitions of tools take in a request.
+func CountTools(tok Tokenizer, toolDefs []tools.Tool) int {
+	var n int
+	for _, tool := range toolDefs {
+		n += messageOverhead + tok.Count(tool.Name) + tok.Count(tool.Description)
+		if tool.Parameters != nil {
+			if schema, err := json.Marshal(tool.Parameters); err == nil {
+				n += tok.Count(string(schema))
+			}
+		}
+	}
+	return n
+}
+
+// heuristic counts one token every four bytes.
+type heuristic struct{}
+
+func (heuristic) Name() string { return "heuristic" }
+
+func (heuristic) Count(text string) int {
+	return (len(text) + 3) / 4
+}
+
+var (
+	// bpePattern splits text like the pre-tokenizers of byte-level BPE
+	// encodings: contractions, words with an optional leading symbol,
+	// numbers of up to three digits, punctuation runs and whitespace.
+	bpePattern = regexp.MustCompile(`(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+`)
+
+	// sentencePiecePattern splits text like SentencePiece encodings: words
+	// with their leading space, single digits, punctuation runs and whitespace.
+	sentencePiecePattern = regexp.MustCompile(` ?\p{L}+|\p{N}| ?[^\s\p{L}\p{N}]+|\s+`)
+)
+
+// pieceTokenizer approximates an encoding by splitting text with its
+// pre-tokenization rules and weighting each piece.
+type pieceTokenizer struct {
+	name    string
+	pattern *regexp.Regexp
+	// wordLen is the length in bytes up to which an ASCII piece is usually
+	// a single token of the vocabulary.
+	wordLen int
+	// bytesPerToken is the average length of the tokens longer ASCII pieces
+	// are split into.
+	bytesPerToken float64
+	// runeTokens is the average number of tokens per character in pieces
+	// that aren't ASCII, such as CJK text or emojis.
+	runeTokens float64
+
+	// counts caches the counts of long texts. The same messages are counted
+	// again before every model call of a session, and splitting them with
+	// the pattern is much slower than hashing them.
+	counts countCache
+}
+
+func (t *pieceTokenizer) Name() string { return t.name }
+
+func (t *pieceTokenizer) Count(text string) int {
+	if len(text) < minCachedLen {
+		return t.count(text)
+	}
+
+	key := maphash.String(countSeed, text)
+	if n, ok := t.counts.get(key); ok {
+		return n
+	}
+	n := t.count(text)
+	t.counts.put(key, n)
+	return n
+}
+
+func (t *pieceTokenizer) count(text string) int {
+	var n int
+	for _, piece := range t.pattern.FindAllString(text, -1) {
+		n += t.countPiece(piece)
+	}
+	return n
+}
+
+func (t *pieceTokenizer) countPiece(piece string) int {
+	if !isASCII(piece) {
+		return max(1, int(math.Ceil(float64(utf8.RuneCountInString(piece))*t.runeTokens)))
+	}
+	if len(piece) <= t.wordLen {
+		return 1
+	}
+	return int(math.Ceil(float64(len(piece)) / t.bytesPerToken))
+}
+
+const (
+	// minCachedLen is the length from which the counts of texts are cached.
+	minCachedLen = 256
+	// maxCachedCounts bounds the number of counts cached by each tokenizer.
+	maxCachedCounts = 8192
+)
+
+var countSeed = maphash.MakeSeed()
+
+// countCache maps the hashes of texts to their number of tokens. It is
+// emptied when it reaches maxCachedCounts entries.
+type countCache struct {
+	mu     sync.Mutex
+	counts map[uint64]int
+}
+
+func (c *countCache) get(key uint64) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n, ok := c.counts[key]
+	return n, ok
+}
+
+func (c *countCache) put(key uint64, n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.counts == nil || len(c.counts) >= maxCachedCounts {
+		c.counts = make(map[uint64]int)
+	}
+	c.counts[key] = n
+}
+
+func isASCII(s string) bool {
+	for i := range len(s) {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
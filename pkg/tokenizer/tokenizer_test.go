@@ -0,0 +1,127 @@
+package tokenizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+func TestForModel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		modelID  string
+		expected string
+	}{
+		{"openai/gpt-4o", "o200k"},
+		{"openai/gpt-5-mini", "o200k"},
+		{"openai/o3-mini", "o200k"},
+		{"openai/gpt-4-turbo", "cl100k"},
+		{"openai/some-future-model", "o200k"},
+		{"anthropic/claude-sonnet-4-5", "claude"},
+		{"amazon-bedrock/global.anthropic.claude-sonnet-4-5-20250929-v1:0", "claude"},
+		{"google/gemini-2.5-flash", "gemini"},
+		{"dmr/ai/gemma3", "gemini"},
+		{"dmr/ai/qwen3", "heuristic"},
+		{"claude-haiku-4-5", "claude"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.modelID, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, ForModel(tt.modelID).Name())
+		})
+	}
+}
+
+func TestCount(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, O200k.Count(""))
+	assert.Equal(t, 0, Heuristic.Count(""))
+
+	// Short words and punctuation are single tokens.
+	assert.Equal(t, 4, O200k.Count("Hello, world!"))
+	assert.Equal(t, 4, Cl100k.Count("Hello, world!"))
+
+	// Long words are split, more often by Claude's smaller vocabulary.
+	assert.Equal(t, 5, O200k.Count("internationalization"))
+	assert.Equal(t, 6, Claude.Count("internationalization"))
+
+	// Numbers are split in groups of three digits, or single digits for Gemini.
+	assert.Equal(t, 3, O200k.Count("1234567"))
+	assert.Equal(t, 7, Gemini.Count("1234567"))
+
+	// Non-latin text is counted per character.
+	assert.Equal(t, 4, Cl100k.Count("日本語で"))
+
+	assert.Equal(t, 3, Heuristic.Count("Hello, world"))
+}
+
+func TestCountMessage(t *testing.T) {
+	t.Parallel()
+
+	msg := chat.Message{
+		Role:             chat.MessageRoleAssistant,
+		Content:          "result",
+		ReasoningContent: "thinking",
+		MultiContent: []chat.MessagePart{
+			{Type: chat.MessagePartTypeText, Text: "extra detail"},
+			{Type: chat.MessagePartTypeImageURL, ImageURL: &chat.MessageImageURL{URL: "data:image/png;base64,xyz"}},
+		},
+		ToolCalls: []tools.ToolCall{
+			{Function: tools.FunctionCall{Name: "cmd", Arguments: `{"x":"y"}`}},
+		},
+	}
+
+	// 5 overhead + 2 + 2 + 3 + 1600 image + 1 + 3
+	assert.Equal(t, 1616, CountMessage(Heuristic, &msg))
+	assert.Equal(t, 2*1616, CountMessages(Heuristic, []chat.Message{msg, msg}))
+}
+
+func TestCountTools(t *testing.T) {
+	t.Parallel()
+
+	withSchema := []tools.Tool{{
+		Name:        "read_file",
+		Description: "Read a file",
+		Parameters:  map[string]any{"type": "object"},
+	}}
+	withoutSchema := []tools.Tool{{Name: "read_file", Description: "Read a file"}}
+
+	assert.Greater(t, CountTools(O200k, withSchema), CountTools(O200k, withoutSchema))
+	assert.Equal(t, 5+3+3, CountTools(Heuristic, withoutSchema))
+}
+
+func TestCountLargeText(t *testing.T) {
+	t.Parallel()
+
+	text := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 1000)
+
+	// Every word and the period are single tokens: 10 per sentence, plus the trailing space.
+	assert.Equal(t, 10001, O200k.Count(text))
+	assert.Equal(t, len(text)/4, Heuristic.Count(text))
+}
+
+func TestCountIsCached(t *testing.T) {
+	t.Parallel()
+
+	tok := &pieceTokenizer{name: "test", pattern: bpePattern, wordLen: 8, bytesPerToken: 4, runeTokens: 1}
+	text := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 10)
+
+	n := tok.Count(text)
+	assert.Equal(t, 101, n)
+	assert.Len(t, tok.counts.counts, 1)
+
+	// Cached counts are returned without splitting the text again.
+	tok.pattern = nil
+	assert.Equal(t, n, tok.Count(text))
+
+	// Short texts aren't cached.
+	tok.pattern = bpePattern
+	assert.Equal(t, 2, tok.Count("short text"))
+	assert.Len(t, tok.counts.counts, 1)
+}
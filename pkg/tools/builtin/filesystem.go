@@ -13,8 +13,10 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/docker/docker-agent/pkg/chat"
+	"github.com/docker/docker-agent/pkg/checkpoint"
 	"github.com/docker/docker-agent/pkg/fsx"
 	"github.com/docker/docker-agent/pkg/tools"
 )
@@ -37,10 +39,15 @@ type PostEditConfig struct {
 	Cmd  string // Command to execute (with $path placeholder)
 }
 
+// defaultMaxReadSize is the size in bytes above which read_file and
+// read_multiple_files return a page of a file instead of its whole content.
+const defaultMaxReadSize = 100 * 1024
+
 type FilesystemTool struct {
 	workingDir       string
 	postEditCommands []PostEditConfig
 	ignoreVCS        bool
+	maxReadSize      int
 	repoMatcher      *fsx.VCSMatcher
 	repoMatcherOnce  sync.Once
 }
@@ -65,9 +72,20 @@ func WithIgnoreVCS(ignoreVCS bool) FileSystemOpt {
 	}
 }
 
+// WithMaxReadSize sets the size in bytes above which reads are paged.
+// A size of 0 keeps the default.
+func WithMaxReadSize(maxReadSize int) FileSystemOpt {
+	return func(t *FilesystemTool) {
+		if maxReadSize > 0 {
+			t.maxReadSize = maxReadSize
+		}
+	}
+}
+
 func NewFilesystemTool(workingDir string, opts ...FileSystemOpt) *FilesystemTool {
 	t := &FilesystemTool{
-		workingDir: workingDir,
+		workingDir:  workingDir,
+		maxReadSize: defaultMaxReadSize,
 	}
 
 	for _, opt := range opts {
@@ -82,6 +100,7 @@ func (t *FilesystemTool) Instructions() string {
 
 - Relative paths resolve from the working directory; absolute paths and ".." work as expected
 - Prefer read_multiple_files over sequential read_file calls
+- Large files are returned one page at a time; use offset and limit to read specific lines
 - Use search_files_content to locate code or text across files
 - Use exclude patterns in searches and max_depth in directory_tree to limit output`
 }
@@ -96,8 +115,11 @@ type WriteFileArgs struct {
 }
 
 type ReadMultipleFilesArgs struct {
-	Paths []string `json:"paths" jsonschema:"Array of file paths to read"`
-	JSON  bool     `json:"json,omitempty" jsonschema:"Whether to return the result as JSON"`
+	Paths  []string `json:"paths" jsonschema:"Array of file paths to read"`
+	JSON   bool     `json:"json,omitempty" jsonschema:"Whether to return the result as JSON"`
+	Offset int      `json:"offset,omitempty" jsonschema:"The 1-based line number to start reading each file from"`
+	Column int      `json:"column,omitempty" jsonschema:"The 1-based byte position to start reading the line at offset from, to continue a line that was cut"`
+	Limit  int      `json:"limit,omitempty" jsonschema:"The maximum number of lines to read from each file"`
 }
 
 type ReadMultipleFilesMeta struct {
@@ -141,14 +163,22 @@ type DirectoryTreeMeta struct {
 }
 
 type ReadFileArgs struct {
-	Path string `json:"path" jsonschema:"The file path to read"`
+	Path   string `json:"path" jsonschema:"The file path to read"`
+	Offset int    `json:"offset,omitempty" jsonschema:"The 1-based line number to start reading from"`
+	Column int    `json:"column,omitempty" jsonschema:"The 1-based byte position to start reading the line at offset from, to continue a line that was cut"`
+	Limit  int    `json:"limit,omitempty" jsonschema:"The maximum number of lines to read"`
 }
 
 type ReadFileMeta struct {
 	Path      string `json:"path"`
 	Content   string `json:"content"`
 	LineCount int    `json:"lineCount"`
-	Error     string `json:"error,omitempty"`
+	// TotalLines is the number of lines of the whole file, which is larger
+	// than LineCount when only a page of the file was returned.
+	TotalLines int    `json:"totalLines,omitempty"`
+	StartLine  int    `json:"startLine,omitempty"`
+	EndLine    int    `json:"endLine,omitempty"`
+	Error      string `json:"error,omitempty"`
 }
 
 type Edit struct {
@@ -226,7 +256,7 @@ func (t *FilesystemTool) Tools(context.Context) ([]tools.Tool, error) {
 		{
 			Name:         ToolNameReadFile,
 			Category:     "filesystem",
-			Description:  "Read the complete contents of a file from the file system. Supports text files and images (jpg, png, gif, webp). Images are returned as image content that you can view directly.",
+			Description:  "Read the contents of a file from the file system. Supports text files and images (jpg, png, gif, webp). Images are returned as image content that you can view directly. Large text files are returned one page at a time; use offset and limit to read a range of lines.",
 			Parameters:   tools.MustSchemaFor[ReadFileArgs](),
 			OutputSchema: tools.MustSchemaFor[string](),
 			Handler:      tools.NewHandler(t.handleReadFile),
@@ -238,7 +268,7 @@ func (t *FilesystemTool) Tools(context.Context) ([]tools.Tool, error) {
 		{
 			Name:        ToolNameReadMultipleFiles,
 			Category:    "filesystem",
-			Description: "Read the contents of multiple files simultaneously.",
+			Description: "Read the contents of multiple files simultaneously. Large files are returned one page at a time; use offset and limit to read a range of lines.",
 			Parameters:  tools.MustSchemaFor[ReadMultipleFilesArgs](),
 			// TODO(dga): depends on the json param
 			OutputSchema: tools.MustSchemaFor[string](),
@@ -452,6 +482,7 @@ func (t *FilesystemTool) handleEditFile(ctx context.Context, args EditFileArgs)
 		changes = append(changes, fmt.Sprintf("Edit %d: Replaced %d characters", i+1, len(edit.OldText)))
 	}
 
+	checkpoint.Snapshot(ctx, resolvedPath)
 	if err := os.WriteFile(resolvedPath, []byte(modifiedContent), 0o644); err != nil {
 		return tools.ResultError(fmt.Sprintf("Error writing file: %s", err)), nil
 	}
@@ -543,12 +574,151 @@ func (t *FilesystemTool) handleReadFile(_ context.Context, args ReadFileArgs) (*
 		}, nil
 	}
 
+	return t.ReadFileResult(string(content), args), nil
+}
+
+// ReadFileResult returns the result of read_file for a text file with the
+// given content: the page of the file selected by args, capped to the
+// maximum read size. It lets toolsets that read files by other means page
+// them as read_file does.
+func (t *FilesystemTool) ReadFileResult(content string, args ReadFileArgs) *tools.ToolCallResult {
+	page, err := t.readPage(content, args.Offset, args.Column, args.Limit)
+	if err != nil {
+		return &tools.ToolCallResult{
+			Output:  err.Error(),
+			IsError: true,
+			Meta: ReadFileMeta{
+				Error: err.Error(),
+			},
+		}
+	}
+
 	return &tools.ToolCallResult{
-		Output: string(content),
-		Meta: ReadFileMeta{
-			LineCount: strings.Count(string(content), "\n") + 1,
-		},
-	}, nil
+		Output: page.output(),
+		Meta:   page.meta(args.Path),
+	}
+}
+
+// filePage is the range of lines of a text file returned by a read.
+type filePage struct {
+	content string
+	// start and end are the 1-based first and last lines of the page,
+	// both 0 when the page is empty.
+	start, end int
+	total      int
+	size       int
+	// tooLarge is set when the page was cut to fit the maximum read size.
+	tooLarge bool
+	// cutLineSize is the size of the page's last line when it was cut
+	// because the rest of it doesn't fit in the maximum read size on its own,
+	// and nextColumn the 1-based byte position to continue reading it from.
+	cutLineSize int
+	nextColumn  int
+}
+
+// readPage selects up to limit lines of content, starting at line offset
+// and, within that line, at byte column. The page stops before it grows past
+// the maximum read size, cutting its only line when that line doesn't fit.
+func (t *FilesystemTool) readPage(content string, offset, column, limit int) (filePage, error) {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	page := filePage{total: len(lines), size: len(content)}
+	offset = max(offset, 1)
+	if offset > len(lines) {
+		if offset == 1 {
+			return page, nil
+		}
+		return page, fmt.Errorf("offset %d is beyond the end of the file (%d lines)", offset, len(lines))
+	}
+
+	column = max(column, 1)
+	if column > len(lines[offset-1]) {
+		return page, fmt.Errorf("column %d is beyond the end of line %d (%d bytes)", column, offset, len(lines[offset-1]))
+	}
+
+	end := len(lines)
+	if limit > 0 {
+		end = min(end, offset-1+limit)
+	}
+
+	var b strings.Builder
+	for i := offset - 1; i < end; i++ {
+		line, start := lines[i], 1
+		if i == offset-1 {
+			line, start = line[column-1:], column
+		}
+		if b.Len()+len(line) > t.maxReadSize {
+			page.tooLarge = true
+			if b.Len() == 0 {
+				// Cut a single line that doesn't fit on its own, on a
+				// character boundary.
+				n := t.maxReadSize
+				for n > 1 && !utf8.RuneStart(line[n]) {
+					n--
+				}
+				b.WriteString(line[:n])
+				page.cutLineSize = len(lines[i])
+				page.nextColumn = start + n
+				end = i + 1
+			} else {
+				end = i
+			}
+			break
+		}
+		b.WriteString(line)
+	}
+
+	page.content = b.String()
+	page.start = offset
+	page.end = end
+	return page, nil
+}
+
+// note tells how to read the rest of the file when the page doesn't reach its end.
+func (p filePage) note() string {
+	if p.end >= p.total && !p.tooLarge {
+		return ""
+	}
+
+	var note string
+	if p.tooLarge {
+		note = fmt.Sprintf("File too large (%d bytes), showing lines %d-%d of %d.", p.size, p.start, p.end, p.total)
+	} else {
+		note = fmt.Sprintf("Showing lines %d-%d of %d.", p.start, p.end, p.total)
+	}
+	if p.cutLineSize > 0 {
+		note += fmt.Sprintf(" Line %d is %d bytes long and was cut before byte %d; use offset=%d and column=%d to continue reading it.", p.end, p.cutLineSize, p.nextColumn, p.end, p.nextColumn)
+	} else if p.end < p.total {
+		note += fmt.Sprintf(" Use offset=%d to continue.", p.end+1)
+	}
+	return note
+}
+
+// output returns the content of the page followed by its note, if any.
+func (p filePage) output() string {
+	note := p.note()
+	if note == "" {
+		return p.content
+	}
+	return strings.TrimSuffix(p.content, "\n") + "\n\n[" + note + "]"
+}
+
+func (p filePage) meta(path string) ReadFileMeta {
+	lineCount := 0
+	if p.end > 0 {
+		lineCount = p.end - p.start + 1
+	}
+	return ReadFileMeta{
+		Path:       path,
+		Content:    p.content,
+		LineCount:  lineCount,
+		TotalLines: p.total,
+		StartLine:  p.start,
+		EndLine:    p.end,
+	}
 }
 
 // readImageFile reads an image file and returns it as base64-encoded image content.
@@ -610,6 +780,7 @@ func (t *FilesystemTool) handleReadMultipleFiles(ctx context.Context, args ReadM
 	type PathContent struct {
 		Path    string `json:"path"`
 		Content string `json:"content"`
+		Note    string `json:"note,omitempty"`
 	}
 
 	var contents []PathContent
@@ -625,6 +796,10 @@ func (t *FilesystemTool) handleReadMultipleFiles(ctx context.Context, args ReadM
 		resolvedPath := t.resolvePath(path)
 
 		content, err := os.ReadFile(resolvedPath)
+		var page filePage
+		if err == nil {
+			page, err = t.readPage(string(content), args.Offset, args.Column, args.Limit)
+		}
 		if err != nil {
 			errMsg := err.Error()
 			if os.IsNotExist(err) {
@@ -641,11 +816,10 @@ func (t *FilesystemTool) handleReadMultipleFiles(ctx context.Context, args ReadM
 
 		contents = append(contents, PathContent{
 			Path:    path,
-			Content: string(content),
+			Content: page.content,
+			Note:    page.note(),
 		})
-		entry.Content = string(content)
-		entry.LineCount = strings.Count(string(content), "\n") + 1
-		meta.Files = append(meta.Files, entry)
+		meta.Files = append(meta.Files, page.meta(path))
 	}
 
 	var output string
@@ -659,6 +833,9 @@ func (t *FilesystemTool) handleReadMultipleFiles(ctx context.Context, args ReadM
 		var result strings.Builder
 		for _, content := range contents {
 			fmt.Fprintf(&result, "=== %s ===\n%s\n\n", content.Path, content.Content)
+			if content.Note != "" {
+				fmt.Fprintf(&result, "[%s]\n\n", content.Note)
+			}
 		}
 		output = result.String()
 	}
@@ -780,6 +957,7 @@ func (t *FilesystemTool) handleSearchFilesContent(_ context.Context, args Search
 
 func (t *FilesystemTool) handleWriteFile(ctx context.Context, args WriteFileArgs) (*tools.ToolCallResult, error) {
 	resolvedPath := t.resolvePath(args.Path)
+	checkpoint.Snapshot(ctx, resolvedPath)
 
 	// Create parent directory structure if it doesn't exist
 	dir := filepath.Dir(resolvedPath)
@@ -798,10 +976,11 @@ func (t *FilesystemTool) handleWriteFile(ctx context.Context, args WriteFileArgs
 	return tools.ResultSuccess(fmt.Sprintf("File written successfully: %s (%d bytes)", args.Path, len(args.Content))), nil
 }
 
-func (t *FilesystemTool) handleCreateDirectory(_ context.Context, args CreateDirectoryArgs) (*tools.ToolCallResult, error) {
+func (t *FilesystemTool) handleCreateDirectory(ctx context.Context, args CreateDirectoryArgs) (*tools.ToolCallResult, error) {
 	var results []string
 	for _, path := range args.Paths {
 		resolvedPath := t.resolvePath(path)
+		checkpoint.Snapshot(ctx, resolvedPath)
 		if err := os.MkdirAll(resolvedPath, 0o755); err != nil {
 			return tools.ResultError(fmt.Sprintf("Error creating directory %s: %s", path, err)), nil
 		}
@@ -811,10 +990,11 @@ func (t *FilesystemTool) handleCreateDirectory(_ context.Context, args CreateDir
 	return tools.ResultSuccess(strings.Join(results, "\n")), nil
 }
 
-func (t *FilesystemTool) handleRemoveDirectory(_ context.Context, args RemoveDirectoryArgs) (*tools.ToolCallResult, error) {
+func (t *FilesystemTool) handleRemoveDirectory(ctx context.Context, args RemoveDirectoryArgs) (*tools.ToolCallResult, error) {
 	var results []string
 	for _, path := range args.Paths {
 		resolvedPath := t.resolvePath(path)
+		checkpoint.Snapshot(ctx, resolvedPath)
 
 		if err := rmdir(resolvedPath); err != nil {
 			return tools.ResultError(fmt.Sprintf("Error removing directory %s: %s", path, err)), nil
@@ -9,6 +9,7 @@ import (
 	"image/png"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -135,6 +136,87 @@ func TestFilesystemTool_ReadFile(t *testing.T) {
 	assert.Equal(t, "not found", result.Output)
 }
 
+func TestFilesystemTool_ReadFile_OffsetAndLimit(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	tool := NewFilesystemTool(tmpDir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "lines.txt"), []byte("one\ntwo\nthree\nfour\nfive\n"), 0o644))
+
+	result, err := tool.handleReadFile(t.Context(), ReadFileArgs{Path: "lines.txt", Offset: 2, Limit: 2})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "two\nthree\n\n[Showing lines 2-3 of 5. Use offset=4 to continue.]", result.Output)
+
+	meta, ok := result.Meta.(ReadFileMeta)
+	require.True(t, ok)
+	assert.Equal(t, 2, meta.LineCount)
+	assert.Equal(t, 5, meta.TotalLines)
+	assert.Equal(t, 2, meta.StartLine)
+	assert.Equal(t, 3, meta.EndLine)
+
+	result, err = tool.handleReadFile(t.Context(), ReadFileArgs{Path: "lines.txt", Offset: 4})
+	require.NoError(t, err)
+	assert.Equal(t, "four\nfive\n", result.Output)
+
+	result, err = tool.handleReadFile(t.Context(), ReadFileArgs{Path: "lines.txt", Offset: 10})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Output, "beyond the end of the file (5 lines)")
+}
+
+func TestFilesystemTool_ReadFile_PagesLargeFiles(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	tool := NewFilesystemTool(tmpDir, WithMaxReadSize(10))
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "large.txt"), []byte("aaaa\nbbbb\ncccc\n"), 0o644))
+
+	result, err := tool.handleReadFile(t.Context(), ReadFileArgs{Path: "large.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, "aaaa\nbbbb\n\n[File too large (15 bytes), showing lines 1-2 of 3. Use offset=3 to continue.]", result.Output)
+
+	result, err = tool.handleReadFile(t.Context(), ReadFileArgs{Path: "large.txt", Offset: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "cccc\n", result.Output)
+
+	// An explicit limit doesn't lift the maximum size.
+	result, err = tool.handleReadFile(t.Context(), ReadFileArgs{Path: "large.txt", Limit: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "aaaa\nbbbb\n\n[File too large (15 bytes), showing lines 1-2 of 3. Use offset=3 to continue.]", result.Output)
+
+	// A single line larger than the maximum size is cut, and read in pages
+	// from the column where it was cut.
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "minified.js"), []byte(strings.Repeat("x", 25)+"\nend\n"), 0o644))
+	result, err = tool.handleReadFile(t.Context(), ReadFileArgs{Path: "minified.js"})
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("x", 10)+"\n\n[File too large (30 bytes), showing lines 1-1 of 2. Line 1 is 26 bytes long and was cut before byte 11; use offset=1 and column=11 to continue reading it.]", result.Output)
+
+	result, err = tool.handleReadFile(t.Context(), ReadFileArgs{Path: "minified.js", Offset: 1, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("x", 10)+"\n\n[File too large (30 bytes), showing lines 1-1 of 2. Line 1 is 26 bytes long and was cut before byte 11; use offset=1 and column=11 to continue reading it.]", result.Output)
+
+	result, err = tool.handleReadFile(t.Context(), ReadFileArgs{Path: "minified.js", Offset: 1, Column: 11})
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("x", 10)+"\n\n[File too large (30 bytes), showing lines 1-1 of 2. Line 1 is 26 bytes long and was cut before byte 21; use offset=1 and column=21 to continue reading it.]", result.Output)
+
+	result, err = tool.handleReadFile(t.Context(), ReadFileArgs{Path: "minified.js", Offset: 1, Column: 21})
+	require.NoError(t, err)
+	assert.Equal(t, "xxxxx\nend\n", result.Output)
+
+	result, err = tool.handleReadFile(t.Context(), ReadFileArgs{Path: "minified.js", Offset: 1, Column: 27})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Output, "column 27 is beyond the end of line 1 (26 bytes)")
+
+	// Multi-byte characters are not cut in the middle.
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "utf8.txt"), []byte(strings.Repeat("é", 10)+"\n"), 0o644))
+	result, err = tool.handleReadFile(t.Context(), ReadFileArgs{Path: "utf8.txt", Limit: 1})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(result.Output, strings.Repeat("é", 5)+"\n\n"))
+	assert.Contains(t, result.Output, "use offset=1 and column=11 to continue reading it")
+}
+
 func TestFilesystemTool_ReadImageFile(t *testing.T) {
 	t.Parallel()
 	tmpDir := t.TempDir()
@@ -199,6 +281,30 @@ func TestFilesystemTool_ReadMultipleFiles(t *testing.T) {
 	assert.Contains(t, result.Output, "not found")
 }
 
+func TestFilesystemTool_ReadMultipleFiles_Paged(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	tool := NewFilesystemTool(tmpDir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "a.txt"), []byte("a1\na2\na3\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "b.txt"), []byte("b1\n"), 0o644))
+
+	result, err := tool.handleReadMultipleFiles(t.Context(), ReadMultipleFilesArgs{
+		Paths: []string{"a.txt", "b.txt"},
+		Limit: 1,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "=== a.txt ===\na1\n\n\n[Showing lines 1-1 of 3. Use offset=2 to continue.]")
+	assert.Contains(t, result.Output, "=== b.txt ===\nb1\n")
+	assert.NotContains(t, result.Output, "of 1.")
+
+	meta, ok := result.Meta.(ReadMultipleFilesMeta)
+	require.True(t, ok)
+	require.Len(t, meta.Files, 2)
+	assert.Equal(t, 3, meta.Files[0].TotalLines)
+	assert.Equal(t, 1, meta.Files[0].LineCount)
+}
+
 func TestFilesystemTool_ListDirectory(t *testing.T) {
 	t.Parallel()
 	tmpDir := t.TempDir()
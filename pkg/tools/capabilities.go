@@ -19,6 +19,17 @@ type Elicitable interface {
 	SetElicitationHandler(handler ElicitationHandler)
 }
 
+// SamplingCapable is implemented by toolsets that support MCP sampling.
+type SamplingCapable interface {
+	SetSamplingHandler(handler SamplingHandler)
+}
+
+// RootsCapable is implemented by toolsets that can be told the directories
+// they may operate in, like MCP servers supporting roots.
+type RootsCapable interface {
+	SetRoots(dirs []string)
+}
+
 // OAuthCapable is implemented by toolsets that support OAuth flows.
 type OAuthCapable interface {
 	SetOAuthSuccessHandler(handler func())
@@ -20,7 +20,10 @@ type GatewayToolset struct {
 	cleanUp func() error
 }
 
-var _ tools.ToolSet = (*GatewayToolset)(nil)
+var (
+	_ tools.ToolSet   = (*GatewayToolset)(nil)
+	_ tools.Unwrapper = (*GatewayToolset)(nil)
+)
 
 func NewGatewayToolset(ctx context.Context, name, mcpServerName string, config any, envProvider environment.Provider, cwd string) (*GatewayToolset, error) {
 	slog.Debug("Creating MCP Gateway toolset", "name", mcpServerName)
@@ -65,6 +68,12 @@ func NewGatewayToolset(ctx context.Context, name, mcpServerName string, config a
 	}, nil
 }
 
+// Unwrap implements tools.Unwrapper, so that the MCP toolset talking to the
+// gateway can be found with tools.As.
+func (t *GatewayToolset) Unwrap() tools.ToolSet {
+	return t.Toolset
+}
+
 func (t *GatewayToolset) Stop(ctx context.Context) error {
 	return errors.Join(t.Toolset.Stop(ctx), t.cleanUp())
 }
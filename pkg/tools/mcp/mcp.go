@@ -11,6 +11,7 @@ import (
 	"iter"
 	"log/slog"
 	"net/url"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -26,11 +27,22 @@ type mcpClient interface {
 	CallTool(ctx context.Context, request *mcp.CallToolParams) (*mcp.CallToolResult, error)
 	ListPrompts(ctx context.Context, request *mcp.ListPromptsParams) iter.Seq2[*mcp.Prompt, error]
 	GetPrompt(ctx context.Context, request *mcp.GetPromptParams) (*mcp.GetPromptResult, error)
+	ListResources(ctx context.Context, request *mcp.ListResourcesParams) iter.Seq2[*mcp.Resource, error]
+	ReadResource(ctx context.Context, request *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error)
+	Subscribe(ctx context.Context, request *mcp.SubscribeParams) error
+	Unsubscribe(ctx context.Context, request *mcp.UnsubscribeParams) error
+	SetLoggingLevel(ctx context.Context, request *mcp.SetLoggingLevelParams) error
 	SetElicitationHandler(handler tools.ElicitationHandler)
+	SetSamplingHandler(handler samplingFunc)
+	SetRoots(roots []*mcp.Root)
 	SetOAuthSuccessHandler(handler func())
 	SetManagedOAuth(managed bool)
 	SetToolListChangedHandler(handler func())
 	SetPromptListChangedHandler(handler func())
+	SetResourceListChangedHandler(handler func())
+	SetResourceUpdatedHandler(handler func(uri string))
+	SetProgressNotificationHandler(handler func(*mcp.ProgressNotificationParams))
+	SetLoggingMessageHandler(handler func(*mcp.LoggingMessageParams))
 	// Wait blocks until the underlying connection is closed by the server.
 	// It returns nil if the connection was closed gracefully.
 	Wait() error
@@ -48,23 +60,40 @@ type Toolset struct {
 	started      bool
 	stopping     bool // true when Stop() has been called
 
-	// Cached tools and prompts, invalidated via MCP notifications.
+	// Cached tools, prompts and resources, invalidated via MCP notifications.
 	// cacheGen is bumped on each invalidation so that a concurrent
-	// Tools()/ListPrompts() call can detect that its result is stale.
-	cachedTools   []tools.Tool
-	cachedPrompts []PromptInfo
-	cacheGen      uint64
+	// Tools()/ListPrompts()/ListResources() call can detect that its result
+	// is stale.
+	cachedTools     []tools.Tool
+	cachedPrompts   []PromptInfo
+	cachedResources []ResourceInfo
+	cacheGen        uint64
+
+	// resources is what the server told us about its resources when we
+	// connected, nil if it has none.
+	resources *mcp.ResourceCapabilities
+	// resourceTools exposes the resources of the server to the agent with
+	// the list_resources and read_resource tools.
+	resourceTools bool
+	// subscriptions are the URIs of the resources we subscribed to, so that
+	// we can subscribe again after a restart.
+	subscriptions map[string]bool
+
+	// progressHandlers are the handlers of the running tool calls that want
+	// to know their progress, by progress token.
+	progressHandlers map[string]tools.ProgressHandler
 
 	// toolsChangedHandler is called after the tool cache is refreshed
 	// following a ToolListChanged notification from the server.
 	toolsChangedHandler func()
 }
 
-// invalidateCache clears the cached tools and prompts and bumps the
-// generation counter. The caller must hold ts.mu.
+// invalidateCache clears the cached tools, prompts and resources and bumps
+// the generation counter. The caller must hold ts.mu.
 func (ts *Toolset) invalidateCache() {
 	ts.cachedTools = nil
 	ts.cachedPrompts = nil
+	ts.cachedResources = nil
 	ts.cacheGen++
 }
 
@@ -75,10 +104,12 @@ var (
 
 // Verify that Toolset implements optional capability interfaces
 var (
-	_ tools.Instructable   = (*Toolset)(nil)
-	_ tools.Elicitable     = (*Toolset)(nil)
-	_ tools.OAuthCapable   = (*Toolset)(nil)
-	_ tools.ChangeNotifier = (*Toolset)(nil)
+	_ tools.Instructable    = (*Toolset)(nil)
+	_ tools.Elicitable      = (*Toolset)(nil)
+	_ tools.SamplingCapable = (*Toolset)(nil)
+	_ tools.RootsCapable    = (*Toolset)(nil)
+	_ tools.OAuthCapable    = (*Toolset)(nil)
+	_ tools.ChangeNotifier  = (*Toolset)(nil)
 )
 
 // NewToolsetCommand creates a new MCP toolset from a command.
@@ -199,6 +230,16 @@ func (ts *Toolset) doStart(ctx context.Context) error {
 		slog.Debug("MCP server notified prompt list changed, refreshing", "server", ts.logID)
 		ts.refreshPromptCache(ctx)
 	})
+	ts.mcpClient.SetResourceListChangedHandler(func() {
+		ts.mu.Lock()
+		ts.invalidateCache()
+		ts.mu.Unlock()
+
+		slog.Debug("MCP server notified resource list changed, refreshing", "server", ts.logID)
+		ts.refreshResourceCache(ctx)
+	})
+	ts.mcpClient.SetProgressNotificationHandler(ts.handleProgress)
+	ts.mcpClient.SetLoggingMessageHandler(ts.handleLogMessage)
 
 	initRequest := &mcp.InitializeRequest{
 		Params: &mcp.InitializeParams{
@@ -211,6 +252,8 @@ func (ts *Toolset) doStart(ctx context.Context) error {
 					Form: &mcp.FormElicitationCapabilities{},
 					URL:  &mcp.URLElicitationCapabilities{},
 				},
+				Sampling: &mcp.SamplingCapabilities{},
+				RootsV2:  &mcp.RootCapabilities{ListChanged: true},
 			},
 		},
 	}
@@ -254,6 +297,14 @@ func (ts *Toolset) doStart(ctx context.Context) error {
 
 	slog.Debug("Started MCP toolset successfully", "server", ts.logID)
 	ts.instructions = result.Instructions
+	ts.resources = nil
+	if result.Capabilities != nil {
+		ts.resources = result.Capabilities.Resources
+		if result.Capabilities.Logging != nil {
+			ts.setLoggingLevel(ctx)
+		}
+	}
+	ts.resubscribe(ctx)
 
 	return nil
 }
@@ -373,6 +424,13 @@ func (ts *Toolset) Tools(ctx context.Context) ([]tools.Tool, error) {
 		slog.Debug("Added MCP tool", "tool", name)
 	}
 
+	ts.mu.Lock()
+	withResourceTools := ts.resourceTools && ts.resources != nil
+	ts.mu.Unlock()
+	if withResourceTools {
+		toolsList = append(toolsList, ts.resourceToolsList()...)
+	}
+
 	slog.Debug("Listed MCP tools", "count", len(toolsList), "server", ts.logID)
 
 	ts.mu.Lock()
@@ -405,6 +463,15 @@ func (ts *Toolset) refreshToolCache(ctx context.Context) {
 	}
 }
 
+// refreshResourceCache fetches the resource list from the server and
+// populates the cache. It is called by the ResourceListChanged notification
+// handler.
+func (ts *Toolset) refreshResourceCache(ctx context.Context) {
+	if _, err := ts.ListResources(ctx); err != nil {
+		slog.Warn("Failed to refresh resources after notification", "server", ts.logID, "error", err)
+	}
+}
+
 // refreshPromptCache fetches the prompt list from the server and populates
 // the cache. It is called by the PromptListChanged notification handler.
 func (ts *Toolset) refreshPromptCache(ctx context.Context) {
@@ -436,6 +503,13 @@ func (ts *Toolset) callTool(ctx context.Context, toolCall tools.ToolCall) (*tool
 	request := &mcp.CallToolParams{}
 	request.Name = toolCall.Function.Name
 	request.Arguments = args
+	if handler := tools.ProgressHandlerFromContext(ctx); handler != nil && toolCall.ID != "" {
+		// SetProgressToken doesn't store the token when Meta is nil.
+		request.Meta = mcp.Meta{}
+		request.SetProgressToken(toolCall.ID)
+		ts.addProgressHandler(toolCall.ID, handler)
+		defer ts.removeProgressHandler(toolCall.ID)
+	}
 
 	resp, err := ts.mcpClient.CallTool(ctx, request)
 	if err != nil {
@@ -533,6 +607,41 @@ func (ts *Toolset) SetElicitationHandler(handler tools.ElicitationHandler) {
 	ts.mcpClient.SetElicitationHandler(handler)
 }
 
+// SetSamplingHandler sets the handler that answers sampling requests from the
+// MCP server, on behalf of this toolset.
+func (ts *Toolset) SetSamplingHandler(handler tools.SamplingHandler) {
+	if handler == nil {
+		ts.mcpClient.SetSamplingHandler(nil)
+		return
+	}
+	server := cmp.Or(ts.name, ts.logID)
+	ts.mcpClient.SetSamplingHandler(func(ctx context.Context, req *mcp.CreateMessageParams) (*mcp.CreateMessageResult, error) {
+		return handler(ctx, server, req)
+	})
+}
+
+// SetRoots sets the directories advertised to the MCP server as its roots.
+func (ts *Toolset) SetRoots(dirs []string) {
+	roots := make([]*mcp.Root, 0, len(dirs))
+	for _, dir := range dirs {
+		roots = append(roots, &mcp.Root{
+			URI:  fileURI(dir),
+			Name: filepath.Base(dir),
+		})
+	}
+	ts.mcpClient.SetRoots(roots)
+}
+
+// fileURI returns the file:// URI of an absolute path.
+func fileURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		// Windows paths, like C:/Users
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
 func (ts *Toolset) SetOAuthSuccessHandler(handler func()) {
 	ts.mcpClient.SetOAuthSuccessHandler(handler)
 }
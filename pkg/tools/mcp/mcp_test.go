@@ -14,7 +14,10 @@ import (
 
 // mockMCPClient is a test double for the mcpClient interface.
 type mockMCPClient struct {
-	callToolFn func(ctx context.Context, request *mcp.CallToolParams) (*mcp.CallToolResult, error)
+	callToolFn     func(ctx context.Context, request *mcp.CallToolParams) (*mcp.CallToolResult, error)
+	resources      []*mcp.Resource
+	readResourceFn func(ctx context.Context, request *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error)
+	roots          []*mcp.Root
 }
 
 func (m *mockMCPClient) Initialize(context.Context, *mcp.InitializeRequest) (*mcp.InitializeResult, error) {
@@ -37,8 +40,34 @@ func (m *mockMCPClient) GetPrompt(context.Context, *mcp.GetPromptParams) (*mcp.G
 	return &mcp.GetPromptResult{}, nil
 }
 
+func (m *mockMCPClient) ListResources(context.Context, *mcp.ListResourcesParams) iter.Seq2[*mcp.Resource, error] {
+	return func(yield func(*mcp.Resource, error) bool) {
+		for _, r := range m.resources {
+			if !yield(r, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (m *mockMCPClient) ReadResource(ctx context.Context, request *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error) {
+	return m.readResourceFn(ctx, request)
+}
+
+func (m *mockMCPClient) Subscribe(context.Context, *mcp.SubscribeParams) error { return nil }
+
+func (m *mockMCPClient) Unsubscribe(context.Context, *mcp.UnsubscribeParams) error { return nil }
+
+func (m *mockMCPClient) SetLoggingLevel(context.Context, *mcp.SetLoggingLevelParams) error {
+	return nil
+}
+
 func (m *mockMCPClient) SetElicitationHandler(tools.ElicitationHandler) {}
 
+func (m *mockMCPClient) SetSamplingHandler(samplingFunc) {}
+
+func (m *mockMCPClient) SetRoots(roots []*mcp.Root) { m.roots = roots }
+
 func (m *mockMCPClient) SetOAuthSuccessHandler(func()) {}
 
 func (m *mockMCPClient) SetManagedOAuth(bool) {}
@@ -47,6 +76,14 @@ func (m *mockMCPClient) SetToolListChangedHandler(func()) {}
 
 func (m *mockMCPClient) SetPromptListChangedHandler(func()) {}
 
+func (m *mockMCPClient) SetResourceListChangedHandler(func()) {}
+
+func (m *mockMCPClient) SetResourceUpdatedHandler(func(string)) {}
+
+func (m *mockMCPClient) SetProgressNotificationHandler(func(*mcp.ProgressNotificationParams)) {}
+
+func (m *mockMCPClient) SetLoggingMessageHandler(func(*mcp.LoggingMessageParams)) {}
+
 func (m *mockMCPClient) Wait() error { return nil }
 
 func (m *mockMCPClient) Close(context.Context) error { return nil }
@@ -0,0 +1,112 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// setLoggingLevel asks the server to send us its log messages. Servers don't
+// send any until we do. We ask for debug messages only when we log them.
+func (ts *Toolset) setLoggingLevel(ctx context.Context) {
+	level := mcp.LoggingLevel("info")
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		level = "debug"
+	}
+
+	if err := ts.mcpClient.SetLoggingLevel(ctx, &mcp.SetLoggingLevelParams{Level: level}); err != nil {
+		slog.Debug("Failed to set MCP server logging level", "server", ts.logID, "error", err)
+	}
+}
+
+func (ts *Toolset) addProgressHandler(token string, handler tools.ProgressHandler) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.progressHandlers == nil {
+		ts.progressHandlers = make(map[string]tools.ProgressHandler)
+	}
+	ts.progressHandlers[token] = handler
+}
+
+func (ts *Toolset) removeProgressHandler(token string) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	delete(ts.progressHandlers, token)
+}
+
+// handleProgress reports the progress notifications of the server to the
+// tool call they are about, identified by its progress token.
+func (ts *Toolset) handleProgress(params *mcp.ProgressNotificationParams) {
+	token, _ := params.ProgressToken.(string)
+
+	ts.mu.Lock()
+	handler := ts.progressHandlers[token]
+	ts.mu.Unlock()
+
+	if handler == nil {
+		slog.Debug("Ignoring MCP progress notification for unknown token", "server", ts.logID, "token", params.ProgressToken)
+		return
+	}
+
+	handler(tools.ToolProgress{
+		Progress: params.Progress,
+		Total:    params.Total,
+		Message:  params.Message,
+	})
+}
+
+// handleLogMessage logs the messages of the server at their level. Log
+// messages aren't tied to a request, so they are also reported as the
+// progress of the running tool call when there is exactly one.
+func (ts *Toolset) handleLogMessage(params *mcp.LoggingMessageParams) {
+	text := logText(params.Data)
+	slog.Log(context.Background(), logLevel(params.Level), "MCP server log message", "server", ts.logID, "logger", params.Logger, "message", text)
+
+	ts.mu.Lock()
+	var handler tools.ProgressHandler
+	if len(ts.progressHandlers) == 1 {
+		for _, h := range ts.progressHandlers {
+			handler = h
+		}
+	}
+	ts.mu.Unlock()
+
+	if handler != nil && logLevel(params.Level) >= slog.LevelInfo {
+		handler(tools.ToolProgress{Message: text})
+	}
+}
+
+// logLevel maps the syslog levels of MCP log messages to slog levels.
+func logLevel(level mcp.LoggingLevel) slog.Level {
+	switch level {
+	case "debug":
+		return slog.LevelDebug
+	case "info", "notice":
+		return slog.LevelInfo
+	case "warning":
+		return slog.LevelWarn
+	default:
+		// error, critical, alert and emergency
+		return slog.LevelError
+	}
+}
+
+// logText returns the data of a log message as text. Servers can send any
+// JSON value.
+func logText(data any) string {
+	if s, ok := data.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Sprint(data)
+	}
+	return string(b)
+}
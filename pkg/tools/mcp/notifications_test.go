@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+func TestCallToolReportsProgress(t *testing.T) {
+	t.Parallel()
+
+	ts := &Toolset{started: true}
+	ts.mcpClient = &mockMCPClient{
+		callToolFn: func(_ context.Context, request *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+			assert.Equal(t, "call_1", request.GetProgressToken())
+
+			ts.handleProgress(&mcp.ProgressNotificationParams{ProgressToken: "call_1", Progress: 1, Total: 4, Message: "Building"})
+			ts.handleProgress(&mcp.ProgressNotificationParams{ProgressToken: "other", Progress: 2})
+			ts.handleLogMessage(&mcp.LoggingMessageParams{Level: "info", Data: "step 2"})
+			ts.handleLogMessage(&mcp.LoggingMessageParams{Level: "debug", Data: "details"})
+
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "done"}}}, nil
+		},
+	}
+
+	var progress []tools.ToolProgress
+	ctx := tools.WithProgressHandler(t.Context(), func(p tools.ToolProgress) {
+		progress = append(progress, p)
+	})
+
+	result, err := ts.callTool(ctx, tools.ToolCall{ID: "call_1", Function: tools.FunctionCall{Name: "build"}})
+	require.NoError(t, err)
+	assert.Equal(t, "done", result.Output)
+
+	assert.Equal(t, []tools.ToolProgress{
+		{Progress: 1, Total: 4, Message: "Building"},
+		{Message: "step 2"},
+	}, progress)
+	assert.Empty(t, ts.progressHandlers)
+}
+
+func TestCallToolWithoutProgressHandler(t *testing.T) {
+	t.Parallel()
+
+	ts := &Toolset{started: true}
+	ts.mcpClient = &mockMCPClient{
+		callToolFn: func(_ context.Context, request *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+			assert.Nil(t, request.GetProgressToken())
+			return &mcp.CallToolResult{}, nil
+		},
+	}
+
+	_, err := ts.callTool(t.Context(), tools.ToolCall{ID: "call_1", Function: tools.FunctionCall{Name: "build"}})
+	require.NoError(t, err)
+}
+
+func TestLogLevel(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, slog.LevelDebug, logLevel("debug"))
+	assert.Equal(t, slog.LevelInfo, logLevel("info"))
+	assert.Equal(t, slog.LevelInfo, logLevel("notice"))
+	assert.Equal(t, slog.LevelWarn, logLevel("warning"))
+	assert.Equal(t, slog.LevelError, logLevel("error"))
+	assert.Equal(t, slog.LevelError, logLevel("emergency"))
+}
+
+func TestLogText(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "plain", logText("plain"))
+	assert.JSONEq(t, `{"file":"main.go","line":3}`, logText(map[string]any{"file": "main.go", "line": 3}))
+}
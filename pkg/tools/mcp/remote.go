@@ -57,7 +57,7 @@ func (c *remoteMCPClient) Initialize(ctx context.Context, _ *gomcp.InitializeReq
 		return nil, fmt.Errorf("unsupported transport type: %s", c.transportType)
 	}
 
-	// Create an MCP client with elicitation support
+	// Create an MCP client with elicitation and sampling support
 	impl := &gomcp.Implementation{
 		Name:    "docker agent",
 		Version: "1.0.0",
@@ -66,12 +66,18 @@ func (c *remoteMCPClient) Initialize(ctx context.Context, _ *gomcp.InitializeReq
 	toolChanged, promptChanged := c.notificationHandlers()
 
 	opts := &gomcp.ClientOptions{
-		ElicitationHandler:       c.handleElicitationRequest,
-		ToolListChangedHandler:   toolChanged,
-		PromptListChangedHandler: promptChanged,
+		ElicitationHandler:          c.handleElicitationRequest,
+		CreateMessageHandler:        c.handleCreateMessageRequest,
+		ToolListChangedHandler:      toolChanged,
+		PromptListChangedHandler:    promptChanged,
+		ResourceListChangedHandler:  c.handleResourceListChanged,
+		ResourceUpdatedHandler:      c.handleResourceUpdated,
+		ProgressNotificationHandler: c.handleProgressNotification,
+		LoggingMessageHandler:       c.handleLoggingMessage,
 	}
 
 	client := gomcp.NewClient(impl, opts)
+	c.setClient(client)
 
 	// Connect to the MCP server
 	session, err := client.Connect(ctx, transport, nil)
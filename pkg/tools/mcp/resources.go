@@ -0,0 +1,251 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+// ResourceInfo contains metadata about a resource of an MCP server
+type ResourceInfo struct {
+	URI         string `json:"uri"`                   // The URI of the resource
+	Name        string `json:"name"`                  // The resource name
+	Title       string `json:"title,omitempty"`       // Human-readable title, if any
+	Description string `json:"description,omitempty"` // Human-readable description of the resource
+	MIMEType    string `json:"mime_type,omitempty"`   // The MIME type of the resource, if known
+	Size        int64  `json:"size,omitempty"`        // The size of the resource in bytes, if known
+	Server      string `json:"server,omitempty"`      // The name of the toolset of the server
+}
+
+// ReadResourceArgs are the arguments of the read_resource tool.
+type ReadResourceArgs struct {
+	URI string `json:"uri" jsonschema:"The URI of the resource to read, as returned by list_resources"`
+}
+
+// SetResourceTools sets whether the resources of the server are exposed to
+// the agent with the list_resources and read_resource tools.
+func (ts *Toolset) SetResourceTools(enabled bool) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.resourceTools = enabled
+	ts.invalidateCache()
+}
+
+// SetResourceUpdatedHandler sets the handler called with the URI of a
+// resource we subscribed to when the server notifies us that it changed.
+func (ts *Toolset) SetResourceUpdatedHandler(handler func(uri string)) {
+	ts.mcpClient.SetResourceUpdatedHandler(handler)
+}
+
+// ListResources retrieves the resources available from the MCP server.
+// Servers without resources have an empty list.
+func (ts *Toolset) ListResources(ctx context.Context) ([]ResourceInfo, error) {
+	ts.mu.Lock()
+	if !ts.started {
+		ts.mu.Unlock()
+		return nil, errors.New("toolset not started")
+	}
+	if ts.resources == nil {
+		ts.mu.Unlock()
+		return nil, nil
+	}
+	if ts.cachedResources != nil {
+		result := ts.cachedResources
+		ts.mu.Unlock()
+		return result, nil
+	}
+	gen := ts.cacheGen
+	ts.mu.Unlock()
+
+	slog.Debug("Listing MCP resources (cache miss)", "server", ts.logID)
+
+	resourcesList := []ResourceInfo{}
+	for resource, err := range ts.mcpClient.ListResources(ctx, &mcp.ListResourcesParams{}) {
+		if err != nil {
+			slog.Warn("Error listing MCP resource", "error", err)
+			return nil, err
+		}
+
+		resourcesList = append(resourcesList, ResourceInfo{
+			URI:         resource.URI,
+			Name:        resource.Name,
+			Title:       resource.Title,
+			Description: resource.Description,
+			MIMEType:    resource.MIMEType,
+			Size:        resource.Size,
+			Server:      ts.name,
+		})
+	}
+
+	slog.Debug("Listed MCP resources", "count", len(resourcesList), "server", ts.logID)
+
+	ts.mu.Lock()
+	if ts.cacheGen == gen {
+		ts.cachedResources = resourcesList
+	}
+	ts.mu.Unlock()
+
+	return resourcesList, nil
+}
+
+// ReadResource reads a resource of the MCP server and returns its content as
+// text. Binary content is replaced with a short description of it.
+func (ts *Toolset) ReadResource(ctx context.Context, uri string) (string, error) {
+	ts.mu.Lock()
+	started := ts.started
+	ts.mu.Unlock()
+	if !started {
+		return "", errors.New("toolset not started")
+	}
+
+	slog.Debug("Reading MCP resource", "uri", uri, "server", ts.logID)
+
+	result, err := ts.mcpClient.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+	if err != nil {
+		slog.Error("Failed to read MCP resource", "uri", uri, "error", err)
+		return "", fmt.Errorf("failed to read resource %s: %w", uri, err)
+	}
+
+	return resourceText(result), nil
+}
+
+// SubscribeResource asks the server to notify us when a resource changes.
+// It does nothing for servers that don't support subscriptions.
+func (ts *Toolset) SubscribeResource(ctx context.Context, uri string) error {
+	ts.mu.Lock()
+	if !ts.started {
+		ts.mu.Unlock()
+		return errors.New("toolset not started")
+	}
+	if ts.resources == nil || !ts.resources.Subscribe || ts.subscriptions[uri] {
+		ts.mu.Unlock()
+		return nil
+	}
+	ts.mu.Unlock()
+
+	if err := ts.mcpClient.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}); err != nil {
+		return fmt.Errorf("failed to subscribe to resource %s: %w", uri, err)
+	}
+
+	ts.mu.Lock()
+	if ts.subscriptions == nil {
+		ts.subscriptions = make(map[string]bool)
+	}
+	ts.subscriptions[uri] = true
+	ts.mu.Unlock()
+
+	slog.Debug("Subscribed to MCP resource", "uri", uri, "server", ts.logID)
+	return nil
+}
+
+// UnsubscribeResource tells the server that we no longer need the updates of
+// a resource. It does nothing if we didn't subscribe to it.
+func (ts *Toolset) UnsubscribeResource(ctx context.Context, uri string) error {
+	ts.mu.Lock()
+	if !ts.started || !ts.subscriptions[uri] {
+		ts.mu.Unlock()
+		return nil
+	}
+	delete(ts.subscriptions, uri)
+	ts.mu.Unlock()
+
+	if err := ts.mcpClient.Unsubscribe(ctx, &mcp.UnsubscribeParams{URI: uri}); err != nil {
+		return fmt.Errorf("failed to unsubscribe from resource %s: %w", uri, err)
+	}
+
+	slog.Debug("Unsubscribed from MCP resource", "uri", uri, "server", ts.logID)
+	return nil
+}
+
+// resubscribe subscribes again to the resources we were subscribed to before
+// the server restarted. The caller must hold ts.mu.
+func (ts *Toolset) resubscribe(ctx context.Context) {
+	if ts.resources == nil || !ts.resources.Subscribe {
+		return
+	}
+	for uri := range ts.subscriptions {
+		if err := ts.mcpClient.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}); err != nil {
+			slog.Warn("Failed to subscribe again to MCP resource", "uri", uri, "server", ts.logID, "error", err)
+		}
+	}
+}
+
+// resourceToolsList returns the tools that let the agent list and read the
+// resources of the server.
+func (ts *Toolset) resourceToolsList() []tools.Tool {
+	listName, readName := "list_resources", "read_resource"
+	if ts.name != "" {
+		listName = fmt.Sprintf("%s_%s", ts.name, listName)
+		readName = fmt.Sprintf("%s_%s", ts.name, readName)
+	}
+
+	return []tools.Tool{
+		{
+			Name:         listName,
+			Category:     "mcp",
+			Description:  "List the resources (files, documents, records…) that the MCP server makes available. Read them with " + readName + ".",
+			OutputSchema: tools.MustSchemaFor[[]ResourceInfo](),
+			Handler:      tools.NewHandler(ts.handleListResources),
+			Annotations: tools.ToolAnnotations{
+				ReadOnlyHint: true,
+				Title:        "List Resources",
+			},
+		},
+		{
+			Name:         readName,
+			Category:     "mcp",
+			Description:  "Read the content of a resource of the MCP server.",
+			Parameters:   tools.MustSchemaFor[ReadResourceArgs](),
+			OutputSchema: tools.MustSchemaFor[string](),
+			Handler:      tools.NewHandler(ts.handleReadResource),
+			Annotations: tools.ToolAnnotations{
+				ReadOnlyHint: true,
+				Title:        "Read Resource",
+			},
+		},
+	}
+}
+
+func (ts *Toolset) handleListResources(ctx context.Context, _ map[string]any) (*tools.ToolCallResult, error) {
+	resources, err := ts.ListResources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resources: %w", err)
+	}
+
+	result, err := json.Marshal(resources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resources: %w", err)
+	}
+
+	return tools.ResultSuccess(string(result)), nil
+}
+
+func (ts *Toolset) handleReadResource(ctx context.Context, args ReadResourceArgs) (*tools.ToolCallResult, error) {
+	content, err := ts.ReadResource(ctx, args.URI)
+	if err != nil {
+		return tools.ResultError(err.Error()), nil
+	}
+	return tools.ResultSuccess(content), nil
+}
+
+// resourceText joins the contents of a resource as text.
+func resourceText(result *mcp.ReadResourceResult) string {
+	var parts []string
+	for _, c := range result.Contents {
+		if c.Blob != nil {
+			parts = append(parts, fmt.Sprintf("[binary resource %s (%s, %s)]", c.URI, cmp.Or(c.MIMEType, "unknown type"), units.HumanSize(float64(len(c.Blob)))))
+			continue
+		}
+		parts = append(parts, c.Text)
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/docker-agent/pkg/tools"
+)
+
+func newResourcesToolset() *Toolset {
+	return &Toolset{
+		name:      "docs",
+		started:   true,
+		resources: &mcp.ResourceCapabilities{},
+		mcpClient: &mockMCPClient{
+			resources: []*mcp.Resource{
+				{URI: "docs://readme", Name: "readme", MIMEType: "text/markdown"},
+				{URI: "docs://logo", Name: "logo", MIMEType: "image/png", Size: 3},
+			},
+			readResourceFn: func(_ context.Context, request *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error) {
+				return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{
+					{URI: request.URI, Text: "# Readme"},
+				}}, nil
+			},
+		},
+	}
+}
+
+func TestListResources(t *testing.T) {
+	t.Parallel()
+
+	resources, err := newResourcesToolset().ListResources(t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, []ResourceInfo{
+		{URI: "docs://readme", Name: "readme", MIMEType: "text/markdown", Server: "docs"},
+		{URI: "docs://logo", Name: "logo", MIMEType: "image/png", Size: 3, Server: "docs"},
+	}, resources)
+}
+
+func TestListResources_NoResourcesCapability(t *testing.T) {
+	t.Parallel()
+
+	ts := newResourcesToolset()
+	ts.resources = nil
+
+	resources, err := ts.ListResources(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, resources)
+}
+
+func TestResourceTools(t *testing.T) {
+	t.Parallel()
+
+	ts := newResourcesToolset()
+
+	toolsList, err := ts.Tools(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, toolsList)
+
+	ts.SetResourceTools(true)
+	toolsList, err = ts.Tools(t.Context())
+	require.NoError(t, err)
+	require.Len(t, toolsList, 2)
+	assert.Equal(t, "docs_list_resources", toolsList[0].Name)
+	assert.Equal(t, "docs_read_resource", toolsList[1].Name)
+
+	result, err := toolsList[1].Handler(t.Context(), tools.ToolCall{
+		Function: tools.FunctionCall{Name: "docs_read_resource", Arguments: `{"uri":"docs://readme"}`},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "# Readme", result.Output)
+}
+
+func TestResourceText(t *testing.T) {
+	t.Parallel()
+
+	text := resourceText(&mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{
+		{URI: "docs://readme", Text: "# Readme"},
+		{URI: "docs://logo", MIMEType: "image/png", Blob: []byte("png")},
+	}})
+	assert.Equal(t, "# Readme\n\n[binary resource docs://logo (image/png, 3B)]", text)
+}
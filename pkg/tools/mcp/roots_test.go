@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolsetSetRoots(t *testing.T) {
+	t.Parallel()
+
+	client := &mockMCPClient{}
+	ts := &Toolset{mcpClient: client}
+
+	ts.SetRoots([]string{"/home/user/project", "/tmp/shared dir"})
+
+	assert.Equal(t, []*mcp.Root{
+		{URI: "file:///home/user/project", Name: "project"},
+		{URI: "file:///tmp/shared%20dir", Name: "shared dir"},
+	}, client.roots)
+}
+
+func TestSessionClientRoots(t *testing.T) {
+	t.Parallel()
+
+	changed := make(chan struct{}, 10)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, &mcp.ServerOptions{
+		RootsListChangedHandler: func(context.Context, *mcp.RootsListChangedRequest) {
+			changed <- struct{}{}
+		},
+	})
+
+	c := &sessionClient{}
+	c.SetRoots([]*mcp.Root{{URI: "file:///project", Name: "project"}})
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil)
+	c.setClient(client)
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = clientSession.Close() })
+
+	roots, err := serverSession.ListRoots(t.Context(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []*mcp.Root{{URI: "file:///project", Name: "project"}}, roots.Roots)
+
+	// Setting the same roots again is a no-op.
+	c.SetRoots([]*mcp.Root{{URI: "file:///project", Name: "project"}})
+	c.SetRoots([]*mcp.Root{{URI: "file:///other", Name: "other"}})
+	<-changed
+
+	roots, err = serverSession.ListRoots(t.Context(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []*mcp.Root{{URI: "file:///other", Name: "other"}}, roots.Roots)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"iter"
 	"log/slog"
+	"slices"
 	"sync"
 
 	gomcp "github.com/modelcontextprotocol/go-sdk/mcp"
@@ -18,12 +19,59 @@ import (
 // duplicating the session-nil guards, notification handlers, and delegating
 // methods.
 type sessionClient struct {
-	session                  *gomcp.ClientSession
-	toolListChangedHandler   func()
-	promptListChangedHandler func()
-	elicitationHandler       tools.ElicitationHandler
-	oauthSuccessHandler      func()
-	mu                       sync.RWMutex
+	client                     *gomcp.Client
+	session                    *gomcp.ClientSession
+	roots                      []*gomcp.Root
+	toolListChangedHandler     func()
+	promptListChangedHandler   func()
+	resourceListChangedHandler func()
+	resourceUpdatedHandler     func(uri string)
+	progressHandler            func(*gomcp.ProgressNotificationParams)
+	loggingHandler             func(*gomcp.LoggingMessageParams)
+	elicitationHandler         tools.ElicitationHandler
+	samplingHandler            samplingFunc
+	oauthSuccessHandler        func()
+	mu                         sync.RWMutex
+}
+
+// setClient stores the client of the next session under the write lock and
+// gives it the roots to advertise to the server.
+func (c *sessionClient) setClient(client *gomcp.Client) {
+	c.mu.Lock()
+	c.client = client
+	roots := c.roots
+	c.mu.Unlock()
+
+	client.AddRoots(roots...)
+}
+
+// SetRoots sets the roots advertised to the MCP server. When they change,
+// the client notifies the connected server with roots/list_changed.
+func (c *sessionClient) SetRoots(roots []*gomcp.Root) {
+	sameRoot := func(a, b *gomcp.Root) bool { return a.URI == b.URI && a.Name == b.Name }
+
+	c.mu.Lock()
+	if slices.EqualFunc(c.roots, roots, sameRoot) {
+		c.mu.Unlock()
+		return
+	}
+	previous := c.roots
+	c.roots = roots
+	client := c.client
+	c.mu.Unlock()
+
+	if client == nil {
+		return
+	}
+
+	var removed []string
+	for _, root := range previous {
+		if !slices.ContainsFunc(roots, func(r *gomcp.Root) bool { return r.URI == root.URI }) {
+			removed = append(removed, root.URI)
+		}
+	}
+	client.RemoveRoots(removed...)
+	client.AddRoots(roots...)
 }
 
 // setSession stores the session under the write lock.
@@ -78,6 +126,79 @@ func (c *sessionClient) SetPromptListChangedHandler(handler func()) {
 	c.mu.Unlock()
 }
 
+func (c *sessionClient) SetResourceListChangedHandler(handler func()) {
+	c.mu.Lock()
+	c.resourceListChangedHandler = handler
+	c.mu.Unlock()
+}
+
+func (c *sessionClient) SetResourceUpdatedHandler(handler func(uri string)) {
+	c.mu.Lock()
+	c.resourceUpdatedHandler = handler
+	c.mu.Unlock()
+}
+
+// handleResourceListChanged forwards ResourceListChanged notifications to the
+// registered handler. It is used as the gomcp ResourceListChangedHandler
+// callback for both stdio and remote clients.
+func (c *sessionClient) handleResourceListChanged(context.Context, *gomcp.ResourceListChangedRequest) {
+	c.mu.RLock()
+	h := c.resourceListChangedHandler
+	c.mu.RUnlock()
+	if h != nil {
+		h()
+	}
+}
+
+// handleResourceUpdated forwards ResourceUpdated notifications, sent for the
+// resources we subscribed to, to the registered handler. It is used as the
+// gomcp ResourceUpdatedHandler callback for both stdio and remote clients.
+func (c *sessionClient) handleResourceUpdated(_ context.Context, req *gomcp.ResourceUpdatedNotificationRequest) {
+	c.mu.RLock()
+	h := c.resourceUpdatedHandler
+	c.mu.RUnlock()
+	if h != nil && req.Params != nil {
+		h(req.Params.URI)
+	}
+}
+
+func (c *sessionClient) SetProgressNotificationHandler(handler func(*gomcp.ProgressNotificationParams)) {
+	c.mu.Lock()
+	c.progressHandler = handler
+	c.mu.Unlock()
+}
+
+func (c *sessionClient) SetLoggingMessageHandler(handler func(*gomcp.LoggingMessageParams)) {
+	c.mu.Lock()
+	c.loggingHandler = handler
+	c.mu.Unlock()
+}
+
+// handleProgressNotification forwards Progress notifications, sent for the
+// requests we gave a progress token to, to the registered handler. It is used
+// as the gomcp ProgressNotificationHandler callback for both stdio and remote
+// clients.
+func (c *sessionClient) handleProgressNotification(_ context.Context, req *gomcp.ProgressNotificationClientRequest) {
+	c.mu.RLock()
+	h := c.progressHandler
+	c.mu.RUnlock()
+	if h != nil && req.Params != nil {
+		h(req.Params)
+	}
+}
+
+// handleLoggingMessage forwards the log messages of the server to the
+// registered handler. It is used as the gomcp LoggingMessageHandler callback
+// for both stdio and remote clients.
+func (c *sessionClient) handleLoggingMessage(_ context.Context, req *gomcp.LoggingMessageRequest) {
+	c.mu.RLock()
+	h := c.loggingHandler
+	c.mu.RUnlock()
+	if h != nil && req.Params != nil {
+		h(req.Params)
+	}
+}
+
 func (c *sessionClient) Wait() error {
 	if s := c.getSession(); s != nil {
 		return s.Wait()
@@ -124,6 +245,43 @@ func (c *sessionClient) GetPrompt(ctx context.Context, request *gomcp.GetPromptP
 	return nil, errors.New("session not initialized")
 }
 
+func (c *sessionClient) ListResources(ctx context.Context, request *gomcp.ListResourcesParams) iter.Seq2[*gomcp.Resource, error] {
+	if s := c.getSession(); s != nil {
+		return s.Resources(ctx, request)
+	}
+	return func(yield func(*gomcp.Resource, error) bool) {
+		yield(nil, errors.New("session not initialized"))
+	}
+}
+
+func (c *sessionClient) ReadResource(ctx context.Context, request *gomcp.ReadResourceParams) (*gomcp.ReadResourceResult, error) {
+	if s := c.getSession(); s != nil {
+		return s.ReadResource(ctx, request)
+	}
+	return nil, errors.New("session not initialized")
+}
+
+func (c *sessionClient) Subscribe(ctx context.Context, request *gomcp.SubscribeParams) error {
+	if s := c.getSession(); s != nil {
+		return s.Subscribe(ctx, request)
+	}
+	return errors.New("session not initialized")
+}
+
+func (c *sessionClient) Unsubscribe(ctx context.Context, request *gomcp.UnsubscribeParams) error {
+	if s := c.getSession(); s != nil {
+		return s.Unsubscribe(ctx, request)
+	}
+	return errors.New("session not initialized")
+}
+
+func (c *sessionClient) SetLoggingLevel(ctx context.Context, request *gomcp.SetLoggingLevelParams) error {
+	if s := c.getSession(); s != nil {
+		return s.SetLoggingLevel(ctx, request)
+	}
+	return errors.New("session not initialized")
+}
+
 // handleElicitationRequest forwards incoming elicitation requests from the MCP
 // server to the registered handler. It is used as the gomcp ElicitationHandler
 // callback for both stdio and remote clients.
@@ -172,6 +330,40 @@ func (c *sessionClient) requestElicitation(ctx context.Context, req *gomcp.Elici
 	return handler(ctx, req)
 }
 
+// samplingFunc answers the sampling requests of the MCP server of a session.
+// The Toolset binds its name to a tools.SamplingHandler to build it.
+type samplingFunc func(ctx context.Context, req *gomcp.CreateMessageParams) (*gomcp.CreateMessageResult, error)
+
+// handleCreateMessageRequest forwards incoming sampling requests from the MCP
+// server to the registered handler. It is used as the gomcp
+// CreateMessageHandler callback for both stdio and remote clients.
+func (c *sessionClient) handleCreateMessageRequest(ctx context.Context, req *gomcp.CreateMessageRequest) (*gomcp.CreateMessageResult, error) {
+	slog.Debug("Received sampling request from MCP server", "messages", len(req.Params.Messages), "max_tokens", req.Params.MaxTokens)
+
+	c.mu.RLock()
+	handler := c.samplingHandler
+	c.mu.RUnlock()
+
+	if handler == nil {
+		return nil, errors.New("no sampling handler configured")
+	}
+
+	result, err := handler(ctx, req.Params)
+	if err != nil {
+		return nil, fmt.Errorf("sampling failed: %w", err)
+	}
+
+	return result, nil
+}
+
+// SetSamplingHandler sets the handler that answers sampling requests from the
+// MCP server.
+func (c *sessionClient) SetSamplingHandler(handler samplingFunc) {
+	c.mu.Lock()
+	c.samplingHandler = handler
+	c.mu.Unlock()
+}
+
 // SetOAuthSuccessHandler sets the handler called when an OAuth flow completes.
 func (c *sessionClient) SetOAuthSuccessHandler(handler func()) {
 	c.mu.Lock()
@@ -37,17 +37,23 @@ func (c *stdioMCPClient) Initialize(ctx context.Context, _ *gomcp.InitializeRequ
 
 	toolChanged, promptChanged := c.notificationHandlers()
 
-	// Create client options with elicitation and notification support
+	// Create client options with elicitation, sampling and notification support
 	opts := &gomcp.ClientOptions{
-		ElicitationHandler:       c.handleElicitationRequest,
-		ToolListChangedHandler:   toolChanged,
-		PromptListChangedHandler: promptChanged,
+		ElicitationHandler:          c.handleElicitationRequest,
+		CreateMessageHandler:        c.handleCreateMessageRequest,
+		ToolListChangedHandler:      toolChanged,
+		PromptListChangedHandler:    promptChanged,
+		ResourceListChangedHandler:  c.handleResourceListChanged,
+		ResourceUpdatedHandler:      c.handleResourceUpdated,
+		ProgressNotificationHandler: c.handleProgressNotification,
+		LoggingMessageHandler:       c.handleLoggingMessage,
 	}
 
 	client := gomcp.NewClient(&gomcp.Implementation{
 		Name:    "docker agent",
 		Version: "1.0.0",
 	}, opts)
+	c.setClient(client)
 
 	cmd := exec.CommandContext(ctx, c.command, c.args...)
 	cmd.Env = c.env
@@ -0,0 +1,30 @@
+package tools
+
+import "context"
+
+// ToolProgress is an update on a running tool call, like the progress
+// notifications of MCP servers. Total is 0 when it is unknown.
+type ToolProgress struct {
+	Progress float64
+	Total    float64
+	Message  string
+}
+
+// ProgressHandler is a function type that receives the progress updates of a
+// running tool call. This allows the runtime to show them while the tool runs.
+type ProgressHandler func(progress ToolProgress)
+
+type progressHandlerKey struct{}
+
+// WithProgressHandler returns a context in which the tool called with it can
+// report its progress to handler.
+func WithProgressHandler(ctx context.Context, handler ProgressHandler) context.Context {
+	return context.WithValue(ctx, progressHandlerKey{}, handler)
+}
+
+// ProgressHandlerFromContext returns the handler the progress of a tool call
+// is reported to, or nil if nobody listens.
+func ProgressHandlerFromContext(ctx context.Context) ProgressHandler {
+	handler, _ := ctx.Value(progressHandlerKey{}).(ProgressHandler)
+	return handler
+}
@@ -0,0 +1,12 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// SamplingHandler is a function type that handles sampling requests from an MCP server,
+// identified by the name of its toolset. This allows the runtime to answer them with
+// the agent's model.
+type SamplingHandler func(ctx context.Context, server string, req *mcp.CreateMessageParams) (*mcp.CreateMessageResult, error)
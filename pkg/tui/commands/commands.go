@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
@@ -203,6 +204,20 @@ func builtInSessionCommands() []Item {
 				return core.CmdHandler(messages.SetSessionTitleMsg{Title: arg})
 			},
 		},
+		{
+			ID:           "session.undo",
+			Label:        "Undo",
+			SlashCommand: "/undo",
+			Description:  "Restore the files changed by the last turn (usage: /undo [turn])",
+			Category:     "Session",
+			Execute: func(arg string) tea.Cmd {
+				turn, err := strconv.Atoi(strings.TrimSpace(arg))
+				if err != nil || turn < 1 {
+					turn = 0
+				}
+				return core.CmdHandler(messages.UndoTurnMsg{Turn: turn})
+			},
+		},
 		{
 			ID:           "session.yolo",
 			Label:        "Yolo",
@@ -31,6 +31,6 @@ type AsyncLoader interface {
 func Completions(a *app.App) []Completion {
 	return []Completion{
 		NewCommandCompletion(a),
-		NewFileCompletion(),
+		NewFileCompletion(a),
 	}
 }
@@ -1,12 +1,18 @@
 package completions
 
 import (
+	"cmp"
 	"context"
 	"slices"
 	"sync"
 
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/docker/docker-agent/pkg/app"
 	"github.com/docker/docker-agent/pkg/fsx"
 	"github.com/docker/docker-agent/pkg/tui/components/completion"
+	"github.com/docker/docker-agent/pkg/tui/core"
+	"github.com/docker/docker-agent/pkg/tui/messages"
 )
 
 // Initial loading limits for snappy UX
@@ -15,14 +21,19 @@ const (
 	initialMaxDepth = 2
 )
 
+// fileCompletion completes @ references to files and to the resources of the
+// agent's MCP servers.
 type fileCompletion struct {
+	app    *app.App
 	mu     sync.Mutex
 	items  []completion.Item
 	loaded bool
 }
 
-func NewFileCompletion() Completion {
-	return &fileCompletion{}
+func NewFileCompletion(a *app.App) Completion {
+	return &fileCompletion{
+		app: a,
+	}
 }
 
 func (c *fileCompletion) AutoSubmit() bool {
@@ -95,7 +106,7 @@ func (c *fileCompletion) LoadInitialItemsAsync(ctx context.Context) <-chan []com
 			items := c.items
 			c.mu.Unlock()
 			select {
-			case ch <- items:
+			case ch <- c.withResourceItems(ctx, items):
 			case <-ctx.Done():
 			}
 			return
@@ -137,7 +148,7 @@ func (c *fileCompletion) LoadInitialItemsAsync(ctx context.Context) <-chan []com
 
 		// Don't cache initial items - we'll cache full items later
 		select {
-		case ch <- items:
+		case ch <- c.withResourceItems(ctx, items):
 		case <-ctx.Done():
 		}
 	}()
@@ -159,7 +170,7 @@ func (c *fileCompletion) LoadItemsAsync(ctx context.Context) <-chan []completion
 			items := c.items
 			c.mu.Unlock()
 			select {
-			case ch <- items:
+			case ch <- c.withResourceItems(ctx, items):
 			case <-ctx.Done():
 			}
 			return
@@ -206,7 +217,7 @@ func (c *fileCompletion) LoadItemsAsync(ctx context.Context) <-chan []completion
 		c.mu.Unlock()
 
 		select {
-		case ch <- items:
+		case ch <- c.withResourceItems(ctx, items):
 		case <-ctx.Done():
 		}
 	}()
@@ -214,6 +225,27 @@ func (c *fileCompletion) LoadItemsAsync(ctx context.Context) <-chan []completion
 	return ch
 }
 
+// withResourceItems puts the MCP resources of the current agent before the
+// file items. Resources aren't cached, since servers can change them at any time.
+func (c *fileCompletion) withResourceItems(ctx context.Context, items []completion.Item) []completion.Item {
+	if c.app == nil {
+		return items
+	}
+
+	var resourceItems []completion.Item
+	for _, resource := range c.app.CurrentMCPResources(ctx) {
+		resourceItems = append(resourceItems, completion.Item{
+			Label:       cmp.Or(resource.Title, resource.Name),
+			Description: resource.URI,
+			Value:       "@" + resource.URI,
+			Execute: func() tea.Cmd {
+				return core.CmdHandler(messages.AttachResourceMsg{URI: resource.URI, Name: resource.Name})
+			},
+		})
+	}
+	return append(resourceItems, items...)
+}
+
 func (c *fileCompletion) MatchMode() completion.MatchMode {
 	return completion.MatchFuzzy
 }
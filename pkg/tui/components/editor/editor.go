@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -46,7 +47,8 @@ const (
 
 type attachment struct {
 	path        string // Path to file (temp for pastes, real for file refs)
-	placeholder string // @paste-1 or @filename
+	resourceURI string // URI of an MCP resource, read by the runtime
+	placeholder string // @paste-1, @filename or @uri
 	label       string // Display label like "paste-1 (21.1 KB)"
 	sizeBytes   int
 	isTemp      bool // True for paste temp files that need cleanup
@@ -74,6 +76,8 @@ type Editor interface {
 	InsertText(text string)
 	// AttachFile adds a file as an attachment and inserts @filepath into the editor
 	AttachFile(filePath string) error
+	// AttachResource adds an MCP resource as an attachment and inserts @uri into the editor
+	AttachResource(uri, name string)
 	Cleanup()
 	GetSize() (width, height int)
 	BannerHeight() int
@@ -1225,6 +1229,14 @@ func (e *editor) AttachmentAt(x int) (AttachmentPreview, bool) {
 			continue
 		}
 
+		if att.resourceURI != "" {
+			// The resource is only read when the message is sent.
+			return AttachmentPreview{
+				Title:   item.label,
+				Content: att.resourceURI,
+			}, true
+		}
+
 		data, err := os.ReadFile(att.path)
 		if err != nil {
 			slog.Warn("failed to read attachment preview", "path", att.path, "error", err)
@@ -1291,6 +1303,23 @@ func (e *editor) AttachFile(filePath string) error {
 	return nil
 }
 
+// AttachResource adds an MCP resource as an attachment and inserts @uri into the editor
+func (e *editor) AttachResource(uri, name string) {
+	placeholder := "@" + uri
+	if !slices.ContainsFunc(e.attachments, func(att attachment) bool { return att.placeholder == placeholder }) {
+		e.attachments = append(e.attachments, attachment{
+			resourceURI: uri,
+			placeholder: placeholder,
+			label:       name,
+		})
+	}
+	currentValue := e.textarea.Value()
+	e.textarea.SetValue(currentValue + placeholder + " ")
+	e.textarea.MoveToEnd()
+	e.userTyped = true
+	e.updateAttachmentBanner()
+}
+
 // tryAddFileRef checks if word is a valid @filepath and adds it as attachment.
 // Called when cursor leaves a word to detect manually-typed file references.
 func (e *editor) tryAddFileRef(word string) {
@@ -1376,7 +1405,14 @@ func (e *editor) collectAttachments(content string) []messages.Attachment {
 			continue
 		}
 
-		if att.isTemp {
+		switch {
+		case att.resourceURI != "":
+			// MCP resource attachment: read by the runtime at send time.
+			result = append(result, messages.Attachment{
+				Name:        att.label,
+				ResourceURI: att.resourceURI,
+			})
+		case att.isTemp:
 			// Paste attachment: read into memory and remove the temp file.
 			data, err := os.ReadFile(att.path)
 			_ = os.Remove(att.path)
@@ -1388,7 +1424,7 @@ func (e *editor) collectAttachments(content string) []messages.Attachment {
 				Name:    strings.TrimPrefix(att.placeholder, "@"),
 				Content: string(data),
 			})
-		} else {
+		default:
 			// File-reference attachment: keep the path for later processing.
 			result = append(result, messages.Attachment{
 				Name:     filepath.Base(att.path),
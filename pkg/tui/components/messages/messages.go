@@ -52,6 +52,7 @@ type Model interface {
 	AddWelcomeMessage(content string) tea.Cmd
 	AddOrUpdateToolCall(agentName string, toolCall tools.ToolCall, toolDef tools.Tool, status types.ToolStatus) tea.Cmd
 	AddToolResult(msg *runtime.ToolCallResponseEvent, status types.ToolStatus) tea.Cmd
+	UpdateToolProgress(toolCallID, progress string)
 	AppendToLastMessage(agentName, content string) tea.Cmd
 	AppendReasoning(agentName, content string) tea.Cmd
 	AddShellOutputMessage(content string) tea.Cmd
@@ -1359,6 +1360,26 @@ func (m *model) AddToolResult(msg *runtime.ToolCallResponseEvent, status types.T
 	return nil
 }
 
+// UpdateToolProgress shows the latest progress reported by a running tool call.
+func (m *model) UpdateToolProgress(toolCallID, progress string) {
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		switch msg := m.messages[i]; msg.Type {
+		case types.MessageTypeAssistantReasoningBlock:
+			if block, ok := m.views[i].(*reasoningblock.Model); ok && block.HasToolCall(toolCallID) {
+				block.UpdateToolProgress(toolCallID, progress)
+				m.invalidateItem(i)
+				return
+			}
+		case types.MessageTypeToolCall:
+			if msg.ToolCall.ID == toolCallID {
+				msg.ToolProgress = progress
+				m.invalidateItem(i)
+				return
+			}
+		}
+	}
+}
+
 func (m *model) AppendToLastMessage(agentName, content string) tea.Cmd {
 	m.removeSpinner()
 
@@ -213,6 +213,16 @@ func (m *Model) UpdateToolCall(toolCallID string, status types.ToolStatus, args
 	}
 }
 
+// UpdateToolProgress updates the progress reported by a running tool call.
+func (m *Model) UpdateToolProgress(toolCallID, progress string) {
+	for _, entry := range m.toolEntries {
+		if entry.msg.ToolCall.ID == toolCallID {
+			entry.msg.ToolProgress = progress
+			return
+		}
+	}
+}
+
 // UpdateToolResult updates tool result for a tool call.
 func (m *Model) UpdateToolResult(toolCallID, content string, status types.ToolStatus, result *tools.ToolCallResult) tea.Cmd {
 	for i, entry := range m.toolEntries {
@@ -20,6 +20,11 @@ func render(msg *types.Message, s spinner.Spinner, sessionState service.SessionS
 		argsContent = renderToolArgs(msg.ToolCall, width-4-len(msg.ToolDefinition.DisplayName()), width-3)
 	}
 
+	if msg.ToolStatus == types.ToolStatusRunning && msg.ToolProgress != "" {
+		// Long-running tools, e.g. MCP tools, can report their progress.
+		return toolcommon.RenderTool(msg, s, argsContent, msg.ToolProgress, width, sessionState.HideToolResults())
+	}
+
 	if argsContent == "" {
 		return toolcommon.RenderTool(msg, s, "", "", width, sessionState.HideToolResults())
 	}
@@ -28,5 +28,8 @@ func extractResult(msg *types.Message) string {
 	if meta.Error != "" {
 		return meta.Error
 	}
+	if meta.LineCount < meta.TotalLines {
+		return fmt.Sprintf("lines %d-%d of %d", meta.StartLine, meta.EndLine, meta.TotalLines)
+	}
 	return fmt.Sprintf("%d lines", meta.LineCount)
 }
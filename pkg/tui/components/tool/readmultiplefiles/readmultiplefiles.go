@@ -96,9 +96,12 @@ func formatSummaryLines(meta *builtin.ReadMultipleFilesMeta) []fileSummary {
 	for _, file := range meta.Files {
 		path := toolcommon.ShortenPath(file.Path)
 		var output string
-		if file.Error != "" {
+		switch {
+		case file.Error != "":
 			output = " " + file.Error
-		} else {
+		case file.LineCount < file.TotalLines:
+			output = fmt.Sprintf(" lines %d-%d of %d", file.StartLine, file.EndLine, file.TotalLines)
+		default:
 			output = fmt.Sprintf(" %d lines", file.LineCount)
 		}
 
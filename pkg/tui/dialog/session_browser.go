@@ -1,6 +1,7 @@
 package dialog
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -10,6 +11,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/atotto/clipboard"
+	"github.com/mattn/go-runewidth"
 
 	"github.com/docker/docker-agent/pkg/session"
 	"github.com/docker/docker-agent/pkg/tui/components/notification"
@@ -37,11 +39,34 @@ const (
 	sessionBrowserListStartY   = 6  // border(1) + padding(1) + title(1) + space(1) + input(1) + separator(1)
 )
 
+// sessionSearchDelay is how long the user must stop typing before the
+// session contents are searched.
+const sessionSearchDelay = 150 * time.Millisecond
+
+// SessionSearchFunc searches the titles and message contents of stored sessions.
+type SessionSearchFunc func(ctx context.Context, query string) ([]session.SearchResult, error)
+
+// sessionSearchTickMsg fires when the search delay for a query has elapsed.
+type sessionSearchTickMsg struct {
+	seq int
+}
+
+// sessionSearchResultsMsg carries the results of a content search.
+type sessionSearchResultsMsg struct {
+	seq     int
+	results []session.SearchResult
+	err     error
+}
+
 type sessionBrowserDialog struct {
 	BaseDialog
 	textInput  textinput.Model
 	sessions   []session.Summary
 	filtered   []session.Summary
+	search     SessionSearchFunc
+	searchSeq  int                    // incremented on each query change, to drop stale results
+	matches    []session.SearchResult // content matches for the current query
+	snippets   map[string]string      // session ID -> highlighted snippet
 	selected   int
 	scrollview *scrollview.Model
 	keyMap     sessionBrowserKeyMap
@@ -53,8 +78,10 @@ type sessionBrowserDialog struct {
 	lastClickIndex int
 }
 
-// NewSessionBrowserDialog creates a new session browser dialog
-func NewSessionBrowserDialog(sessions []session.Summary) Dialog {
+// NewSessionBrowserDialog creates a new session browser dialog.
+// When search is not nil, the message contents of the sessions are searched
+// as the user types, in addition to their titles.
+func NewSessionBrowserDialog(sessions []session.Summary, search SessionSearchFunc) Dialog {
 	ti := textinput.New()
 	ti.Placeholder = "Type to search sessions…"
 	ti.Focus()
@@ -72,6 +99,7 @@ func NewSessionBrowserDialog(sessions []session.Summary) Dialog {
 	d := &sessionBrowserDialog{
 		textInput:  ti,
 		sessions:   nonEmptySessions,
+		search:     search,
 		scrollview: scrollview.New(scrollview.WithReserveScrollbarSpace(true)),
 		keyMap: sessionBrowserKeyMap{
 			Up:         key.NewBinding(key.WithKeys("up", "ctrl+k")),
@@ -107,8 +135,24 @@ func (d *sessionBrowserDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 	case tea.PasteMsg:
 		var cmd tea.Cmd
 		d.textInput, cmd = d.textInput.Update(msg)
+		return d, tea.Batch(cmd, d.queryChanged())
+
+	case sessionSearchTickMsg:
+		if msg.seq != d.searchSeq {
+			return d, nil
+		}
+		return d, d.runSearch(msg.seq, d.textInput.Value())
+
+	case sessionSearchResultsMsg:
+		if msg.seq != d.searchSeq {
+			return d, nil
+		}
+		if msg.err != nil {
+			return d, notification.ErrorCmd(fmt.Sprintf("Failed to search sessions: %v", msg.err))
+		}
+		d.matches = msg.results
 		d.filterSessions()
-		return d, cmd
+		return d, nil
 
 	case tea.MouseClickMsg:
 		// Scrollbar clicks already handled above; this handles list item clicks
@@ -195,30 +239,65 @@ func (d *sessionBrowserDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 			return d, nil
 
 		default:
+			prev := d.textInput.Value()
 			var cmd tea.Cmd
 			d.textInput, cmd = d.textInput.Update(msg)
-			d.filterSessions()
-			return d, cmd
+			if d.textInput.Value() == prev {
+				return d, cmd
+			}
+			return d, tea.Batch(cmd, d.queryChanged())
 		}
 	}
 
 	return d, nil
 }
 
+// queryChanged filters the sessions by title right away and schedules a
+// search of their contents once the user stops typing.
+func (d *sessionBrowserDialog) queryChanged() tea.Cmd {
+	d.searchSeq++
+	d.matches = nil
+	d.filterSessions()
+
+	if d.search == nil || strings.TrimSpace(d.textInput.Value()) == "" {
+		return nil
+	}
+	seq := d.searchSeq
+	return tea.Tick(sessionSearchDelay, func(time.Time) tea.Msg {
+		return sessionSearchTickMsg{seq: seq}
+	})
+}
+
+func (d *sessionBrowserDialog) runSearch(seq int, query string) tea.Cmd {
+	search := d.search
+	return func() tea.Msg {
+		results, err := search(context.Background(), query)
+		return sessionSearchResultsMsg{seq: seq, results: results, err: err}
+	}
+}
+
 func (d *sessionBrowserDialog) filterSessions() {
 	query := strings.ToLower(strings.TrimSpace(d.textInput.Value()))
 
 	d.filtered = nil
-	for _, sess := range d.sessions {
+	d.snippets = make(map[string]string, len(d.matches))
+	seen := make(map[string]bool)
+	keep := func(sess session.Summary) bool {
+		if seen[sess.ID] {
+			return false
+		}
 		switch d.starFilter {
 		case 1:
-			if !sess.Starred {
-				continue
-			}
+			return sess.Starred
 		case 2:
-			if sess.Starred {
-				continue
-			}
+			return !sess.Starred
+		}
+		return true
+	}
+
+	for _, sess := range d.sessions {
+		if !keep(sess) {
+			continue
 		}
 
 		if query != "" {
@@ -231,6 +310,28 @@ func (d *sessionBrowserDialog) filterSessions() {
 			}
 		}
 
+		seen[sess.ID] = true
+		d.filtered = append(d.filtered, sess)
+	}
+
+	// Sessions whose messages match come after the title matches.
+	for _, match := range d.matches {
+		sess := match.Summary
+		// Prefer the local copy, which reflects stars toggled in the dialog.
+		for _, s := range d.sessions {
+			if s.ID == sess.ID {
+				sess = s
+				break
+			}
+		}
+		if seen[sess.ID] || !keep(sess) {
+			if seen[sess.ID] {
+				d.snippets[sess.ID] = match.Snippet
+			}
+			continue
+		}
+		seen[sess.ID] = true
+		d.snippets[sess.ID] = match.Snippet
 		d.filtered = append(d.filtered, sess)
 	}
 
@@ -272,7 +373,7 @@ func (d *sessionBrowserDialog) View() string {
 	// Build all session lines
 	var allLines []string
 	for i, sess := range d.filtered {
-		allLines = append(allLines, d.renderSession(sess, i == d.selected, contentWidth))
+		allLines = append(allLines, d.renderSession(sess, d.snippets[sess.ID], i == d.selected, contentWidth))
 	}
 
 	// Configure scrollview and let it handle slicing + rendering
@@ -348,7 +449,7 @@ func (d *sessionBrowserDialog) SetSize(width, height int) tea.Cmd {
 	return cmd
 }
 
-func (d *sessionBrowserDialog) renderSession(sess session.Summary, selected bool, maxWidth int) string {
+func (d *sessionBrowserDialog) renderSession(sess session.Summary, snippet string, selected bool, maxWidth int) string {
 	titleStyle, timeStyle := styles.PaletteUnselectedActionStyle, styles.PaletteUnselectedDescStyle
 	if selected {
 		titleStyle, timeStyle = styles.PaletteSelectedActionStyle, styles.PaletteSelectedDescStyle
@@ -363,11 +464,61 @@ func (d *sessionBrowserDialog) renderSession(sess session.Summary, selected bool
 
 	starWidth := 3
 	maxTitleLen := max(1, maxWidth-len(suffix)-starWidth)
+	// Leave room for the snippet of matching message contents.
+	if snippet != "" && !strings.EqualFold(stripSnippetMarks(snippet), title) {
+		maxTitleLen = max(1, min(maxTitleLen/2, len(title)))
+	} else {
+		snippet = ""
+	}
 	if len(title) > maxTitleLen {
 		title = title[:maxTitleLen-1] + "…"
 	}
 
-	return styles.StarIndicator(sess.Starred) + titleStyle.Render(title) + timeStyle.Render(suffix)
+	line := styles.StarIndicator(sess.Starred) + titleStyle.Render(title)
+	if snippet != "" {
+		snippetWidth := maxWidth - starWidth - len(title) - len(suffix) - 3
+		line += timeStyle.Render(" • ") + renderSnippet(snippet, snippetWidth, timeStyle, timeStyle.Bold(true).Foreground(styles.Highlight))
+	}
+	return line + timeStyle.Render(suffix)
+}
+
+// stripSnippetMarks removes the match markers from a search snippet.
+func stripSnippetMarks(snippet string) string {
+	return strings.NewReplacer(session.SnippetMatchStart, "", session.SnippetMatchEnd, "").Replace(snippet)
+}
+
+// renderSnippet renders a search snippet on at most width cells, with the
+// matched terms in matchStyle.
+func renderSnippet(snippet string, width int, baseStyle, matchStyle lipgloss.Style) string {
+	if width <= 1 {
+		return ""
+	}
+
+	var sb strings.Builder
+	remaining := width
+	write := func(text string, style lipgloss.Style) bool {
+		w := runewidth.StringWidth(text)
+		if w > remaining {
+			sb.WriteString(style.Render(runewidth.Truncate(text, remaining, "…")))
+			return false
+		}
+		remaining -= w
+		sb.WriteString(style.Render(text))
+		return true
+	}
+
+	for rest := snippet; rest != ""; {
+		before, after, found := strings.Cut(rest, session.SnippetMatchStart)
+		if !write(before, baseStyle) || !found {
+			break
+		}
+		match, tail, _ := strings.Cut(after, session.SnippetMatchEnd)
+		if !write(match, matchStyle) {
+			break
+		}
+		rest = tail
+	}
+	return sb.String()
 }
 
 func (d *sessionBrowserDialog) timeAgo(t time.Time) string {
@@ -1,6 +1,7 @@
 package dialog
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"testing"
@@ -20,7 +21,7 @@ func TestSessionBrowserNavigation(t *testing.T) {
 		{ID: "3", Title: "Session 3", CreatedAt: time.Now()},
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, nil)
 	d := dialog.(*sessionBrowserDialog)
 
 	// Initialize and set window size like the TUI does
@@ -68,7 +69,7 @@ func TestSessionBrowserNavigationWithCtrl(t *testing.T) {
 		{ID: "3", Title: "Session 3", CreatedAt: time.Now()},
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, nil)
 	d := dialog.(*sessionBrowserDialog)
 	d.Init()
 	d.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
@@ -103,7 +104,7 @@ func TestSessionBrowserViewShowsSelection(t *testing.T) {
 		{ID: "3", Title: "Session 3", CreatedAt: time.Now()},
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, nil)
 	d := dialog.(*sessionBrowserDialog)
 	d.Init()
 	d.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
@@ -133,7 +134,7 @@ func TestSessionBrowserFiltersEmptySessions(t *testing.T) {
 		{ID: "5", Title: "Session 5", CreatedAt: time.Now()},
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, nil)
 	d := dialog.(*sessionBrowserDialog)
 
 	// Should only have non-empty sessions
@@ -152,7 +153,7 @@ func TestSessionBrowserAllEmptySessions(t *testing.T) {
 		{ID: "2", Title: "", CreatedAt: time.Now()},
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, nil)
 	d := dialog.(*sessionBrowserDialog)
 
 	// Should have no sessions
@@ -171,7 +172,7 @@ func TestSessionBrowserScrolling(t *testing.T) {
 		}
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, nil)
 	d := dialog.(*sessionBrowserDialog)
 	d.Init()
 	// Set a small window size to force scrolling
@@ -211,7 +212,7 @@ func TestSessionBrowserMouseClickSelectsSession(t *testing.T) {
 		{ID: "3", Title: "Session 3", CreatedAt: time.Now()},
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, nil)
 	d := dialog.(*sessionBrowserDialog)
 	d.Init()
 	d.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
@@ -257,7 +258,7 @@ func TestSessionBrowserDoubleClickOpensSession(t *testing.T) {
 		{ID: "sess-3", Title: "Session 3", CreatedAt: time.Now()},
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, nil)
 	d := dialog.(*sessionBrowserDialog)
 	d.Init()
 	d.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
@@ -288,7 +289,7 @@ func TestSessionBrowserClickOutsideListIgnored(t *testing.T) {
 		{ID: "2", Title: "Session 2", CreatedAt: time.Now()},
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, nil)
 	d := dialog.(*sessionBrowserDialog)
 	d.Init()
 	d.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
@@ -306,3 +307,42 @@ func TestSessionBrowserClickOutsideListIgnored(t *testing.T) {
 	require.Equal(t, 0, d.selected, "click outside list should not change selection")
 	require.Nil(t, cmd, "click outside list should not produce a command")
 }
+
+func TestSessionBrowserSearchesContents(t *testing.T) {
+	sessions := []session.Summary{
+		{ID: "1", Title: "Fix migration", CreatedAt: time.Now()},
+		{ID: "2", Title: "Refactor", CreatedAt: time.Now()},
+		{ID: "3", Title: "Docs", CreatedAt: time.Now()},
+	}
+
+	var queries []string
+	search := func(_ context.Context, query string) ([]session.SearchResult, error) {
+		queries = append(queries, query)
+		return []session.SearchResult{
+			{Summary: sessions[1], Snippet: "move the <mark>migration</mark> code"},
+			{Summary: sessions[0], Snippet: "Fix <mark>migration</mark>"},
+		}, nil
+	}
+
+	d := NewSessionBrowserDialog(sessions, search).(*sessionBrowserDialog)
+	d.Init()
+	d.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
+
+	_, cmd := d.Update(tea.PasteMsg{Content: "migration"})
+	require.NotNil(t, cmd, "typing should schedule a content search")
+	require.Len(t, d.filtered, 1, "titles are filtered right away")
+
+	// A stale tick is ignored.
+	_, cmd = d.Update(sessionSearchTickMsg{seq: d.searchSeq - 1})
+	require.Nil(t, cmd)
+
+	_, cmd = d.Update(sessionSearchTickMsg{seq: d.searchSeq})
+	require.NotNil(t, cmd)
+	d.Update(cmd())
+	require.Equal(t, []string{"migration"}, queries)
+
+	require.Len(t, d.filtered, 2)
+	require.Equal(t, "1", d.filtered[0].ID, "title matches come first")
+	require.Equal(t, "2", d.filtered[1].ID)
+	require.Contains(t, d.View(), "move the")
+}
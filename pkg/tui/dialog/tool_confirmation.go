@@ -74,6 +74,9 @@ func (d *toolConfirmationDialog) SetSize(width, height int) tea.Cmd {
 
 	question := styles.DialogQuestionStyle.Width(contentWidth).Render("Do you want to allow this tool call?")
 	questionHeight := lipgloss.Height(question)
+	if reason := d.renderReason(contentWidth); reason != "" {
+		questionHeight += lipgloss.Height(reason) + 1
+	}
 
 	options := RenderHelpKeys(contentWidth, "Y", "yes", "N", "no", "T", d.alwaysAllowHelpText(), "A", "all tools")
 	optionsHeight := lipgloss.Height(options)
@@ -92,6 +95,15 @@ func (d *toolConfirmationDialog) renderSeparator(contentWidth int) string {
 	return RenderSeparator(contentWidth)
 }
 
+// renderReason renders why the tool call needs confirmation, or an empty
+// string when the runtime didn't give a reason.
+func (d *toolConfirmationDialog) renderReason(contentWidth int) string {
+	if d.msg.Reason == "" {
+		return ""
+	}
+	return styles.MutedStyle.Width(contentWidth).Render(d.msg.Reason)
+}
+
 // alwaysAllowHelpText returns a descriptive help text for the "always allow" option.
 // For shell commands, it shows the command pattern (e.g., "always allow ls*").
 // For other tools, it shows "always allow <toolname>".
@@ -335,6 +347,10 @@ func (d *toolConfirmationDialog) View() string {
 	question := styles.DialogQuestionStyle.Width(contentWidth).Render("Do you want to allow this tool call?")
 	options := RenderHelpKeys(contentWidth, "Y", "yes", "N", "no", "T", d.alwaysAllowHelpText(), "A", "all tools")
 
+	if reason := d.renderReason(contentWidth); reason != "" {
+		parts = append(parts, "", reason)
+	}
+
 	parts = append(parts, "", question, "", options)
 
 	content := lipgloss.JoinVertical(lipgloss.Left, parts...)
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/docker/docker-agent/pkg/app"
 	"github.com/docker/docker-agent/pkg/browser"
+	"github.com/docker/docker-agent/pkg/checkpoint"
 	"github.com/docker/docker-agent/pkg/evaluation"
 	"github.com/docker/docker-agent/pkg/modelsdev"
 	"github.com/docker/docker-agent/pkg/session"
@@ -170,6 +172,17 @@ func (m *appModel) handleExportSession(filename string) (tea.Model, tea.Cmd) {
 	return m, notification.SuccessCmd("Session exported to " + exportFile)
 }
 
+func (m *appModel) handleUndoTurn(turn int) (tea.Model, tea.Cmd) {
+	undone, restored, err := m.application.UndoTurn(context.Background(), turn)
+	switch {
+	case errors.Is(err, checkpoint.ErrNothingToRestore):
+		return m, notification.InfoCmd("No file changes to undo.")
+	case err != nil:
+		return m, notification.ErrorCmd(fmt.Sprintf("Failed to undo: %v", err))
+	}
+	return m, notification.SuccessCmd(fmt.Sprintf("Restored %d path(s) to their state before turn %d.", len(restored), undone))
+}
+
 func (m *appModel) handleCompactSession(additionalPrompt string) (tea.Model, tea.Cmd) {
 	return m, m.chatPage.CompactSession(additionalPrompt)
 }
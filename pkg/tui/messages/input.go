@@ -5,6 +5,9 @@ type (
 	// AttachFileMsg attaches a file directly or opens file picker if empty/directory.
 	AttachFileMsg struct{ FilePath string }
 
+	// AttachResourceMsg attaches a resource of an MCP server.
+	AttachResourceMsg struct{ URI, Name string }
+
 	// InsertFileRefMsg inserts @filepath reference into editor.
 	InsertFileRefMsg struct{ FilePath string }
 
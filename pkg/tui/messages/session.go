@@ -4,8 +4,8 @@ package messages
 import "github.com/docker/docker-agent/pkg/session"
 
 // Attachment represents content attached to a message. It is either a reference
-// to a file on disk (FilePath is set) or inline content already in memory
-// (Content is set, e.g. pasted text). When FilePath is set the consumer reads
+// to a file on disk (FilePath is set), a resource of an MCP server (ResourceURI
+// is set) or inline content already in memory (Content is set, e.g. pasted text). When FilePath is set the consumer reads
 // and classifies the file at send time; when only Content is set the consumer
 // uses it directly as inline text. This design lets us add binary-file support
 // (images, PDFs, …) in the future by extending the struct with a MimeType hint.
@@ -19,6 +19,9 @@ type Attachment struct {
 	// backing temp file is cleaned up before the message reaches the app layer.
 	// Empty for file-reference attachments that are read from disk.
 	Content string
+	// ResourceURI is the URI of an MCP resource, read through the runtime at
+	// send time.
+	ResourceURI string
 }
 
 // Session lifecycle messages control session state and persistence.
@@ -47,6 +50,9 @@ type (
 	// ExportSessionMsg exports the session to the specified file.
 	ExportSessionMsg struct{ Filename string }
 
+	// UndoTurnMsg restores the files changed since the start of a turn; 0 means the last turn.
+	UndoTurnMsg struct{ Turn int }
+
 	// OpenSessionBrowserMsg opens the session browser dialog.
 	OpenSessionBrowserMsg struct{}
 
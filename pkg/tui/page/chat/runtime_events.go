@@ -3,6 +3,7 @@ package chat
 import (
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
@@ -36,6 +37,7 @@ import (
 //   - PartialToolCallEvent      → Show tool call in progress
 //   - ToolCallEvent             → Tool execution started
 //   - ToolCallConfirmationEvent → Show confirmation dialog
+//   - ToolCallProgressEvent     → Show progress of running tool
 //   - ToolCallResponseEvent     → Show tool result
 //
 // Sidebar Updates (forwarded):
@@ -98,6 +100,12 @@ func (p *chatPage) handleRuntimeEvent(msg tea.Msg) (bool, tea.Cmd) {
 	case *runtime.ToolCallConfirmationEvent:
 		return true, p.handleToolCallConfirmation(msg)
 
+	case *runtime.ToolCallProgressEvent:
+		// Log messages can span several lines, only show the first one.
+		progress, _, _ := strings.Cut(msg.Text(), "\n")
+		p.messages.UpdateToolProgress(msg.ToolCallID, progress)
+		return true, nil
+
 	case *runtime.ToolCallResponseEvent:
 		return true, p.handleToolCallResponse(msg)
 
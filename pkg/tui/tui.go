@@ -787,6 +787,9 @@ func (m *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case messages.ExportSessionMsg:
 		return m.handleExportSession(msg.Filename)
 
+	case messages.UndoTurnMsg:
+		return m.handleUndoTurn(msg.Turn)
+
 	case messages.ToggleSessionStarMsg:
 		sessionID := msg.SessionID
 		if sessionID == "" {
@@ -873,6 +876,10 @@ func (m *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case messages.AttachFileMsg:
 		return m.handleAttachFile(msg.FilePath)
 
+	case messages.AttachResourceMsg:
+		m.editor.AttachResource(msg.URI, msg.Name)
+		return m, nil
+
 	case messages.SendAttachmentMsg:
 		m.application.RunWithMessage(context.Background(), nil, msg.Content)
 		return m, nil
@@ -993,7 +1000,7 @@ func (m *appModel) handleOpenSessionBrowser() (tea.Model, tea.Cmd) {
 	}
 
 	return m, core.CmdHandler(dialog.OpenDialogMsg{
-		Model: dialog.NewSessionBrowserDialog(sessions),
+		Model: dialog.NewSessionBrowserDialog(sessions, store.SearchSessions),
 	})
 }
 